@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2017-2023 F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// configFlag is the flag of the YAML file holding the controller options
+	configFlag = "config"
+	// envPrefix is the prefix of the environment variables holding the controller options
+	envPrefix = "CIS_"
+
+	// sources of the controller options, in the order of precedence
+	configSourceFlag    = "flag"
+	configSourceEnv     = "env"
+	configSourceFile    = "file"
+	configSourceDefault = "default"
+
+	maskedValue = "<redacted>"
+)
+
+var (
+	// configSources holds the source of the options which aren't defaulted
+	configSources map[string]string
+	// secretFlags are masked in the dump of the effective configuration
+	secretFlags = map[string]bool{
+		"cm-password":  true,
+		"cm-api-token": true,
+	}
+)
+
+// envName returns the environment variable of the flag, for example CIS_CM_URL for cm-url
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// loadConfig sets the flags not given on the command line from the CIS_ prefixed environment variables
+// and the YAML file of the --config flag, command line flags take precedence over the environment variables
+// which take precedence over the config file
+func loadConfig(fs *pflag.FlagSet, environ []string) error {
+	configSources = make(map[string]string)
+	fs.Visit(func(f *pflag.Flag) {
+		configSources[f.Name] = configSourceFlag
+	})
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if name, value, found := strings.Cut(kv, "="); found && strings.HasPrefix(name, envPrefix) {
+			env[name] = value
+		}
+	}
+	// the config file itself can be set with the environment variable
+	configFile := fs.Lookup(configFlag)
+	if configFile == nil {
+		return nil
+	}
+	if _, ok := configSources[configFlag]; !ok {
+		if value, ok := env[envName(configFlag)]; ok {
+			if err := fs.Set(configFlag, value); err != nil {
+				return fmt.Errorf("invalid value of %v: %v", envName(configFlag), err)
+			}
+			configSources[configFlag] = configSourceEnv
+		}
+	}
+	fileConfig, err := readConfigFile(configFile.Value.String())
+	if err != nil {
+		return err
+	}
+	for name := range fileConfig {
+		if name == configFlag || fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown option %v in the config file %v", name, configFile.Value.String())
+		}
+	}
+	var setErr error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if _, ok := configSources[f.Name]; ok || setErr != nil {
+			return
+		}
+		if value, ok := env[envName(f.Name)]; ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				setErr = fmt.Errorf("invalid value of %v: %v", envName(f.Name), err)
+				return
+			}
+			configSources[f.Name] = configSourceEnv
+		} else if value, ok := fileConfig[f.Name]; ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				setErr = fmt.Errorf("invalid value of %v in the config file: %v", f.Name, err)
+				return
+			}
+			configSources[f.Name] = configSourceFile
+		}
+	})
+	return setErr
+}
+
+// readConfigFile reads the options from the YAML file, the options are keyed by the flag names
+// and the underscores are accepted in place of the hyphens
+func readConfigFile(path string) (map[string]string, error) {
+	config := make(map[string]string)
+	if len(path) == 0 {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %v", err)
+	}
+	var options map[string]interface{}
+	if err = yaml.Unmarshal(data, &options); err != nil {
+		return nil, fmt.Errorf("failed to parse the config file %v: %v", path, err)
+	}
+	for key, value := range options {
+		name := strings.ReplaceAll(key, "_", "-")
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			config[name] = v
+		case bool:
+			config[name] = strconv.FormatBool(v)
+		case float64:
+			config[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("invalid value of %v in the config file %v: %v", key, path, value)
+		}
+	}
+	return config, nil
+}
+
+// logEffectiveConfig logs the options of the controller with their source, secrets are masked
+func logEffectiveConfig(fs *pflag.FlagSet) {
+	var options []string
+	fs.VisitAll(func(f *pflag.Flag) {
+		source, ok := configSources[f.Name]
+		if !ok {
+			source = configSourceDefault
+		}
+		value := f.Value.String()
+		if secretFlags[f.Name] && len(value) > 0 {
+			value = maskedValue
+		}
+		options = append(options, fmt.Sprintf("%v=%v (%v)", f.Name, value, source))
+	})
+	log.Infof("[INIT] Effective configuration: %v", strings.Join(options, ", "))
+}
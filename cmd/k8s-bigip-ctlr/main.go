@@ -23,6 +23,8 @@ import (
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/controller"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -59,6 +61,9 @@ const (
 	versionPathOpenshiftv3 = "/version/openshift"
 	versionPathOpenshiftv4 = "/apis/config.openshift.io/v1/clusterversions/version"
 	versionPathk8s         = "/version"
+
+	logFormatText = "text"
+	logFormatJSON = "json"
 )
 
 var (
@@ -75,6 +80,7 @@ var (
 
 	logLevel        *string
 	logFile         *string
+	logFormat       *string
 	printVersion    *bool
 	disableTeems    *bool
 	useNodeInternal *bool
@@ -86,6 +92,11 @@ var (
 	cmURL         *string
 	cmUsername    *string
 	cmPassword    *string
+	cmAuthType    *string
+	cmClientCert  *string
+	cmClientKey   *string
+	cmAPIToken    *string
+	cmTokenFile   *string
 	credsDir      *string
 	sslInsecure   *bool
 	ipam          *bool
@@ -95,6 +106,21 @@ var (
 
 	CISConfigCR *string
 	httpAddress *string
+	adminToken  *string
+
+	tracingExporter    *string
+	tracingEndpoint    *string
+	tracingFile        *string
+	tracingSampleRatio *float64
+
+	leaderElect               *bool
+	leaderElectLeaseName      *string
+	leaderElectLeaseNamespace *string
+	leaderElectLeaseDuration  *time.Duration
+	leaderElectRenewDeadline  *time.Duration
+	leaderElectRetryPeriod    *time.Duration
+
+	shutdownTimeout *time.Duration
 
 	// package variables
 	clientSets       controller.ClientSets
@@ -121,10 +147,17 @@ func _init() {
 	}
 
 	// Global flags
+	globalFlags.String(configFlag, "",
+		"Optional, filepath of the YAML file holding the options keyed by the flag names. The options are also "+
+			"read from the CIS_ prefixed environment variables, for example CIS_LOG_LEVEL for --log-level. "+
+			"Command line flags take precedence over the environment variables, which take precedence over the file.")
 	logLevel = globalFlags.String("log-level", "INFO",
 		"Optional, logging level")
 	logFile = globalFlags.String("log-file", "",
 		"Optional, filepath to store the CIS logs")
+	logFormat = globalFlags.String("log-format", logFormatText,
+		"Optional, format of the CIS logs: text or json. JSON logs carry the request id, tenant, BIG-IP "+
+			"and resource as fields.")
 	printVersion = globalFlags.Bool("version", false,
 		"Optional, print version and exit.")
 	tmpTrue := true
@@ -136,7 +169,36 @@ func _init() {
 	CISConfigCR = globalFlags.String("deploy-config-cr", "",
 		"Required, specify a CRD that holds additional spec for controller.")
 	httpAddress = globalFlags.String("http-listen-address", "0.0.0.0:8080",
-		"Optional, address to serve http based informations (/metrics and /health).")
+		"Optional, address to serve http based informations (/metrics, /health, /livez and /readyz).")
+	adminToken = globalFlags.String("admin-token-file", "",
+		"Optional, filepath of the bearer token authenticating the requests to the /admin/log-config endpoint, "+
+			"which changes the log level and AS3 debug logging at runtime, and to the read-only /debug/ endpoints. "+
+			"The endpoints are disabled when not set.")
+	tracingExporter = globalFlags.String("tracing-exporter", "",
+		"Optional, exporter of the OpenTelemetry traces of the reconcile and post pipeline: otlp or file. "+
+			"Tracing is disabled when not set.")
+	tracingEndpoint = globalFlags.String("tracing-endpoint", tracing.DefaultOTLPEndpoint,
+		"Optional, traces URL of the OTLP/HTTP collector for the otlp tracing exporter.")
+	tracingFile = globalFlags.String("tracing-file", "",
+		"Optional, filepath to write the traces in OTLP JSON lines format for the file tracing exporter.")
+	tracingSampleRatio = globalFlags.Float64("tracing-sample-ratio", 1,
+		"Optional, ratio of the traces to sample, between 0 and 1.")
+	leaderElect = globalFlags.Bool("leader-elect", false,
+		"Optional, enable the Lease based leader election to run multiple CIS replicas. Only the leader posts "+
+			"to the CentralManager and writes the status, the standby replicas keep their caches warm to take over.")
+	leaderElectLeaseName = globalFlags.String("leader-elect-lease-name", "k8s-bigip-ctlr",
+		"Optional, name of the lease of the leader election.")
+	leaderElectLeaseNamespace = globalFlags.String("leader-elect-lease-namespace", "",
+		"Optional, namespace of the lease of the leader election, defaults to the namespace of the deploy-config-cr.")
+	leaderElectLeaseDuration = globalFlags.Duration("leader-elect-lease-duration", 15*time.Second,
+		"Optional, duration that the standby replicas wait before taking over the lease of the leader.")
+	leaderElectRenewDeadline = globalFlags.Duration("leader-elect-renew-deadline", 10*time.Second,
+		"Optional, duration that the leader retries renewing the lease before giving up the leadership.")
+	leaderElectRetryPeriod = globalFlags.Duration("leader-elect-retry-period", 2*time.Second,
+		"Optional, duration between the attempts of acquiring or renewing the lease.")
+	shutdownTimeout = globalFlags.Duration("shutdown-timeout", 25*time.Second,
+		"Optional, duration to process the queued resources, post the pending declarations and update their status "+
+			"on shutdown. Should be less than the termination grace period of the pod.")
 	globalFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  Global:\n%s\n", globalFlags.FlagUsagesWrapped(width))
 	}
@@ -148,9 +210,25 @@ func _init() {
 		"Required, user name for the CentralManager user account.")
 	cmPassword = cmIPFlags.String("cm-password", "",
 		"Required, password for the CentralManager user account.")
+	cmAuthType = cmIPFlags.String("cm-auth-type", "",
+		"Optional, authentication method for the CentralManager: basic, client-cert, api-token or token-file. "+
+			"When not set, it is derived from the provided credentials, defaults to basic.")
+	cmClientCert = cmIPFlags.String("cm-client-cert", "",
+		"Optional, path to the PEM encoded client certificate used for client-cert (mTLS) authentication.")
+	cmClientKey = cmIPFlags.String("cm-client-key", "",
+		"Optional, path to the PEM encoded client key used for client-cert (mTLS) authentication.")
+	cmAPIToken = cmIPFlags.String("cm-api-token", "",
+		"Optional, long-lived API token used for api-token authentication. Deprecated as the token is visible "+
+			"in the process list, use cm-token-file instead.")
+	_ = cmIPFlags.MarkDeprecated("cm-api-token",
+		"the token is visible in the process list, use --cm-token-file or the token file of --credentials-directory instead")
+	cmTokenFile = cmIPFlags.String("cm-token-file", "",
+		"Optional, path to the file that contains the token used for token-file authentication. "+
+			"The file is re-read whenever it changes.")
 	credsDir = cmIPFlags.String("credentials-directory", "",
-		"Optional, directory that contains the CentralManager username, password, and/or "+
-			"url files. To be used instead of username, password, and/or url arguments.")
+		"Optional, directory that contains the CentralManager username, password, url, token "+
+			"and/or tls.crt, tls.key files. To be used instead of username, password, url, token "+
+			"and/or client certificate arguments.")
 	sslInsecure = cmIPFlags.Bool("no-verify-ssl", false,
 		"Optional, when set to true, enable insecure SSL communication to CentralManager.")
 	trustedCertsCfgmap = cmIPFlags.String("trusted-certs-cfgmap", "",
@@ -195,12 +273,23 @@ func _init() {
 	}
 }
 
-func initLogger(logLevel, logFile string) error {
+func initLogger(logLevel, logFile, logFormat string) error {
 	var logger log.Logger
-	if len(logFile) > 0 {
-		logger = log.NewFileLogger(logFile)
-	} else {
-		logger = log.NewConsoleLoggerExt("", log.Ldate|log.Ltime|log.Lmicroseconds)
+	switch strings.ToLower(logFormat) {
+	case logFormatText:
+		if len(logFile) > 0 {
+			logger = log.NewFileLogger(logFile)
+		} else {
+			logger = log.NewConsoleLoggerExt("", log.Ldate|log.Ltime|log.Lmicroseconds)
+		}
+	case logFormatJSON:
+		if len(logFile) > 0 {
+			logger = log.NewJSONFileLogger(logFile)
+		} else {
+			logger = log.NewJSONLogger(os.Stdout)
+		}
+	default:
+		return fmt.Errorf("'%v' is not a valid log format, allowed values are: %s/%s", logFormat, logFormatText, logFormatJSON)
 	}
 	log.RegisterLogger(
 		log.LL_MIN_LEVEL, log.LL_MAX_LEVEL, logger)
@@ -221,13 +310,17 @@ func init() {
 
 func verifyArgs() error {
 	*logLevel = strings.ToUpper(*logLevel)
-	logErr := initLogger(*logLevel, *logFile)
+	logErr := initLogger(*logLevel, *logFile, *logFormat)
 	if nil != logErr {
 		return logErr
 	}
-	if (len(*cmURL) == 0 || len(*cmUsername) == 0 ||
-		len(*cmPassword) == 0) && len(*credsDir) == 0 {
-		return fmt.Errorf("Missing CM credentials info")
+	if len(*credsDir) == 0 {
+		if len(*cmURL) == 0 {
+			return fmt.Errorf("Missing CM credentials info")
+		}
+		if err := verifyCMAuthArgs(); err != nil {
+			return err
+		}
 	}
 
 	if len(*CISConfigCR) == 0 {
@@ -239,6 +332,18 @@ func verifyArgs() error {
 		}
 	}
 
+	if err := getTracingConfig().Validate(); err != nil {
+		return err
+	}
+
+	if err := verifyLeaderElectionArgs(); err != nil {
+		return err
+	}
+
+	if *shutdownTimeout < 0 {
+		return fmt.Errorf("--shutdown-timeout should not be negative")
+	}
+
 	if *multiClusterMode != "standalone" && *multiClusterMode != "primary" && *multiClusterMode != "secondary" && *multiClusterMode != "" {
 		return fmt.Errorf("'%v' is not a valid multi cluster mode, allowed values are: standalone/primary/secondary", *multiClusterMode)
 	} else if *multiClusterMode != "" {
@@ -248,19 +353,127 @@ func verifyArgs() error {
 	return nil
 }
 
+// getTracingConfig returns the tracing configuration from the CLI args
+func getTracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter:       strings.ToLower(*tracingExporter),
+		Endpoint:       *tracingEndpoint,
+		FilePath:       *tracingFile,
+		SampleRatio:    *tracingSampleRatio,
+		ServiceVersion: version,
+	}
+}
+
+// verifyLeaderElectionArgs verifies the lease durations of the leader election
+func verifyLeaderElectionArgs() error {
+	if !*leaderElect {
+		return nil
+	}
+	if len(*leaderElectLeaseName) == 0 {
+		return fmt.Errorf("Missing required argument --leader-elect-lease-name")
+	}
+	if *leaderElectRetryPeriod <= 0 {
+		return fmt.Errorf("--leader-elect-retry-period should be positive")
+	}
+	if *leaderElectRenewDeadline <= *leaderElectRetryPeriod {
+		return fmt.Errorf("--leader-elect-renew-deadline should be greater than --leader-elect-retry-period")
+	}
+	if *leaderElectLeaseDuration <= *leaderElectRenewDeadline {
+		return fmt.Errorf("--leader-elect-lease-duration should be greater than --leader-elect-renew-deadline")
+	}
+	return nil
+}
+
+// getLeaderElectionConfig returns the leader election configuration from the CLI args, the pod name is
+// used as the identity of the replica
+func getLeaderElectionConfig() (*controller.LeaderElectionConfig, error) {
+	if !*leaderElect {
+		return nil, nil
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the identity of the replica: %v", err)
+	}
+	namespace := *leaderElectLeaseNamespace
+	if len(namespace) == 0 {
+		namespace = strings.Split(*CISConfigCR, "/")[0]
+	}
+	return &controller.LeaderElectionConfig{
+		LeaseName:      *leaderElectLeaseName,
+		LeaseNamespace: namespace,
+		Identity:       identity,
+		LeaseDuration:  *leaderElectLeaseDuration,
+		RenewDeadline:  *leaderElectRenewDeadline,
+		RetryPeriod:    *leaderElectRetryPeriod,
+	}, nil
+}
+
+// getCMAuthType returns the CentralManager authentication type, when not set explicitly
+// it's derived from the provided credentials
+func getCMAuthType() string {
+	if len(*cmAuthType) > 0 {
+		return *cmAuthType
+	}
+	switch {
+	case len(*cmClientCert) > 0 || len(*cmClientKey) > 0:
+		return tokenmanager.AuthClientCert
+	case len(*cmAPIToken) > 0:
+		return tokenmanager.AuthAPIToken
+	case len(*cmTokenFile) > 0:
+		return tokenmanager.AuthTokenFile
+	}
+	return tokenmanager.AuthBasic
+}
+
+// verifyCMAuthArgs verifies the credentials required by the CentralManager authentication type are provided
+func verifyCMAuthArgs() error {
+	switch getCMAuthType() {
+	case tokenmanager.AuthBasic:
+		if len(*cmUsername) == 0 || len(*cmPassword) == 0 {
+			return fmt.Errorf("Missing CM credentials info")
+		}
+	case tokenmanager.AuthClientCert:
+		if len(*cmClientCert) == 0 || len(*cmClientKey) == 0 {
+			return fmt.Errorf("Missing CM client certificate or key for %s authentication", tokenmanager.AuthClientCert)
+		}
+	case tokenmanager.AuthAPIToken:
+		if len(*cmAPIToken) == 0 {
+			return fmt.Errorf("Missing CM api token for %s authentication", tokenmanager.AuthAPIToken)
+		}
+	case tokenmanager.AuthTokenFile:
+		if len(*cmTokenFile) == 0 {
+			return fmt.Errorf("Missing CM token file for %s authentication", tokenmanager.AuthTokenFile)
+		}
+	default:
+		return fmt.Errorf("'%v' is not a valid CM authentication type, allowed values are: %s/%s/%s/%s", *cmAuthType,
+			tokenmanager.AuthBasic, tokenmanager.AuthClientCert, tokenmanager.AuthAPIToken, tokenmanager.AuthTokenFile)
+	}
+	return nil
+}
+
 func getCredentials() error {
 	if len(*credsDir) > 0 {
-		var usr, pass, cmCredURL string
+		var usr, pass, cmCredURL, token, clientCert, clientKey string
 		var err error
-		if strings.HasSuffix(*credsDir, "/") {
-			usr = *credsDir + "username"
-			pass = *credsDir + "password"
-			cmCredURL = *credsDir + "url"
-		} else {
-			usr = *credsDir + "/username"
-			pass = *credsDir + "/password"
-			cmCredURL = *credsDir + "/url"
+		dir := strings.TrimSuffix(*credsDir, "/")
+		usr = dir + "/username"
+		pass = dir + "/password"
+		cmCredURL = dir + "/url"
+		token = dir + "/token"
+		clientCert = dir + "/tls.crt"
+		clientKey = dir + "/tls.key"
+
+		// token and client certificate files are referenced by path so that they can be re-read on change
+		setFilePath := func(field *string, filename string) {
+			if len(*field) == 0 {
+				if _, statErr := os.Stat(filename); statErr == nil {
+					*field = filename
+				}
+			}
 		}
+		setFilePath(cmClientCert, clientCert)
+		setFilePath(cmClientKey, clientKey)
+		setFilePath(cmTokenFile, token)
 
 		setField := func(field *string, filename, fieldType string) error {
 			fileBytes, readErr := os.ReadFile(filename)
@@ -276,15 +489,21 @@ func getCredentials() error {
 			return nil
 		}
 
-		err = setField(cmUsername, usr, "username")
-		if err != nil {
-			return err
+		if getCMAuthType() == tokenmanager.AuthBasic {
+			err = setField(cmUsername, usr, "username")
+			if err != nil {
+				return err
+			}
+			err = setField(cmPassword, pass, "password")
+			if err != nil {
+				return err
+			}
 		}
-		err = setField(cmPassword, pass, "password")
+		err = setField(cmURL, cmCredURL, "url")
 		if err != nil {
 			return err
 		}
-		err = setField(cmURL, cmCredURL, "url")
+		err = verifyCMAuthArgs()
 		if err != nil {
 			return err
 		}
@@ -322,6 +541,11 @@ func run(args []string, parseFunc func([]string) error) error {
 	if err != nil {
 		return err
 	}
+	err = loadConfig(flags, os.Environ())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return err
+	}
 
 	if *printVersion {
 		fmt.Printf("Version: %s\nBuild: %s\n", version, buildInfo)
@@ -342,6 +566,13 @@ func run(args []string, parseFunc func([]string) error) error {
 	}
 
 	log.Infof("[INIT] Starting: Container Ingress Services - Version: %s, BuildInfo: %s", version, buildInfo)
+	logEffectiveConfig(flags)
+
+	shutdownTracing, err := tracing.Init(getTracingConfig())
+	if err != nil {
+		log.Errorf("[INIT] error initializing tracing: %v", err)
+		return err
+	}
 
 	config, err := getKubeConfig(rest.InClusterConfig)
 	if err != nil {
@@ -354,16 +585,28 @@ func run(args []string, parseFunc func([]string) error) error {
 		log.Errorf("[INIT] error connecting to the client: %v", err)
 		return err
 	}
+	leaderElection, err := getLeaderElectionConfig()
+	if err != nil {
+		log.Errorf("[INIT] error getting the leader election config: %v", err)
+		return err
+	}
 	{ // +gocover:ignore:block ignore coverage
 		userAgentInfo = getUserAgentInfo(clientSets.KubeClient.Discovery().RESTClient())
-		ctlr := initController(config)
+		ctlr := initController(config, leaderElection)
 
 		// TODO initialize and add support for teems data
 		initTeems(ctlr)
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 		sig := <-sigs
+		log.Infof("[SHUTDOWN] Received signal %v", sig)
 		ctlr.Stop()
+		// flush the pending spans before exiting
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(ctx); err != nil {
+			log.Errorf("[TRACING] error flushing the traces: %v", err)
+		}
+		cancel()
 		log.Debugf("Exiting - signal %v\n", sig)
 		return nil
 	}
@@ -404,6 +647,7 @@ func initClientSets(
 
 func initController(
 	config *rest.Config,
+	leaderElection *controller.LeaderElectionConfig,
 ) *controller.Controller { //+gocover:ignore:block run controller function can not be run in unit tests
 	ctlr := controller.RunController(
 		controller.Params{
@@ -411,14 +655,24 @@ func initController(
 			ClientSets: &clientSets,
 			UserAgent:  userAgentInfo,
 			CMConfigDetails: &controller.CMConfig{
-				URL:      *cmURL,
-				UserName: *cmUsername,
-				Password: *cmPassword,
+				URL:            *cmURL,
+				UserName:       *cmUsername,
+				Password:       *cmPassword,
+				AuthType:       getCMAuthType(),
+				ClientCertFile: *cmClientCert,
+				ClientKeyFile:  *cmClientKey,
+				APIToken:       *cmAPIToken,
+				TokenFile:      *cmTokenFile,
+				CredentialsDir: *credsDir,
 			},
 			CMTrustedCerts:        getBIGIPTrustedCerts(),
+			CMTrustedCertsCfgMap:  *trustedCertsCfgmap,
 			CMSSLInsecure:         *sslInsecure,
 			CISConfigCRKey:        *CISConfigCR,
 			HttpAddress:           *httpAddress,
+			AdminTokenFile:        *adminToken,
+			LeaderElection:        leaderElection,
+			ShutdownTimeout:       *shutdownTimeout,
 			ManageCustomResources: *manageCustomResources,
 			UseNodeInternal:       *useNodeInternal,
 			MultiClusterMode:      *multiClusterMode,
@@ -533,12 +787,8 @@ func getBIGIPTrustedCerts() string {
 			namespaceCfgmapSlice[1], namespaceCfgmapSlice[0], err)
 	}
 
-	var certs string
 	// Fetch all certificates from configmap
-	for _, v := range cm.Data {
-		certs += v + "\n"
-	}
-	return certs
+	return tokenmanager.TrustedCertsFromConfigMapData(cm.Data)
 }
 
 // getConfigMapUsingNamespaceAndName fetches and returns the configMap
@@ -24,6 +24,9 @@ import (
 	"fmt"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/controller"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
@@ -37,7 +40,9 @@ import (
 	restFake "k8s.io/client-go/rest/fake"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 var _ = Describe("Main Tests", func() {
@@ -241,6 +246,271 @@ var _ = Describe("Main Tests", func() {
 			Expect(err).ToNot(BeNil(), "cm-url should fail with invalid path.")
 		})
 
+		It("verifies CM authentication types", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).To(BeNil())
+			Expect(getCMAuthType()).To(Equal(tokenmanager.AuthAPIToken))
+			Expect(flags.Lookup("cm-api-token").Deprecated).ToNot(BeEmpty(), "api token flag should be deprecated")
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-auth-type=client-cert",
+				"--cm-client-cert=/tmp/tls.crt",
+				"--deploy-config-cr=default/testcr",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil(), "client key is required for client-cert authentication")
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-auth-type=invalid",
+				"--deploy-config-cr=default/testcr",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil())
+		})
+
+		It("verifies tracing arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+				"--tracing-exporter=OTLP",
+				"--tracing-sample-ratio=0.5",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).To(BeNil())
+			Expect(getTracingConfig().Exporter).To(Equal(tracing.ExporterOTLP))
+			Expect(getTracingConfig().Endpoint).To(Equal(tracing.DefaultOTLPEndpoint))
+			Expect(getTracingConfig().SampleRatio).To(Equal(0.5))
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+				"--tracing-exporter=file",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil(), "tracing file is required for file exporter")
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+				"--tracing-exporter=jaeger",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil())
+		})
+		It("verifies leader election arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).To(BeNil())
+			leaderElection, err := getLeaderElectionConfig()
+			Expect(err).To(BeNil())
+			Expect(leaderElection).To(BeNil(), "leader election is disabled by default")
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+				"--leader-elect=true",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).To(BeNil())
+			leaderElection, err = getLeaderElectionConfig()
+			Expect(err).To(BeNil())
+			hostname, _ := os.Hostname()
+			Expect(*leaderElection).To(Equal(controller.LeaderElectionConfig{
+				LeaseName:      "k8s-bigip-ctlr",
+				LeaseNamespace: "default",
+				Identity:       hostname,
+				LeaseDuration:  15 * time.Second,
+				RenewDeadline:  10 * time.Second,
+				RetryPeriod:    2 * time.Second,
+			}))
+
+			_init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+				"--leader-elect=true",
+				"--leader-elect-lease-duration=5s",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil(), "lease duration should be greater than the renew deadline")
+		})
+		It("verifies shutdown timeout argument", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cm.example.com",
+				"--cm-api-token=token",
+				"--deploy-config-cr=default/testcr",
+			}
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).To(BeNil())
+			Expect(*shutdownTimeout).To(Equal(25 * time.Second))
+
+			_init()
+			os.Args = append(os.Args, "--shutdown-timeout=-1s")
+			flags.Parse(os.Args)
+			Expect(verifyArgs()).ToNot(BeNil(), "shutdown timeout should not be negative")
+		})
+		It("reads options from the config file and environment variables", func() {
+			defer _init()
+			stdout, stderr := os.Stdout, os.Stderr
+			defer func() {
+				os.Stdout, os.Stderr = stdout, stderr
+				_ = initLogger("INFO", "", logFormatText)
+			}()
+			dir := GinkgoT().TempDir()
+			configPath := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(configPath, []byte(`
+cm_url: file.example.com
+cm-username: admin
+cm-password: secret
+log-level: DEBUG
+leader-elect: true
+tracing-sample-ratio: 0.5
+`), 0600)).To(BeNil())
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-url=cli.example.com",
+			}
+			flags.Parse(os.Args)
+			Expect(loadConfig(flags, []string{
+				"CIS_CONFIG=" + configPath,
+				"CIS_LOG_LEVEL=WARNING",
+				"CIS_DEPLOY_CONFIG_CR=default/testcr",
+				"OTHER_LOG_LEVEL=ERROR",
+			})).To(BeNil())
+			Expect(*cmURL).To(Equal("cli.example.com"), "command line flags take precedence")
+			Expect(*logLevel).To(Equal("WARNING"), "environment variables take precedence over the file")
+			Expect(*CISConfigCR).To(Equal("default/testcr"))
+			Expect(*cmUsername).To(Equal("admin"))
+			Expect(*cmPassword).To(Equal("secret"))
+			Expect(*leaderElect).To(BeTrue())
+			Expect(*tracingSampleRatio).To(Equal(0.5))
+			Expect(configSources).To(Equal(map[string]string{
+				"cm-url":               configSourceFlag,
+				"config":               configSourceEnv,
+				"log-level":            configSourceEnv,
+				"deploy-config-cr":     configSourceEnv,
+				"cm-username":          configSourceFile,
+				"cm-password":          configSourceFile,
+				"leader-elect":         configSourceFile,
+				"tracing-sample-ratio": configSourceFile,
+			}))
+
+			// secrets are masked in the effective configuration
+			logPath := filepath.Join(dir, "k8s-bigip-ctlr.log")
+			Expect(initLogger("INFO", logPath, logFormatText)).To(BeNil())
+			logEffectiveConfig(flags)
+			data, err := os.ReadFile(logPath)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(ContainSubstring("cm-password=<redacted> (file)"))
+			Expect(string(data)).To(ContainSubstring("cm-url=cli.example.com (flag)"))
+			Expect(string(data)).To(ContainSubstring("kubeconfig=./config (default)"))
+			Expect(string(data)).NotTo(ContainSubstring("secret"))
+
+			_init()
+			Expect(os.WriteFile(configPath, []byte("unknown-option: true"), 0600)).To(BeNil())
+			flags.Parse([]string{"./bin/k8s-bigip-ctlr", "--config=" + configPath})
+			Expect(loadConfig(flags, nil)).ToNot(BeNil(), "unknown options should be rejected")
+
+			_init()
+			flags.Parse([]string{"./bin/k8s-bigip-ctlr"})
+			Expect(loadConfig(flags, []string{"CIS_LEADER_ELECT=maybe"})).ToNot(BeNil(), "invalid values should be rejected")
+		})
+		It("writes logs in JSON format", func() {
+			stdout, stderr := os.Stdout, os.Stderr
+			defer func() {
+				os.Stdout, os.Stderr = stdout, stderr
+				_ = initLogger("INFO", "", logFormatText)
+			}()
+			logPath := filepath.Join(GinkgoT().TempDir(), "k8s-bigip-ctlr.log")
+			Expect(initLogger("INFO", logPath, "JSON")).To(BeNil())
+			log.Debugf("filtered by the log level")
+			log.WithFields(log.Fields{log.FieldRequestID: 1, log.FieldTenant: "test", log.FieldKind: ""}).
+				Infof("posting tenant %v", "test")
+
+			data, err := os.ReadFile(logPath)
+			Expect(err).To(BeNil())
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			Expect(lines).To(HaveLen(1))
+			var entry map[string]interface{}
+			Expect(json.Unmarshal([]byte(lines[0]), &entry)).To(BeNil())
+			Expect(entry["level"]).To(Equal("info"))
+			Expect(entry["msg"]).To(Equal("posting tenant test"))
+			Expect(entry[log.FieldRequestID]).To(BeEquivalentTo(1))
+			Expect(entry[log.FieldTenant]).To(Equal("test"))
+			Expect(entry).ToNot(HaveKey(log.FieldKind), "empty fields are dropped")
+
+			Expect(initLogger("INFO", "", "xml")).ToNot(BeNil())
+		})
+		It("gets token and client certificate from credentials directory", func() {
+			defer _init()
+			defer os.RemoveAll("/tmp/k8s-test-creds")
+
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--credentials-directory=/tmp/k8s-test-creds/",
+				"--deploy-config-cr=default/testcr",
+				"--cm-url=cm.example.com",
+			}
+			flags.Parse(os.Args)
+			os.Mkdir("/tmp/k8s-test-creds", 0755)
+			err := os.WriteFile("/tmp/k8s-test-creds/token", []byte("token"), 0755)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = getCredentials()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCMAuthType()).To(Equal(tokenmanager.AuthTokenFile))
+			Expect(*cmTokenFile).To(Equal("/tmp/k8s-test-creds/token"))
+
+			_init()
+			flags.Parse(os.Args)
+			os.Remove("/tmp/k8s-test-creds/token")
+			err = os.WriteFile("/tmp/k8s-test-creds/tls.crt", []byte("crt"), 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = getCredentials()
+			Expect(err).ToNot(BeNil(), "client key is required for client-cert authentication")
+			err = os.WriteFile("/tmp/k8s-test-creds/tls.key", []byte("key"), 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = getCredentials()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCMAuthType()).To(Equal(tokenmanager.AuthClientCert))
+			Expect(*cmClientKey).To(Equal("/tmp/k8s-test-creds/tls.key"))
+		})
+
 		It("uses credentials file over CLI args", func() {
 			defer _init()
 			defer os.RemoveAll("/tmp/k8s-test-creds")
@@ -28,6 +28,7 @@ type VirtualServerStatus struct {
 // VirtualServerSpec is the spec of the VirtualServer resource.
 type VirtualServerSpec struct {
 	Host                             string           `json:"host,omitempty"`
+	Hosts                            []string         `json:"hosts,omitempty"`
 	HostGroup                        string           `json:"hostGroup,omitempty"`
 	VirtualServerAddress             string           `json:"virtualServerAddress,omitempty"`
 	AdditionalVirtualServerAddresses []string         `json:"additionalVirtualServerAddresses,omitempty"`
@@ -55,6 +56,60 @@ type VirtualServerSpec struct {
 	AllowSourceRange                 []string         `json:"allowSourceRange,omitempty"`
 	HttpMrfRoutingEnabled            *bool            `json:"httpMrfRoutingEnabled,omitempty"`
 	Partition                        string           `json:"partition,omitempty"`
+	RequestHeaders                   *HeaderActions   `json:"requestHeaders,omitempty"`
+	ResponseHeaders                  *HeaderActions   `json:"responseHeaders,omitempty"`
+	Rules                            []VSRule         `json:"rules,omitempty"`
+	ErrorPages                       *ErrorPages      `json:"errorPages,omitempty"`
+}
+
+// ErrorPages defines the pages served by BIG-IP for the VirtualServer in maintenance and in place of the errors.
+type ErrorPages struct {
+	// Maintenance serves the maintenance page for all the requests to the VirtualServer
+	Maintenance bool `json:"maintenance,omitempty"`
+	// MaintenancePage is served in maintenance and when no pool member is available, the status code defaults to 503
+	MaintenancePage *FixedResponse `json:"maintenancePage,omitempty"`
+	Pages           []ErrorPage    `json:"pages,omitempty"`
+}
+
+// ErrorPage replaces the responses of the pool members with the status codes.
+type ErrorPage struct {
+	StatusCodes []int32 `json:"statusCodes"`
+	// Page is served with the status code of the response if the status code of the page isn't set
+	Page FixedResponse `json:"page"`
+}
+
+// VSRule defines a redirect or a fixed response for the requests to the host and path of the VirtualServer.
+type VSRule struct {
+	Path          string         `json:"path"`
+	Redirect      *RuleRedirect  `json:"redirect,omitempty"`
+	FixedResponse *FixedResponse `json:"fixedResponse,omitempty"`
+}
+
+// RuleRedirect defines the location of the redirect, the scheme, host and path of the request are kept if not set.
+type RuleRedirect struct {
+	Scheme string `json:"scheme,omitempty"`
+	Host   string `json:"host,omitempty"`
+	// Path replaces the path of the request
+	Path string `json:"path,omitempty"`
+	// ReplacePrefix replaces the path of the rule in the path of the request
+	ReplacePrefix string `json:"replacePrefix,omitempty"`
+	// StatusCode is one of 301, 302, 307 or 308, defaults to 302
+	StatusCode int32 `json:"statusCode,omitempty"`
+}
+
+// FixedResponse defines the response sent by BIG-IP without forwarding the request to a pool.
+type FixedResponse struct {
+	StatusCode  int32  `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// BodyFrom reads the body from a key of a ConfigMap in the namespace of the VirtualServer
+	BodyFrom *ConfigMapKeyReference `json:"bodyFrom,omitempty"`
+}
+
+// ConfigMapKeyReference refers to a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
 }
 
 // ServiceAddress Service IP address definition (BIG-IP virtual-address).
@@ -84,6 +139,7 @@ type DefaultPool struct {
 type VSPool struct {
 	Name                 string                         `json:"name,omitempty"`
 	Path                 string                         `json:"path,omitempty"`
+	PathType             string                         `json:"pathType,omitempty"`
 	Service              string                         `json:"service"`
 	ServicePort          intstr.IntOrString             `json:"servicePort"`
 	NodeMemberLabel      string                         `json:"nodeMemberLabel,omitempty"`
@@ -100,6 +156,56 @@ type VSPool struct {
 	Weight               *int32                         `json:"weight,omitempty"`
 	AlternateBackends    []AlternateBackend             `json:"alternateBackends"`
 	MultiClusterServices []MultiClusterServiceReference `json:"extendedServiceReferences,omitempty"`
+	Match                *PoolMatch                     `json:"match,omitempty"`
+	RequestHeaders       *HeaderActions                 `json:"requestHeaders,omitempty"`
+	ResponseHeaders      *HeaderActions                 `json:"responseHeaders,omitempty"`
+	Persistence          *PoolPersistence               `json:"persistence,omitempty"`
+	MinimumMembersActive int32                          `json:"minimumMembersActive,omitempty"`
+	FallbackServices     []FallbackService              `json:"fallbackServices,omitempty"`
+	ConnectionLimits     `json:",inline"`
+}
+
+// PoolPersistence defines the session persistence of the requests to the pool.
+type PoolPersistence struct {
+	// Method is one of cookieInsert, sourceAddress, universal or hash
+	Method     string `json:"method"`
+	CookieName string `json:"cookieName,omitempty"`
+	// Expiry of the inserted cookie expressed as [Nd][HH:MM[:SS]]
+	Expiry string `json:"expiry,omitempty"`
+	// HeaderName is the header keying the universal and hash persistence
+	HeaderName string `json:"headerName,omitempty"`
+	// Netmask groups the client addresses of the sourceAddress persistence
+	Netmask string `json:"netmask,omitempty"`
+	TimeOut int32  `json:"timeOut,omitempty"`
+}
+
+// HeaderActions defines the HTTP headers to insert, replace and remove in the request or response.
+type HeaderActions struct {
+	Insert  []HTTPHeader `json:"insert,omitempty"`
+	Replace []HTTPHeader `json:"replace,omitempty"`
+	Remove  []string     `json:"remove,omitempty"`
+}
+
+// HTTPHeader defines the name and value of an HTTP header.
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PoolMatch defines the HTTP request conditions, in addition to the host and path, to route to the pool.
+type PoolMatch struct {
+	Headers         []HTTPMatchCondition `json:"headers,omitempty"`
+	Cookies         []HTTPMatchCondition `json:"cookies,omitempty"`
+	QueryParameters []HTTPMatchCondition `json:"queryParameters,omitempty"`
+	Methods         []string             `json:"methods,omitempty"`
+}
+
+// HTTPMatchCondition matches the named header, cookie or query parameter of the request.
+type HTTPMatchCondition struct {
+	Name string `json:"name"`
+	// Type is one of exact, prefix or present, defaults to exact
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
 }
 
 // TSPool defines a pool object for Transport Server in BIG-IP.
@@ -120,6 +226,9 @@ type TSPool struct {
 	HostRewrite          string                         `json:"hostRewrite,omitempty"`
 	Weight               *int32                         `json:"weight,omitempty"`
 	MultiClusterServices []MultiClusterServiceReference `json:"extendedServiceReferences,omitempty"`
+	MinimumMembersActive int32                          `json:"minimumMembersActive,omitempty"`
+	FallbackServices     []FallbackService              `json:"fallbackServices,omitempty"`
+	ConnectionLimits     `json:",inline"`
 }
 
 // AlternateBackends lists backend svc of A/B
@@ -129,6 +238,13 @@ type AlternateBackend struct {
 	Weight           *int32 `json:"weight,omitempty"`
 }
 
+// FallbackService is a service whose endpoints are added to the pool in a lower priority group, the fallback
+// services are listed in the order of their priority.
+type FallbackService struct {
+	Service          string `json:"service"`
+	ServiceNamespace string `json:"serviceNamespace,omitempty"`
+}
+
 type MultiClusterServiceReference struct {
 	ClusterName string             `json:"clusterName"`
 	SvcName     string             `json:"serviceName"`
@@ -355,6 +471,23 @@ type PoolSettingsSpec struct {
 	ServiceDownAction    string               `json:"serviceDownAction,omitempty"`
 	SlowRampTime         int32                `json:"slowRampTime,omitempty"`
 	MultiPoolPersistence MultiPoolPersistence `json:"multiPoolPersistence,omitempty"`
+	ConnectionLimits     `json:",inline"`
+}
+
+// ConnectionLimits protects the backends of the pool from the excess connections.
+type ConnectionLimits struct {
+	// ConnectionLimit is the maximum concurrent connections of each pool member
+	ConnectionLimit int32 `json:"connectionLimit,omitempty"`
+	// RateLimit is the maximum connections per second accepted by the virtual, the lowest rate limit of the
+	// pools is applied to the virtual
+	RateLimit int32 `json:"rateLimit,omitempty"`
+	// IdleTimeout is the idle timeout in seconds of the connections to the virtual, the highest idle timeout of
+	// the pools is applied to the virtual
+	IdleTimeout int32 `json:"idleTimeout,omitempty"`
+	// ConnectTimeout is the time in seconds to set up the TCP connections of the virtual, it bounds the SYN
+	// retransmissions of the TCP profile as BIG-IP has no connect timeout, the highest connect timeout of the
+	// pools is applied to the virtual
+	ConnectTimeout int32 `json:"connectTimeout,omitempty"`
 }
 
 type SSLProfiles struct {
@@ -474,6 +607,7 @@ type DeployConfigSpec struct {
 	AS3Config     AS3Config     `json:"as3Config,omitempty"`
 	BigIpConfig   []BigIpConfig `json:"bigIpConfig,omitempty"`
 	ExtendedSpec  ExtendedSpec  `json:"extendedSpec,omitempty"`
+	LogConfig     LogConfig     `json:"logConfig,omitempty"`
 }
 
 type BaseConfig struct {
@@ -499,12 +633,32 @@ type AS3Config struct {
 	DebugAS3     bool `json:"debugAS3,omitempty"`
 	PostDelayAS3 int  `json:"postDelayAS3,omitempty"`
 	DocumentAPI  bool `json:"documentAPI,omitempty"`
+	// PostConcurrency is the maximum number of tenants posted concurrently to a Central Manager
+	PostConcurrency int `json:"postConcurrency,omitempty"`
+}
+
+// LogConfig changes the logging of CIS at runtime, it's reverted after the duration when set
+type LogConfig struct {
+	// LogLevel is one of debug, info, warning, error or critical
+	LogLevel string `json:"logLevel,omitempty"`
+	// DebugAS3 logs the AS3 requests and responses
+	DebugAS3 bool `json:"debugAS3,omitempty"`
+	// Components overrides the log level of the components(worker, requesthandler, postmanager)
+	Components map[string]string `json:"components,omitempty"`
+	// Duration after which the logging is reverted to the startup configuration, e.g. 30m
+	Duration string `json:"duration,omitempty"`
 }
 
 type BigIpConfig struct {
 	BigIpAddress     string `json:"bigIpAddress,omitempty"`
 	BigIpLabel       string `json:"bigIpLabel,omitempty"`
 	DefaultPartition string `json:"defaultPartition,omitempty"`
+	// CMURL is the Central Manager owning the BIG-IP, defaults to the Central Manager configured for CIS
+	CMURL string `json:"cmUrl,omitempty"`
+	// CMCredentialsSecret is the secret(<namespace>/<name> or <name>) holding the credentials for CMURL
+	CMCredentialsSecret string `json:"cmCredentialsSecret,omitempty"`
+	// PreferredZone is the topology zone of the BIG-IP, pool members in the zone are preferred over the others
+	PreferredZone string `json:"preferredZone,omitempty"`
 }
 
 type ExtendedSpec struct {
@@ -626,6 +780,7 @@ type HAStatus struct {
 type DeployConfigStatus struct {
 	ControllerStatus    *ControllerStatus    `json:"controllerStatus,omitempty"`
 	CMStatus            *CMStatus            `json:"cmStatus,omitempty"`
+	CMStatuses          []CMStatus           `json:"cmStatuses,omitempty"`
 	NetworkConfigStatus *NetworkConfigStatus `json:"networkConfigStatus,omitempty"`
 	BigIPStatus         []BigIPStatus        `json:"bigIpStatus,omitempty"`
 	K8SClusterStatus    []K8SClusterStatus   `json:"k8sClusterStatus,omitempty"`
@@ -633,6 +788,7 @@ type DeployConfigStatus struct {
 }
 
 type CMStatus struct {
+	URL         string      `json:"url,omitempty"`
 	Message     string      `json:"message"`
 	Error       string      `json:"error,omitempty"`
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
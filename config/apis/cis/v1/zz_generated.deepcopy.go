@@ -228,6 +228,38 @@ func (in *ClusterDetails) DeepCopy() *ClusterDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionLimits) DeepCopyInto(out *ConnectionLimits) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionLimits.
+func (in *ConnectionLimits) DeepCopy() *ConnectionLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerStatus) DeepCopyInto(out *ControllerStatus) {
 	*out = *in
@@ -395,6 +427,7 @@ func (in *DeployConfigSpec) DeepCopyInto(out *DeployConfigSpec) {
 		copy(*out, *in)
 	}
 	in.ExtendedSpec.DeepCopyInto(&out.ExtendedSpec)
+	in.LogConfig.DeepCopyInto(&out.LogConfig)
 	return
 }
 
@@ -421,6 +454,13 @@ func (in *DeployConfigStatus) DeepCopyInto(out *DeployConfigStatus) {
 		*out = new(CMStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CMStatuses != nil {
+		in, out := &in.CMStatuses, &out.CMStatuses
+		*out = make([]CMStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NetworkConfigStatus != nil {
 		in, out := &in.NetworkConfigStatus, &out.NetworkConfigStatus
 		*out = new(NetworkConfigStatus)
@@ -460,6 +500,56 @@ func (in *DeployConfigStatus) DeepCopy() *DeployConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPage) DeepCopyInto(out *ErrorPage) {
+	*out = *in
+	if in.StatusCodes != nil {
+		in, out := &in.StatusCodes, &out.StatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	in.Page.DeepCopyInto(&out.Page)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPage.
+func (in *ErrorPage) DeepCopy() *ErrorPage {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPages) DeepCopyInto(out *ErrorPages) {
+	*out = *in
+	if in.MaintenancePage != nil {
+		in, out := &in.MaintenancePage, &out.MaintenancePage
+		*out = new(FixedResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pages != nil {
+		in, out := &in.Pages, &out.Pages
+		*out = make([]ErrorPage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPages.
+func (in *ErrorPages) DeepCopy() *ErrorPages {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtendedRouteGroupConfig) DeepCopyInto(out *ExtendedRouteGroupConfig) {
 	*out = *in
@@ -638,6 +728,43 @@ func (in *ExternalDNSSpec) DeepCopy() *ExternalDNSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FallbackService) DeepCopyInto(out *FallbackService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FallbackService.
+func (in *FallbackService) DeepCopy() *FallbackService {
+	if in == nil {
+		return nil
+	}
+	out := new(FallbackService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixedResponse) DeepCopyInto(out *FixedResponse) {
+	*out = *in
+	if in.BodyFrom != nil {
+		in, out := &in.BodyFrom, &out.BodyFrom
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixedResponse.
+func (in *FixedResponse) DeepCopy() *FixedResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(FixedResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HAClusterConfig) DeepCopyInto(out *HAClusterConfig) {
 	*out = *in
@@ -690,6 +817,69 @@ func (in *HAStatus) DeepCopy() *HAStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPMatchCondition) DeepCopyInto(out *HTTPMatchCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPMatchCondition.
+func (in *HTTPMatchCondition) DeepCopy() *HTTPMatchCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPMatchCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderActions) DeepCopyInto(out *HeaderActions) {
+	*out = *in
+	if in.Insert != nil {
+		in, out := &in.Insert, &out.Insert
+		*out = make([]HTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replace != nil {
+		in, out := &in.Replace, &out.Replace
+		*out = make([]HTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderActions.
+func (in *HeaderActions) DeepCopy() *HeaderActions {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressLink) DeepCopyInto(out *IngressLink) {
 	*out = *in
@@ -871,6 +1061,29 @@ func (in *L7PolicySpec) DeepCopy() *L7PolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogConfig) DeepCopyInto(out *LogConfig) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogConfig.
+func (in *LogConfig) DeepCopy() *LogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LtmIRulesSpec) DeepCopyInto(out *LtmIRulesSpec) {
 	*out = *in
@@ -1078,10 +1291,63 @@ func (in *PolicySpec) DeepCopy() *PolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMatch) DeepCopyInto(out *PoolMatch) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make([]HTTPMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.QueryParameters != nil {
+		in, out := &in.QueryParameters, &out.QueryParameters
+		*out = make([]HTTPMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMatch.
+func (in *PoolMatch) DeepCopy() *PoolMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolPersistence) DeepCopyInto(out *PoolPersistence) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolPersistence.
+func (in *PoolPersistence) DeepCopy() *PoolPersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolPersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolSettingsSpec) DeepCopyInto(out *PoolSettingsSpec) {
 	*out = *in
 	out.MultiPoolPersistence = in.MultiPoolPersistence
+	out.ConnectionLimits = in.ConnectionLimits
 	return
 }
 
@@ -1192,6 +1458,22 @@ func (in *ProfileVSSpec) DeepCopy() *ProfileVSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleRedirect) DeepCopyInto(out *RuleRedirect) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleRedirect.
+func (in *RuleRedirect) DeepCopy() *RuleRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSLProfiles) DeepCopyInto(out *SSLProfiles) {
 	*out = *in
@@ -1380,6 +1662,12 @@ func (in *TSPool) DeepCopyInto(out *TSPool) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FallbackServices != nil {
+		in, out := &in.FallbackServices, &out.FallbackServices
+		*out = make([]FallbackService, len(*in))
+		copy(*out, *in)
+	}
+	out.ConnectionLimits = in.ConnectionLimits
 	return
 }
 
@@ -1534,6 +1822,32 @@ func (in *VSPool) DeepCopyInto(out *VSPool) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(PoolMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = new(HeaderActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = new(HeaderActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PoolPersistence)
+		**out = **in
+	}
+	if in.FallbackServices != nil {
+		in, out := &in.FallbackServices, &out.FallbackServices
+		*out = make([]FallbackService, len(*in))
+		copy(*out, *in)
+	}
+	out.ConnectionLimits = in.ConnectionLimits
 	return
 }
 
@@ -1547,6 +1861,32 @@ func (in *VSPool) DeepCopy() *VSPool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSRule) DeepCopyInto(out *VSRule) {
+	*out = *in
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(RuleRedirect)
+		**out = **in
+	}
+	if in.FixedResponse != nil {
+		in, out := &in.FixedResponse, &out.FixedResponse
+		*out = new(FixedResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSRule.
+func (in *VSRule) DeepCopy() *VSRule {
+	if in == nil {
+		return nil
+	}
+	out := new(VSRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServer) DeepCopyInto(out *VirtualServer) {
 	*out = *in
@@ -1611,6 +1951,11 @@ func (in *VirtualServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalVirtualServerAddresses != nil {
 		in, out := &in.AdditionalVirtualServerAddresses, &out.AdditionalVirtualServerAddresses
 		*out = make([]string, len(*in))
@@ -1650,6 +1995,28 @@ func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = new(HeaderActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = new(HeaderActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]VSRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ErrorPages != nil {
+		in, out := &in.ErrorPages, &out.ErrorPages
+		*out = new(ErrorPages)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"reflect"
 	"sort"
@@ -90,7 +91,7 @@ func processDataGroupForAS3(rsCfg *ResourceConfig, app as3Application) {
 
 // Process for AS3 Resource
 func processResourcesForAS3(cfg *ResourceConfig, app as3Application, shareNodes bool, tenant string, documentAPI bool,
-	poolMemberType string) {
+	poolMemberType, preferredZone string) {
 
 	//Create policies
 	createPoliciesDecl(cfg, app)
@@ -99,7 +100,7 @@ func processResourcesForAS3(cfg *ResourceConfig, app as3Application, shareNodes
 	createMonitorDecl(cfg, app)
 
 	//Create pools
-	createPoolDecl(cfg, app, shareNodes, tenant, poolMemberType)
+	createPoolDecl(cfg, app, shareNodes, tenant, poolMemberType, preferredZone)
 
 	switch cfg.MetaData.ResourceType {
 	case VirtualServer:
@@ -140,8 +141,9 @@ func createPoliciesDecl(cfg *ResourceConfig, app as3Application) {
 	}
 }
 
-// Create AS3 Pools for CRD
-func createPoolDecl(cfg *ResourceConfig, app as3Application, shareNodes bool, tenant, poolMemberType string) {
+// Create AS3 Pools for CRD, the members in the preferred zone of the bigip are placed in a higher priority group
+// than the other members of the same priority
+func createPoolDecl(cfg *ResourceConfig, app as3Application, shareNodes bool, tenant, poolMemberType, preferredZone string) {
 	for _, v := range cfg.Pools {
 		pool := &as3Pool{}
 		if v.Balance == "fastest-app-response" || v.Balance == "least-connections-member" ||
@@ -160,13 +162,15 @@ func createPoolDecl(cfg *ResourceConfig, app as3Application, shareNodes bool, te
 			log.Warningf("[AS3] virtualServer: %v, pool: %v, ServiceDownAction pool property is not supported with BIG-IP Next", cfg.Virtual.Name, v.Name)
 		}
 		pool.SlowRampTime = v.SlowRampTime
-		poolMemberSet := make(map[PoolMember]struct{})
+		pool.MinimumMembersActive = v.MinimumMembersActive
+		zoneAware := preferredZone != "" && hasZonedMembers(v.Members)
+		if zoneAware && pool.MinimumMembersActive == 0 {
+			// priority groups are activated only with the minimum active members
+			pool.MinimumMembersActive = DefaultMinimumMembersActive
+		}
+		// index of the pool member in the pool by its address and port
+		poolMemberSet := make(map[string]int)
 		for _, val := range v.Members {
-			// Skip duplicate pool members
-			if _, ok := poolMemberSet[val]; ok {
-				continue
-			}
-			poolMemberSet[val] = struct{}{}
 			var member as3PoolMember
 			member.AddressDiscovery = "static"
 			member.ServicePort = val.Port
@@ -180,6 +184,23 @@ func createPoolDecl(cfg *ResourceConfig, app as3Application, shareNodes bool, te
 			if val.ConnectionLimit != 0 {
 				member.ConnectionLimit = val.ConnectionLimit
 			}
+			member.PriorityGroup = val.PriorityGroup
+			if zoneAware {
+				member.PriorityGroup = val.PriorityGroup * 2
+				if val.Zone == preferredZone {
+					member.PriorityGroup++
+				}
+			}
+			// the same address and port is discovered from the primary and the fallback services, keep the member
+			// of the highest priority group
+			memberKey := fmt.Sprintf("%s:%d", val.Address, val.Port)
+			if i, ok := poolMemberSet[memberKey]; ok {
+				if member.PriorityGroup > pool.Members[i].PriorityGroup {
+					pool.Members[i] = member
+				}
+				continue
+			}
+			poolMemberSet[memberKey] = len(pool.Members)
 			pool.Members = append(pool.Members, member)
 		}
 		for _, val := range v.MonitorNames {
@@ -210,6 +231,16 @@ func createPoolDecl(cfg *ResourceConfig, app as3Application, shareNodes bool, te
 	}
 }
 
+// hasZonedMembers returns true if the topology zone is known for any of the pool members
+func hasZonedMembers(members []PoolMember) bool {
+	for _, member := range members {
+		if member.Zone != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func updateVirtualToHTTPS(v *as3Service) {
 	v.Class = "Service_HTTPS"
 	redirect80 := false
@@ -373,6 +404,8 @@ func createServiceDecl(cfg *ResourceConfig, app as3Application, tenant string) {
 			BigIP: cfg.Virtual.ProfileMultiplex,
 		}
 	}
+	svc.RateLimit = cfg.Virtual.RateLimit
+	createConnectionProfileDecl(cfg, app, svc, tenant)
 	// updating the virtual server to https if a passthrough datagroup is found
 	name := getRSCfgResName(cfg.Virtual.Name, PassthroughHostsDgName)
 	mapKey := NameRef{
@@ -551,6 +584,20 @@ func createRuleCondition(rl *Rule, rulesData *as3Rule, port int) {
 					Values: c.Values,
 				}
 			}
+		} else if c.HTTPHeader || c.HTTPCookie {
+			condition.Type = "httpHeader"
+			if c.HTTPCookie {
+				condition.Type = "httpCookie"
+			}
+			condition.Name = c.Name
+			condition.All = createPolicyCompareString(c)
+		} else if c.QueryParameter {
+			condition.Type = "httpUri"
+			condition.Name = c.Name
+			condition.QueryParameter = createPolicyCompareString(c)
+		} else if c.HTTPMethod {
+			condition.Type = "httpMethod"
+			condition.All = createPolicyCompareString(c)
 		}
 		if c.Request {
 			condition.Event = "request"
@@ -560,6 +607,22 @@ func createRuleCondition(rl *Rule, rulesData *as3Rule, port int) {
 	}
 }
 
+// createPolicyCompareString creates the AS3 compare string of the header, cookie, query parameter and method conditions
+func createPolicyCompareString(c *condition) *as3PolicyCompareString {
+	compare := &as3PolicyCompareString{
+		Values: c.Values,
+	}
+	switch {
+	case c.Present:
+		compare.Operand = "exists"
+	case c.StartsWith:
+		compare.Operand = "starts-with"
+	default:
+		compare.Operand = "equals"
+	}
+	return compare
+}
+
 // Create AS3 Rule Action for CRD
 func createRuleAction(rl *Rule, rulesData *as3Rule) {
 	for _, v := range rl.Actions {
@@ -573,6 +636,9 @@ func createRuleAction(rl *Rule, rulesData *as3Rule) {
 		if v.Request {
 			action.Event = "request"
 		}
+		if v.Response {
+			action.Event = "response"
+		}
 		if v.Redirect {
 			action.Type = "httpRedirect"
 		}
@@ -603,6 +669,23 @@ func createRuleAction(rl *Rule, rulesData *as3Rule) {
 				Value: v.Value,
 			}
 		}
+		// Handle header insert, replace and remove.
+		if v.HTTPHeader {
+			action.Type = "httpHeader"
+			header := &as3ActionReplaceMap{
+				Name:  v.HeaderName,
+				Value: v.Value,
+			}
+			if v.Insert {
+				action.Insert = header
+			} else if v.Replace {
+				action.Replace = header
+			} else if v.Remove {
+				action.Remove = &as3ActionReplaceMap{
+					Name: v.HeaderName,
+				}
+			}
+		}
 		p := strings.Split(v.Pool, "/")
 		if v.Pool != "" {
 			action.Select = &as3ActionForwardSelect{
@@ -628,6 +711,31 @@ func createRuleAction(rl *Rule, rulesData *as3Rule) {
 		if v.Drop {
 			action.Type = "drop"
 		}
+		if v.StatusCode != 0 {
+			action.Code = v.StatusCode
+		}
+		// Set the Tcl variable
+		if v.Tcl {
+			action.Type = "tcl"
+			action.SetVariable = &as3ActionSetVariable{
+				Name:       v.Variable,
+				Expression: v.Value,
+			}
+		}
+		// Persist the requests
+		if v.Persist {
+			action.Type = "persist"
+			switch v.PersistMethod {
+			case PersistenceCookieInsert:
+				action.CookieInsert = &as3PersistCookie{Name: v.CookieName, Expiry: v.Expiry}
+			case PersistenceSourceAddress:
+				action.SourceAddress = &as3PersistAddress{Netmask: v.Netmask, Timeout: v.Timeout}
+			case PersistenceUniversal:
+				action.Universal = &as3PersistKey{Key: v.Value, Timeout: v.Timeout}
+			case PersistenceHash:
+				action.Hash = &as3PersistKey{Key: v.Value, Timeout: v.Timeout}
+			}
+		}
 
 		rulesData.Actions = append(rulesData.Actions, action)
 	}
@@ -869,6 +977,71 @@ func createMonitorDecl(cfg *ResourceConfig, app as3Application) {
 }
 
 // Create AS3 transport Service for CRD
+// createConnectionProfileDecl creates the TCP or UDP profile of the virtual with the idle and connect timeouts, the
+// profiles referenced by the policy take precedence over the timeouts
+func createConnectionProfileDecl(cfg *ResourceConfig, app as3Application, svc *as3Service, tenant string) {
+	if cfg.Virtual.IdleTimeout == 0 && cfg.Virtual.ConnectTimeout == 0 {
+		return
+	}
+	if cfg.Virtual.Mode == "performance" || cfg.Virtual.IpProtocol == "sctp" {
+		addConnectionProfileWarning(cfg, "idleTimeout and connectTimeout are not supported with the performance mode and sctp")
+		return
+	}
+	profile := &as3ProtocolProfile{Class: "TCP_Profile", IdleTimeout: cfg.Virtual.IdleTimeout}
+	existing := svc.ProfileTCP
+	if cfg.Virtual.IpProtocol == "udp" {
+		profile.Class = "UDP_Profile"
+		existing = svc.ProfileUDP
+		if cfg.Virtual.ConnectTimeout != 0 {
+			addConnectionProfileWarning(cfg, "connectTimeout is not supported with udp")
+		}
+	} else if cfg.Virtual.ConnectTimeout != 0 {
+		profile.SynRtoBase, profile.SynMaxRetrans = getSynRetransmission(cfg.Virtual.ConnectTimeout)
+	}
+	if existing != nil {
+		addConnectionProfileWarning(cfg, fmt.Sprintf("idleTimeout and connectTimeout are ignored as the %v is "+
+			"referenced by the policy", profile.Class))
+		return
+	}
+	if profile.IdleTimeout == 0 && profile.SynRtoBase == 0 {
+		return
+	}
+	name := fmt.Sprintf("%v_%v", cfg.Virtual.Name, strings.ToLower(profile.Class))
+	app[name] = profile
+	pointer := &as3ResourcePointer{
+		Use: fmt.Sprintf("/%s/%s/%s", tenant, cfg.Virtual.Name, name),
+	}
+	if profile.Class == "UDP_Profile" {
+		svc.ProfileUDP = pointer
+	} else {
+		svc.ProfileTCP = pointer
+	}
+}
+
+// addConnectionProfileWarning reports the connection limits of the virtual which can't be applied on the resources
+// of the virtual
+func addConnectionProfileWarning(cfg *ResourceConfig, warning string) {
+	log.Warningf("[AS3] virtualServer: %v, %v", cfg.Virtual.Name, warning)
+	for rsc, kind := range cfg.MetaData.baseResources {
+		if namespace, name, found := strings.Cut(rsc, "/"); found {
+			prometheus.AddConfigurationWarning(kind, namespace, name, warning)
+		}
+	}
+}
+
+// getSynRetransmission returns the initial SYN retransmission timeout in milliseconds and the SYN retransmissions of
+// the TCP profile to give up on the connection setup after the connect timeout, the retransmission timeout doubles
+// on each retransmission and is limited to maxSynRtoBase
+func getSynRetransmission(connectTimeout int32) (int32, int32) {
+	timeout := int64(connectTimeout) * 1000
+	for retransmissions := int64(1); ; retransmissions++ {
+		// the connection setup waits the initial timeout and the doubled timeout of each retransmission
+		if rtoBase := timeout / (1<<(retransmissions+1) - 1); rtoBase <= maxSynRtoBase {
+			return int32(max(rtoBase, 1)), int32(retransmissions)
+		}
+	}
+}
+
 func createTransportServiceDecl(cfg *ResourceConfig, app as3Application, tenant string) {
 	svc := &as3Service{}
 	if cfg.Virtual.Mode == "standard" {
@@ -942,6 +1115,9 @@ func createTransportServiceDecl(cfg *ResourceConfig, app as3Application, tenant
 		}
 	}
 
+	svc.RateLimit = cfg.Virtual.RateLimit
+	createConnectionProfileDecl(cfg, app, svc, tenant)
+
 	if cfg.Virtual.TranslateServerAddress == true {
 		log.Warningf("[AS3] virtualServer: %v, TranslateServerAddress feature is not supported with BIG-IP Next", cfg.Virtual.Name)
 	}
@@ -1041,8 +1217,15 @@ func (req *RequestHandler) createAS3Config(rsConfig ResourceConfigRequest, pm *P
 		failedTenants:         make(map[string]struct{}),
 		incomingTenantDeclMap: make(map[string]as3Tenant),
 	}
+	// the rendered declarations are updated along with reading the tenant cache
+	pm.tenantCacheLock.Lock()
+	defer pm.tenantCacheLock.Unlock()
+	if pm.renderedTenantDeclMap == nil {
+		pm.renderedTenantDeclMap = make(map[string]as3Tenant)
+	}
 	for tenant, cfg := range pm.AS3PostManager.createAS3BIGIPConfig(rsConfig.bigIpResourceConfig, pm.defaultPartition, pm.cachedTenantDeclMap,
-		rsConfig.poolMemberType) {
+		rsConfig.poolMemberType, rsConfig.bigIpConfig.PreferredZone) {
+		pm.renderedTenantDeclMap[tenant] = cfg.(as3Tenant)
 		if !reflect.DeepEqual(cfg, pm.cachedTenantDeclMap[tenant]) ||
 			(req.PrimaryClusterHealthProbeParams.EndPoint != "" && req.PrimaryClusterHealthProbeParams.statusChanged) {
 			as3cfg.incomingTenantDeclMap[tenant] = cfg.(as3Tenant)
@@ -1059,13 +1242,13 @@ func (req *RequestHandler) createAS3Config(rsConfig ResourceConfigRequest, pm *P
 }
 
 func (as3PM *AS3PostManager) createAS3BIGIPConfig(config BigIpResourceConfig, partition string, cachedTenantDeclMap map[string]as3Tenant,
-	poolMemberType string) as3ADC {
-	adc := as3PM.createAS3LTMConfigADC(config, partition, cachedTenantDeclMap, poolMemberType)
+	poolMemberType, preferredZone string) as3ADC {
+	adc := as3PM.createAS3LTMConfigADC(config, partition, cachedTenantDeclMap, poolMemberType, preferredZone)
 	return adc
 }
 
 func (postMgr *AS3PostManager) createAS3LTMConfigADC(config BigIpResourceConfig, partition string, cachedTenantDeclMap map[string]as3Tenant,
-	poolMemberType string) as3ADC {
+	poolMemberType, preferredZone string) as3ADC {
 	adc := as3ADC{}
 	cisLabel := partition
 
@@ -1094,7 +1277,7 @@ func (postMgr *AS3PostManager) createAS3LTMConfigADC(config BigIpResourceConfig,
 
 			// Process rscfg to create AS3 Resources
 			processResourcesForAS3(resourceConfig, app, config.shareNodes, tenantName,
-				postMgr.AS3Config.DocumentAPI, poolMemberType)
+				postMgr.AS3Config.DocumentAPI, poolMemberType, preferredZone)
 
 			// Process CustomProfiles
 			processCustomProfilesForAS3(resourceConfig, app, postMgr.bigIPAS3Version)
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/statusmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// cmSecretSyncTimeout bounds the wait for the informer of the central manager credentials secret to sync
+const cmSecretSyncTimeout = 30 * time.Second
+
+// getCMKey returns the key of the central manager managing the bigip, empty key refers to the central manager of CIS
+func (ctlr *Controller) getCMKey(config cisapiv1.BigIpConfig) CMKey {
+	cmURL := normalizeCMURL(config.CMURL)
+	if cmURL == "" || (cmURL == normalizeCMURL(ctlr.CMTokenManager.ServerURL) && config.CMCredentialsSecret == "") {
+		return CMKey{}
+	}
+	return CMKey{URL: cmURL, CredentialsSecret: config.CMCredentialsSecret}
+}
+
+// normalizeCMURL returns the url of the central manager without the trailing "/"
+func normalizeCMURL(url string) string {
+	return strings.TrimSuffix(strings.TrimSpace(url), "/")
+}
+
+// getCMTokenManager returns the token manager of the central manager managing the bigip,
+// token manager is created for the central managers referenced for the first time
+func (ctlr *Controller) getCMTokenManager(config cisapiv1.BigIpConfig) (*tokenmanager.TokenManager, error) {
+	key := ctlr.getCMKey(config)
+	if key == (CMKey{}) {
+		return ctlr.CMTokenManager, nil
+	}
+	ctlr.centralManagers.Lock()
+	defer ctlr.centralManagers.Unlock()
+	if cm, ok := ctlr.centralManagers.cmMap[key]; ok {
+		return cm.tokenManager, nil
+	}
+	// the credentials secret is watched, so that the rotated credentials are picked up by the token manager
+	stopCh := make(chan struct{})
+	credentials, authConfig, err := ctlr.getCMCredentials(key, stopCh)
+	if err != nil {
+		close(stopCh)
+		ctlr.CMTokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.CMStatus{
+			URL:         key.URL,
+			Message:     tokenmanager.TokenFetchFailed,
+			Error:       err.Error(),
+			LastUpdated: metaV1.Now(),
+		})
+		return nil, err
+	}
+	tm, err := tokenmanager.NewTokenManagerWithAuth(key.URL, credentials, authConfig, ctlr.CMTokenManager.TrustedCerts,
+		ctlr.CMTokenManager.SslInsecure, ctlr.CMTokenManager.StatusManager)
+	if err != nil {
+		close(stopCh)
+		return nil, fmt.Errorf("invalid authentication config for Central Manager %v: %v", key.URL, err)
+	}
+	tm.SetStatusURL(key.URL)
+	cm := &centralManager{
+		tokenManager: tm,
+		stopCh:       stopCh,
+	}
+	if ctlr.centralManagers.cmMap == nil {
+		ctlr.centralManagers.cmMap = make(map[CMKey]*centralManager)
+	}
+	ctlr.centralManagers.cmMap[key] = cm
+	log.Infof("[CM] Added Central Manager %v", key.URL)
+	go func() {
+		// fetch the token in background, so that other central managers are not blocked
+		if !tm.SyncTokenUntil(cm.stopCh) {
+			return
+		}
+		cmVer, err := tm.GetCMVersion()
+		if err != nil {
+			log.Errorf("[CM] error getting version of Central Manager %v: %v", key.URL, err)
+		}
+		tm.SetCMVersion(cmVer)
+		tm.Start(cm.stopCh, tokenmanager.CMRefreshTokenExpiration)
+	}()
+	return tm, nil
+}
+
+// releaseCMTokenManager stops the token manager of the central manager if none of the bigips refers to it
+func (ctlr *Controller) releaseCMTokenManager(config cisapiv1.BigIpConfig) {
+	key := ctlr.getCMKey(config)
+	if key == (CMKey{}) {
+		return
+	}
+	for bigIpConfig := range ctlr.bigIpConfigMap {
+		if bigIpConfig != config && ctlr.getCMKey(bigIpConfig) == key {
+			return
+		}
+	}
+	ctlr.centralManagers.Lock()
+	defer ctlr.centralManagers.Unlock()
+	if cm, ok := ctlr.centralManagers.cmMap[key]; ok {
+		close(cm.stopCh)
+		delete(ctlr.centralManagers.cmMap, key)
+		log.Infof("[CM] Removed Central Manager %v", key.URL)
+		// remove the central manager from the deploy config status
+		ctlr.CMTokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.CMStatus{
+			URL: key.URL,
+		})
+	}
+}
+
+// getCMTokenManagers returns the token managers of all the central managers
+func (ctlr *Controller) getCMTokenManagers() []*tokenmanager.TokenManager {
+	tokenManagers := []*tokenmanager.TokenManager{ctlr.CMTokenManager}
+	ctlr.centralManagers.Lock()
+	defer ctlr.centralManagers.Unlock()
+	for _, cm := range ctlr.centralManagers.cmMap {
+		tokenManagers = append(tokenManagers, cm.tokenManager)
+	}
+	return tokenManagers
+}
+
+// getCMCredentials starts the informer of the credentials secret of the central manager, which runs until the
+// stop channel is closed, and reads the credentials from the secret
+func (ctlr *Controller) getCMCredentials(key CMKey, stopCh <-chan struct{}) (tokenmanager.Credentials,
+	tokenmanager.AuthConfig, error) {
+	var credentials tokenmanager.Credentials
+	var authConfig tokenmanager.AuthConfig
+	if key.CredentialsSecret == "" {
+		return credentials, authConfig, fmt.Errorf("cmCredentialsSecret is required when cmUrl is provided")
+	}
+	// secret is looked up in the DeployConfig namespace if namespace is not provided
+	namespace := strings.Split(ctlr.CISConfigCRKey, "/")[0]
+	name := key.CredentialsSecret
+	if keys := strings.Split(key.CredentialsSecret, "/"); len(keys) == 2 {
+		namespace, name = keys[0], keys[1]
+	}
+	secretInformer := ctlr.newCMSecretInformer(key, namespace, name)
+	go secretInformer.Run(stopCh)
+	ctx, cancel := context.WithTimeout(context.Background(), cmSecretSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), secretInformer.HasSynced) {
+		return credentials, authConfig, fmt.Errorf("unable to sync Central Manager credentials secret %v/%v", namespace, name)
+	}
+	obj, found, err := secretInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil {
+		return credentials, authConfig, fmt.Errorf("unable to get Central Manager credentials secret %v/%v: %v", namespace,
+			name, err)
+	}
+	if !found {
+		return credentials, authConfig, fmt.Errorf("Central Manager credentials secret %v/%v not found", namespace, name)
+	}
+	return validateCMCredentials(obj.(*v1.Secret))
+}
+
+// newCMSecretInformer creates the informer of the credentials secret of the central manager
+func (ctlr *Controller) newCMSecretInformer(key CMKey, namespace, name string) cache.SharedIndexInformer {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	secretInformer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metaV1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return ctlr.clientsets.KubeClient.CoreV1().Secrets(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return ctlr.clientsets.KubeClient.CoreV1().Secrets(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&v1.Secret{},
+		0*time.Second,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	secretInformer.AddEventHandler(
+		&cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, cur interface{}) { ctlr.updateCMCredentials(key, old, cur) },
+		},
+	)
+	secretInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Secret, Local))
+	return secretInformer
+}
+
+// updateCMCredentials updates the credentials of the central manager when its credentials secret changes
+func (ctlr *Controller) updateCMCredentials(key CMKey, old, cur interface{}) {
+	oldSecret, ok := old.(*v1.Secret)
+	if !ok {
+		return
+	}
+	secret, ok := cur.(*v1.Secret)
+	if !ok || reflect.DeepEqual(oldSecret.Data, secret.Data) {
+		return
+	}
+	ctlr.centralManagers.Lock()
+	cm, ok := ctlr.centralManagers.cmMap[key]
+	ctlr.centralManagers.Unlock()
+	if !ok {
+		return
+	}
+	credentials, authConfig, err := validateCMCredentials(secret)
+	if err != nil {
+		log.Errorf("[CM] Failed to update the credentials of Central Manager %v: %v", key.URL, err)
+		return
+	}
+	log.Infof("[CM] Credentials secret of Central Manager %v updated", key.URL)
+	// token manager re-login may block on retry, so don't block the informer
+	go func() {
+		if err := cm.tokenManager.UpdateAuth(credentials, authConfig); err != nil {
+			log.Errorf("[CM] Failed to update the credentials of Central Manager %v: %v", key.URL, err)
+		}
+	}()
+}
+
+// validateCMCredentials returns the credentials and authentication type of the central manager credentials secret,
+// the basic authentication requires both the username and password
+func validateCMCredentials(secret *v1.Secret) (tokenmanager.Credentials, tokenmanager.AuthConfig, error) {
+	credentials, authConfig := getCMCredentialsFromSecret(secret)
+	if authConfig.Type == tokenmanager.AuthBasic && (credentials.Username == "" || credentials.Password == "") {
+		return credentials, authConfig, fmt.Errorf("Central Manager credentials secret %v/%v should contain username and password, "+
+			"token or tls.crt and tls.key", secret.Namespace, secret.Name)
+	}
+	return credentials, authConfig, nil
+}
+
+// getCMCredentialsFromSecret returns the credentials and authentication type based on the keys of the secret,
+// the keys are same as the files in the credentials directory
+func getCMCredentialsFromSecret(secret *v1.Secret) (tokenmanager.Credentials, tokenmanager.AuthConfig) {
+	var credentials tokenmanager.Credentials
+	authConfig := tokenmanager.AuthConfig{Type: tokenmanager.AuthBasic}
+	if len(secret.Data[v1.TLSCertKey]) > 0 && len(secret.Data[v1.TLSPrivateKeyKey]) > 0 {
+		authConfig.Type = tokenmanager.AuthClientCert
+		authConfig.ClientCertData = secret.Data[v1.TLSCertKey]
+		authConfig.ClientKeyData = secret.Data[v1.TLSPrivateKeyKey]
+		return credentials, authConfig
+	}
+	if token := strings.TrimSpace(string(secret.Data["token"])); token != "" {
+		authConfig.Type = tokenmanager.AuthAPIToken
+		authConfig.APIToken = token
+		return credentials, authConfig
+	}
+	credentials.Username = strings.TrimSpace(string(secret.Data["username"]))
+	credentials.Password = strings.TrimSpace(string(secret.Data["password"]))
+	return credentials, authConfig
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"net/http"
+)
+
+var _ = Describe("Central Manager Tests", func() {
+	var mockCtlr *mockController
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		mockCtlr.CISConfigCRKey = "kube-system/cis-config"
+		server = ghttp.NewServer()
+		server.RouteToHandler("GET", tokenmanager.CMVersionURL,
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{"version": "BIG-IP-Next-CentralManager-20.3.0-0.1"}))
+		mockCtlr.clientsets.KubeClient = k8sfake.NewSimpleClientset(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dc2-cm", Namespace: "kube-system"},
+			Data:       map[string][]byte{"token": []byte("dc2.token")},
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Use the central manager of CIS by default", func() {
+		tm, err := mockCtlr.getCMTokenManager(cisapiv1.BigIpConfig{BigIpAddress: "10.10.10.1"})
+		Expect(err).To(BeNil())
+		Expect(tm).To(Equal(mockCtlr.CMTokenManager))
+		tm, err = mockCtlr.getCMTokenManager(cisapiv1.BigIpConfig{BigIpAddress: "10.10.10.1", CMURL: mockCtlr.CMTokenManager.ServerURL})
+		Expect(err).To(BeNil())
+		Expect(tm).To(Equal(mockCtlr.CMTokenManager))
+		tm, err = mockCtlr.getCMTokenManager(cisapiv1.BigIpConfig{BigIpAddress: "10.10.10.1",
+			CMURL: mockCtlr.CMTokenManager.ServerURL + "/"})
+		Expect(err).To(BeNil())
+		Expect(tm).To(Equal(mockCtlr.CMTokenManager), "trailing / should refer to the central manager of CIS")
+	})
+
+	It("Create and release token manager for the additional central manager", func() {
+		bigIpConfig1 := cisapiv1.BigIpConfig{BigIpAddress: "10.10.20.1", CMURL: server.URL(), CMCredentialsSecret: "dc2-cm"}
+		bigIpConfig2 := cisapiv1.BigIpConfig{BigIpAddress: "10.10.20.2", CMURL: server.URL() + "/", CMCredentialsSecret: "dc2-cm"}
+		tm, err := mockCtlr.getCMTokenManager(bigIpConfig1)
+		Expect(err).To(BeNil())
+		Expect(tm).ToNot(BeIdenticalTo(mockCtlr.CMTokenManager))
+		Expect(tm.ServerURL).To(Equal(server.URL()))
+		Expect(tm.GetAuthType()).To(Equal(tokenmanager.AuthAPIToken))
+		Eventually(tm.GetAccessToken).Should(Equal("dc2.token"))
+		// same central manager is shared by the bigips
+		tm2, err := mockCtlr.getCMTokenManager(bigIpConfig2)
+		Expect(err).To(BeNil())
+		Expect(tm2).To(BeIdenticalTo(tm))
+		Expect(mockCtlr.getCMTokenManagers()).To(HaveLen(2))
+		Eventually(tm.GetCachedCMVersion).Should(Equal("20.3.0"))
+
+		// rotated token is read from the credentials secret
+		_, err = mockCtlr.clientsets.KubeClient.CoreV1().Secrets("kube-system").Update(context.TODO(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dc2-cm", Namespace: "kube-system"},
+			Data:       map[string][]byte{"token": []byte("dc2.rotated.token")},
+		}, metav1.UpdateOptions{})
+		Expect(err).To(BeNil())
+		Eventually(tm.GetAccessToken).Should(Equal("dc2.rotated.token"))
+
+		mockCtlr.bigIpConfigMap[bigIpConfig2] = BigIpResourceConfig{}
+		mockCtlr.releaseCMTokenManager(bigIpConfig1)
+		Expect(mockCtlr.centralManagers.cmMap).To(HaveLen(1), "central manager in use should not be released")
+		delete(mockCtlr.bigIpConfigMap, bigIpConfig2)
+		mockCtlr.releaseCMTokenManager(bigIpConfig2)
+		Expect(mockCtlr.centralManagers.cmMap).To(BeEmpty())
+	})
+
+	It("Fail for missing or invalid credentials secret", func() {
+		_, err := mockCtlr.getCMTokenManager(cisapiv1.BigIpConfig{BigIpAddress: "10.10.20.1", CMURL: server.URL()})
+		Expect(err).ToNot(BeNil())
+		_, err = mockCtlr.getCMTokenManager(cisapiv1.BigIpConfig{BigIpAddress: "10.10.20.1", CMURL: server.URL(),
+			CMCredentialsSecret: "default/unknown"})
+		Expect(err).ToNot(BeNil())
+		Expect(mockCtlr.centralManagers.cmMap).To(BeEmpty())
+	})
+
+	It("Get credentials from secret", func() {
+		creds, authConfig := getCMCredentialsFromSecret(&v1.Secret{Data: map[string][]byte{
+			"username": []byte("admin\n"), "password": []byte("secret")}})
+		Expect(authConfig.Type).To(Equal(tokenmanager.AuthBasic))
+		Expect(creds).To(Equal(tokenmanager.Credentials{Username: "admin", Password: "secret"}))
+
+		_, authConfig = getCMCredentialsFromSecret(&v1.Secret{Data: map[string][]byte{
+			v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key"), "token": []byte("token")}})
+		Expect(authConfig.Type).To(Equal(tokenmanager.AuthClientCert))
+		Expect(authConfig.ClientCertData).To(Equal([]byte("cert")))
+	})
+})
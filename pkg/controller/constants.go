@@ -38,6 +38,8 @@ const (
 	Route = "Route"
 	// Node update
 	NodeUpdate = "Node"
+	// ConfigMap is k8s native ConfigMap resource
+	ConfigMap = "ConfigMap"
 
 	NodePort = "nodeport"
 	Cluster  = "cluster"
@@ -48,6 +50,12 @@ const (
 	PrimaryCIS    = "primary"
 	// Namespace is k8s namespace
 	HACIS = "HACIS"
+	// LeaderElected is the event of the replica acquiring the lease of the leader election
+	LeaderElected = "LeaderElected"
+
+	// roles of the CIS replicas with the leader election
+	leaderRole  = "leader"
+	standbyRole = "standby"
 
 	// Primary cluster health probe
 	DefaultProbeInterval = 60
@@ -86,6 +94,50 @@ const (
 	defaultAS3Version = "3.48.0"
 	defaultAS3Build   = "10"
 	clusterHealthPath = "/readyz"
+	// healthCheckTimeout is the timeout for checking the reachability of central managers in health endpoints
+	healthCheckTimeout = 5 * time.Second
+	// readinessCacheTTL is the time the reachability of central managers is cached for the readiness probes
+	readinessCacheTTL = 10 * time.Second
+
+	// components whose log level can be changed at runtime
+	logComponentWorker         = "worker"
+	logComponentRequestHandler = "requesthandler"
+	logComponentPostManager    = "postmanager"
+
+	// sources of the runtime log config
+	logConfigSourceAPI          = "api"
+	logConfigSourceDeployConfig = "deployConfig"
+	// logConfigPath is the admin endpoint to change the log config at runtime
+	logConfigPath = "/admin/log-config"
+	// debugPathPrefix serves the read-only state of the controller for troubleshooting
+	debugPathPrefix = "/debug/"
+	// debugRead is the kind of the queue key reading the state of the resource worker
+	debugRead = "DebugRead"
+	// debugReadTimeout is the timeout for the resource worker to read the state
+	debugReadTimeout = 10 * time.Second
+	// shutdownPollInterval is the interval to check whether the pending resources and requests are handled on shutdown
+	shutdownPollInterval = 100 * time.Millisecond
+	// match types of the header, cookie and query parameter conditions of the VirtualServer pools
+	MatchTypeExact   = "exact"
+	MatchTypePrefix  = "prefix"
+	MatchTypePresent = "present"
+	// path types of the VirtualServer pools and Routes
+	PathTypePrefix = "Prefix"
+	PathTypeExact  = "Exact"
+	PathTypeRegex  = "Regex"
+	// persistence methods of the VirtualServer pools
+	PersistenceCookieInsert  = "cookieInsert"
+	PersistenceSourceAddress = "sourceAddress"
+	PersistenceUniversal     = "universal"
+	PersistenceHash          = "hash"
+	// DefaultPersistenceTimeout is the timeout in seconds of the sourceAddress, universal and hash persistence
+	DefaultPersistenceTimeout = 180
+	// DefaultPersistenceNetmask persists the requests of each client address with the sourceAddress persistence
+	DefaultPersistenceNetmask = "255.255.255.255"
+	// MaxPersistenceTimeout is the maximum timeout in seconds of the persistence
+	MaxPersistenceTimeout = 65535
+	// DefaultMinimumMembersActive is the minimum active members of the pools with the fallback services
+	DefaultMinimumMembersActive = 1
 
 	Create = "Create"
 	Update = "Update"
@@ -136,6 +188,7 @@ const (
 	F5ClientSslProfileAnnotation       = "virtual-server.f5.com/clientssl"
 	F5HealthMonitorAnnotation          = "virtual-server.f5.com/health"
 	PodConcurrentConnectionsAnnotation = "virtual-server.f5.com/pod-concurrent-connections"
+	F5VsPathTypeAnnotation             = "virtual-server.f5.com/path-type"
 
 	TLSVerion1_3 TLSVersion = "1.3"
 
@@ -176,6 +229,14 @@ const (
 	HttpsRedirectDgName = "https_redirect_dg"
 	TLSIRuleName        = "tls_irule"
 	ABPathIRuleName     = "ab_deployment_path_irule"
+	// FixedResponseIRuleName responds to the requests matching the fixed response rules of the VirtualServer
+	FixedResponseIRuleName = "fixed_response_irule"
+	// fixedResponseVariable is the Tcl variable set by the LTM policy to the fixed response rule of the request
+	fixedResponseVariable = "cis_fixed_response"
+	// RegexPathIRuleName selects the pool of the regex paths
+	RegexPathIRuleName = "regex_path_irule"
+	// ErrorPagesIRuleName serves the maintenance and error pages of the VirtualServer
+	ErrorPagesIRuleName = "error_pages_irule"
 )
 
 // constants for TLS references
@@ -230,8 +291,26 @@ const AllowSourceRangeDgName = "allowSourceRange"
 // Internal data group for ab deployment routes.
 const AbDeploymentDgName = "ab_deployment_dg"
 
+// Internal data group mapping the hosts to the pools and the regex paths.
+const RegexPathDgName = "regex_path_dg"
+
+// Internal data group mapping the fixed response rules to the status code, content type and body of the response.
+const FixedResponseDgName = "fixed_response_dg"
+
+// Internal data group mapping the hosts and the status codes to the maintenance and error pages.
+const ErrorPagesDgName = "error_pages_dg"
+
+// DefaultRedirectStatusCode is the status code of the redirect rules of VirtualServer if not set
+const DefaultRedirectStatusCode = 302
+
+// DefaultMaintenanceStatusCode is the status code of the maintenance page of VirtualServer if not set
+const DefaultMaintenanceStatusCode = 503
+
 const BigIPLabel = ""
 
+// maxSynRtoBase is the highest initial SYN retransmission timeout in milliseconds of the TCP profile
+const maxSynRtoBase = 5000
+
 // const CmDocumentApi = "/api/v1/spaces/default/appsvcs/documents/"
 
 const CmDeclareApi = "/api/v1/spaces/default/appsvcs/declare"
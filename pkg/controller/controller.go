@@ -17,6 +17,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -56,7 +57,11 @@ func RunController(params Params) *Controller {
 	ctlr.addInformers()
 
 	// Start Sync CM token Manager
-	go ctlr.CMTokenManager.Start(make(chan struct{}), tokenmanager.CMRefreshTokenExpiration)
+	go ctlr.CMTokenManager.Start(ctlr.stopCh, tokenmanager.CMRefreshTokenExpiration)
+
+	// watch the CM credentials and trusted certificates for rotation
+	go ctlr.CMTokenManager.WatchCredentials(ctlr.stopCh)
+	ctlr.setupTrustedCertsInformer(params.CMTrustedCertsCfgMap)
 
 	// start request handler
 	ctlr.RequestHandler.startRequestHandler()
@@ -71,12 +76,22 @@ func RunController(params Params) *Controller {
 
 	// setup postmanager for bigip label
 	for bigip, _ := range ctlr.bigIpConfigMap {
-		ctlr.RequestHandler.startPostManager(bigip)
+		if err := ctlr.startPostManager(bigip); err != nil {
+			log.Errorf("[INIT] Unable to start post manager for BIG-IP %v: %v", bigip.BigIpAddress, err)
+			delete(ctlr.bigIpConfigMap, bigip)
+		}
 	}
 
 	// enable http endpoint
 	go ctlr.enableHttpEndpoint(params.HttpAddress)
 
+	// run the leader election of the CIS replicas
+	if params.LeaderElection != nil {
+		if err := ctlr.startLeaderElection(params.LeaderElection); err != nil {
+			log.Fatalf("[INIT] Unable to start the leader election: %v", err)
+		}
+	}
+
 	// setup ipam
 	ctlr.setupIPAM(params)
 	stopChan := make(chan struct{})
@@ -88,10 +103,10 @@ func RunController(params Params) *Controller {
 // NewController creates a new Controller Instance.
 func NewController(params Params, statusManager statusmanager.StatusManagerInterface) *Controller {
 
+	logConfig := newLogConfigManager()
 	ctlr := &Controller{
 		resources:             NewResourceStore(),
 		UseNodeInternal:       params.UseNodeInternal,
-		initState:             true,
 		defaultRouteDomain:    params.DefaultRouteDomain,
 		multiClusterConfigs:   clustermanager.NewMultiClusterConfig(),
 		multiClusterResources: newMultiClusterResourceStore(),
@@ -99,20 +114,42 @@ func NewController(params Params, statusManager statusmanager.StatusManagerInter
 		clusterRatio:          make(map[string]*int),
 		clusterAdminState:     make(map[string]cisapiv1.AdminState),
 		respChan:              make(chan *agentConfig, 1),
-		CMTokenManager: tokenmanager.NewTokenManager(
-			params.CMConfigDetails.URL,
-			tokenmanager.Credentials{Username: params.CMConfigDetails.UserName, Password: params.CMConfigDetails.Password},
-			params.CMTrustedCerts,
-			params.CMSSLInsecure,
-			statusManager),
 		managedResources: ManagedResources{
 			ManageCustomResources: true,
 			ManageTransportServer: true,
 			ManageIL:              true,
 		},
-		bigIpConfigMap: make(BigIpConfigMap),
-		PostParams:     PostParams{},
-		clientsets:     params.ClientSets,
+		bigIpConfigMap:  make(BigIpConfigMap),
+		PostParams:      PostParams{runtimeDebugAS3: logConfig.debugAS3},
+		clientsets:      params.ClientSets,
+		logConfig:       logConfig,
+		adminTokenFile:  params.AdminTokenFile,
+		shutdownTimeout: params.ShutdownTimeout,
+		stopCh:          make(chan struct{}),
+	}
+	ctlr.initState.Store(true)
+
+	var err error
+	ctlr.CMTokenManager, err = tokenmanager.NewTokenManagerWithAuth(
+		params.CMConfigDetails.URL,
+		tokenmanager.Credentials{Username: params.CMConfigDetails.UserName, Password: params.CMConfigDetails.Password},
+		tokenmanager.AuthConfig{
+			Type:           params.CMConfigDetails.AuthType,
+			ClientCertFile: params.CMConfigDetails.ClientCertFile,
+			ClientKeyFile:  params.CMConfigDetails.ClientKeyFile,
+			APIToken:       params.CMConfigDetails.APIToken,
+			TokenFile:      params.CMConfigDetails.TokenFile,
+			CredentialsDir: params.CMConfigDetails.CredentialsDir,
+		},
+		params.CMTrustedCerts,
+		params.CMSSLInsecure,
+		statusManager)
+	if err != nil {
+		log.Fatalf("[INIT] Invalid Central Manager authentication config: %v", err)
+	}
+	// the replica runs as standby until it acquires the lease of the leader election
+	if params.LeaderElection != nil {
+		ctlr.setStandby(true)
 	}
 
 	log.Debug("Controller Created")
@@ -151,6 +188,8 @@ func (ctlr *Controller) NewRequestHandler(userAgent string, httpClientMetrics bo
 		PostParams:        ctlr.PostParams,
 		httpClientMetrics: httpClientMetrics,
 	}
+	// user agent is set in the declarations created by the post managers
+	ctlr.RequestHandler.PostParams.UserAgent = userAgent
 }
 
 func (ctlr *Controller) setupIPAM(params Params) {
@@ -167,6 +206,8 @@ func (ctlr *Controller) setupIPAM(params Params) {
 		ipamClient := ipammachinery.NewIPAMClient(ipamParams)
 
 		ctlr.ipamHandler = ipmanager.NewIpamHandler(ctlr.ControllerIdentifier, params.Config, ipamClient, params.IPAMNamespace)
+		// the IPAM CR is created and updated by the leader
+		ctlr.ipamHandler.SetStandby(params.LeaderElection != nil)
 
 		ctlr.ipamHandler.RegisterIPAMCRD()
 		time.Sleep(3 * time.Second)
@@ -226,19 +267,75 @@ func (ctlr *Controller) Start(stopChan chan struct{}) {
 	ctlr.Stop()
 }
 
-// Stop the Controller
+// Stop the Controller, the queued resources are processed and the pending declarations are posted
+// and their status updated before stopping, the shutdown timeout bounds the wait
 func (ctlr *Controller) Stop() {
-	// stop the informers
+	ctx, cancel := context.WithTimeout(context.Background(), ctlr.shutdownTimeout)
+	defer cancel()
+	log.Infof("[SHUTDOWN] Stopping the controller")
+	// stop the informers so that no new events are queued
 	ctlr.stopInformers()
+	if ctlr.trustedCertsInformer != nil {
+		ctlr.trustedCertsInformer.stop()
+	}
+	ctlr.drainResourceQueue(ctx)
+	ctlr.RequestHandler.waitForPendingRequests(ctx)
+	// IPAM entries are updated by the worker and the response handler, stop it once they are done
 	if ctlr.ipamHandler != nil {
 		ctlr.ipamHandler.IpamCli.Stop()
 	}
-	// Stop the status manager
+	// release the lease once the declarations are posted so that a standby replica takes over
+	ctlr.stopLeaderElection()
+	ctlr.centralManagers.Lock()
+	for _, cm := range ctlr.centralManagers.cmMap {
+		close(cm.stopCh)
+	}
+	ctlr.centralManagers.cmMap = nil
+	ctlr.centralManagers.Unlock()
+	if ctlr.stopCh != nil {
+		close(ctlr.stopCh)
+	}
+	// Stop the status manager, it writes the pending status updates before returning
 	ctlr.CMTokenManager.StatusManager.Stop()
+	log.Infof("[SHUTDOWN] Stopped the controller")
+}
+
+// drainResourceQueue processes the queued resources and shuts down the resource queue
+func (ctlr *Controller) drainResourceQueue(ctx context.Context) {
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		// the queue doesn't accept the resources once shut down, so wait for the worker to pick the queued
+		// resources first as processing them may queue the dependent resources
+		_ = wait.PollUntilContextCancel(ctx, shutdownPollInterval, true, func(context.Context) (bool, error) {
+			return ctlr.resourceQueue.Len() == 0, nil
+		})
+		// waits for the resource being processed
+		ctlr.resourceQueue.ShutDownWithDrain()
+	}()
+	select {
+	case <-drained:
+		log.Debugf("[SHUTDOWN] Processed the queued resources")
+	case <-ctx.Done():
+		log.Warningf("[SHUTDOWN] Timed out processing the queued resources, %v resources aren't processed",
+			ctlr.resourceQueue.Len())
+		ctlr.resourceQueue.ShutDown()
+	}
 }
 
 // Set the resource count for prometheus metrics
 func (ctlr *Controller) setPrometheusResourceCount() {
 	prometheus.ManagedServices.Set(float64(len(ctlr.resources.poolMemCache)))
 	prometheus.ManagedTransportServers.Set(float64(len(ctlr.TeemData.ResourceType.TransportServer) + len(ctlr.TeemData.ResourceType.IPAMTS)))
+	// reset the pool members to remove the deleted pools
+	prometheus.PoolMembers.Reset()
+	for bigIpConfig, bigIpResourceConfig := range ctlr.resources.bigIpMap {
+		for partition, partitionConfig := range bigIpResourceConfig.ltmConfig {
+			for _, rsCfg := range partitionConfig.ResourceMap {
+				for _, pool := range rsCfg.Pools {
+					prometheus.PoolMembers.WithLabelValues(bigIpConfig.BigIpAddress, partition, pool.Name).Set(float64(len(pool.Members)))
+				}
+			}
+		}
+	}
 }
@@ -80,7 +80,7 @@ func newMockPostManger() *mockPostManager {
 		RespIndex: 0,
 	}
 	mockPM.AS3PostManager = &AS3PostManager{}
-	mockPM.AS3PostManager.firstPost = true
+	mockPM.AS3PostManager.firstPost.Store(true)
 	statusManager := mockmanager.NewMockStatusManager()
 	mockPM.tokenManager = tokenmanager.NewTokenManager(
 		"0.0.0.0",
@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"fmt"
+
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned/fake"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/statusmanager/mockmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	. "github.com/onsi/ginkgo/v2"
@@ -11,6 +14,7 @@ import (
 	"github.com/onsi/gomega/ghttp"
 	fakeRouteClient "github.com/openshift/client-go/route/clientset/versioned/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
 	"time"
 )
 
@@ -114,3 +118,80 @@ var _ = Describe("New Controller", func() {
 		stopChan <- struct{}{}
 	})
 })
+
+var _ = Describe("Graceful Shutdown", func() {
+	var mockCtlr *mockController
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		mockCtlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "custom-resource-controller")
+		mockCtlr.resources = NewResourceStore()
+		mockCtlr.requestMap = &requestMap{requestMap: make(map[cisapiv1.BigIpConfig]requestMeta)}
+		mockCtlr.TeemData = &teem.TeemsData{}
+		mockCtlr.ipamHandler = nil
+		mockCtlr.shutdownTimeout = 5 * time.Second
+		mockCtlr.stopCh = make(chan struct{})
+	})
+
+	It("Process the queued resources and wait for the pending requests on stop", func() {
+		for i := 0; i < 3; i++ {
+			mockCtlr.resourceQueue.Add(&rqKey{kind: "unknown", rscName: fmt.Sprintf("resource-%d", i)})
+		}
+		// pending request is handled after the resources are processed
+		mockCtlr.RequestHandler.pendingRequests.Add(1)
+		workerStopped := make(chan struct{})
+		go func() {
+			defer close(workerStopped)
+			for mockCtlr.processResources() {
+			}
+			mockCtlr.RequestHandler.pendingRequests.Add(-1)
+		}()
+		mockCtlr.Stop()
+		Expect(mockCtlr.resourceQueue.Len()).To(BeZero())
+		Expect(mockCtlr.resourceQueue.ShuttingDown()).To(BeTrue())
+		Expect(mockCtlr.RequestHandler.pendingRequests.Load()).To(BeZero())
+		Eventually(workerStopped).Should(BeClosed())
+		Expect(mockCtlr.stopCh).To(BeClosed(), "token manager of the central manager should be stopped")
+	})
+
+	It("Stop waiting for the pending requests after the shutdown timeout", func() {
+		mockCtlr.shutdownTimeout = 200 * time.Millisecond
+		mockCtlr.resourceQueue.Add(&rqKey{kind: "unknown"})
+		mockCtlr.RequestHandler.pendingRequests.Add(1)
+		startTime := time.Now()
+		mockCtlr.Stop()
+		Expect(time.Since(startTime)).To(BeNumerically("<", 2*time.Second))
+		Expect(mockCtlr.resourceQueue.ShuttingDown()).To(BeTrue())
+		Expect(mockCtlr.RequestHandler.pendingRequests.Load()).To(Equal(int64(1)))
+	})
+
+	It("Track the pending requests till the response is handled", func() {
+		mockCtlr.respChan = make(chan *agentConfig, 1)
+		mockCtlr.RequestHandler.respChan = mockCtlr.respChan
+		bigIpConfig := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+		mockPM := newMockPostManger()
+		mockPM.pendingRequests = &mockCtlr.RequestHandler.pendingRequests
+		mockPM.respChan = mockCtlr.respChan
+		mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpConfig] = mockPM.PostManager
+		go mockCtlr.RequestHandler.requestHandler()
+		go mockCtlr.responseHandler(mockCtlr.respChan)
+
+		// requests of the bigips without post manager aren't posted
+		mockCtlr.RequestHandler.EnqueueRequestConfig(ResourceConfigRequest{
+			bigIpConfig: cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.12"}})
+		Eventually(mockCtlr.RequestHandler.pendingRequests.Load).Should(BeZero())
+
+		// request is pending till the post manager posts it and the response handler handles it
+		mockCtlr.RequestHandler.EnqueueRequestConfig(ResourceConfigRequest{
+			bigIpConfig: bigIpConfig,
+			bigIpResourceConfig: BigIpResourceConfig{ltmConfig: LTMConfig{"test": &PartitionConfig{
+				ResourceMap: make(ResourceMap)}}},
+			reqMeta: requestMeta{id: 1},
+		})
+		var config agentConfig
+		Eventually(mockPM.postChan).Should(Receive(&config))
+		Expect(mockCtlr.RequestHandler.pendingRequests.Load()).To(Equal(int64(1)))
+		mockCtlr.respChan <- &config
+		Eventually(mockCtlr.RequestHandler.pendingRequests.Load).Should(BeZero())
+	})
+})
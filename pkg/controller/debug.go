@@ -0,0 +1,346 @@
+/*-
+ * Copyright (c) 2019-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
+	v1 "k8s.io/api/core/v1"
+)
+
+type (
+	// debugReadRequest reads and encodes the state of the resource worker on the worker goroutine
+	debugReadRequest struct {
+		read   func() interface{}
+		result chan debugReadResult
+	}
+
+	debugReadResult struct {
+		response []byte
+		err      error
+	}
+
+	// debugBigIPResources is the resource store of a bigip
+	debugBigIPResources struct {
+		BigIP      cisapiv1.BigIpConfig      `json:"bigIp"`
+		Partitions map[string]debugPartition `json:"partitions"`
+		GTM        GTMConfig                 `json:"gtm,omitempty"`
+	}
+
+	debugPartition struct {
+		Priority  *int                           `json:"priority,omitempty"`
+		Resources map[string]debugResourceConfig `json:"resources"`
+	}
+
+	// debugResourceConfig is the ResourceConfig with the maps keyed by the name references flattened
+	debugResourceConfig struct {
+		Virtual        Virtual                          `json:"virtual"`
+		Pools          Pools                            `json:"pools,omitempty"`
+		Policies       Policies                         `json:"policies,omitempty"`
+		Monitors       []Monitor                        `json:"monitors,omitempty"`
+		ServiceAddress []ServiceAddress                 `json:"serviceAddress,omitempty"`
+		IRules         map[string]*IRule                `json:"iRules,omitempty"`
+		DataGroups     map[string]DataGroupNamespaceMap `json:"dataGroups,omitempty"`
+		CustomProfiles map[string]CustomProfile         `json:"customProfiles,omitempty"`
+	}
+
+	// debugPoolMembers are the pool members of a service
+	debugPoolMembers struct {
+		Service string                  `json:"service"`
+		Cluster string                  `json:"cluster,omitempty"`
+		Type    v1.ServiceType          `json:"type,omitempty"`
+		Ports   []v1.ServicePort        `json:"ports,omitempty"`
+		Members map[string][]PoolMember `json:"members"`
+	}
+
+	// debugMultiClusterState holds the services referenced by the resources and the pools of the services
+	debugMultiClusterState struct {
+		ResourceServices map[string][]debugClusterService `json:"resourceServices"`
+		ClusterServices  map[string][]debugClusterService `json:"clusterServices"`
+	}
+
+	debugClusterService struct {
+		Cluster string   `json:"cluster,omitempty"`
+		Service string   `json:"service"`
+		Port    string   `json:"port,omitempty"`
+		Pools   []string `json:"pools,omitempty"`
+	}
+
+	// debugTenantDeclaration is the last rendered and last applied declaration of a tenant
+	debugTenantDeclaration struct {
+		Rendered as3Tenant `json:"rendered,omitempty"`
+		Applied  as3Tenant `json:"applied,omitempty"`
+	}
+)
+
+// CISDebugHandler dumps the state of the controller as JSON with the secrets redacted, the state is
+// selected by the path under /debug/
+func (ctlr *Controller) CISDebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var response []byte
+		var err error
+		switch strings.TrimPrefix(r.URL.Path, debugPathPrefix) {
+		case "resources":
+			response, err = ctlr.readOnWorker(r.Context(), ctlr.getDebugResources)
+		case "pool-members":
+			response, err = ctlr.readOnWorker(r.Context(), ctlr.getDebugPoolMembers)
+		case "multicluster":
+			response, err = ctlr.readOnWorker(r.Context(), ctlr.getDebugMultiClusterState)
+		case "ipam":
+			response, err = ctlr.readOnWorker(r.Context(), ctlr.getDebugIPAMCache)
+		case "static-routes":
+			response, err = redactSecrets(ctlr.getDebugStaticRoutes())
+		case "declarations":
+			response, err = redactSecrets(ctlr.getDebugDeclarations())
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read the state: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	})
+}
+
+// readOnWorker reads and encodes the state on the resource worker through the resource queue, as the
+// stores of the worker aren't guarded by locks
+func (ctlr *Controller) readOnWorker(ctx context.Context, read func() interface{}) ([]byte, error) {
+	req := debugReadRequest{read: read, result: make(chan debugReadResult, 1)}
+	ctlr.resourceQueue.Add(&rqKey{kind: debugRead, rsc: req})
+	ctx, cancel := context.WithTimeout(ctx, debugReadTimeout)
+	defer cancel()
+	select {
+	case result := <-req.result:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the resource worker")
+	}
+}
+
+// run is called by the resource worker, the result channel is buffered so that the worker isn't blocked
+// when the request has timed out
+func (req debugReadRequest) run() {
+	response, err := redactSecrets(req.read())
+	req.result <- debugReadResult{response: response, err: err}
+}
+
+func (ctlr *Controller) getDebugResources() interface{} {
+	bigIPs := make([]debugBigIPResources, 0, len(ctlr.resources.bigIpMap))
+	for bigIpConfig, bigIpResourceConfig := range ctlr.resources.bigIpMap {
+		bigIP := debugBigIPResources{
+			BigIP:      bigIpConfig,
+			Partitions: make(map[string]debugPartition),
+			GTM:        bigIpResourceConfig.gtmConfig,
+		}
+		for partition, partitionConfig := range bigIpResourceConfig.ltmConfig {
+			partitionConfig.PriorityMutex.RLock()
+			debugPart := debugPartition{Priority: partitionConfig.Priority, Resources: make(map[string]debugResourceConfig)}
+			partitionConfig.PriorityMutex.RUnlock()
+			for name, rsCfg := range partitionConfig.ResourceMap {
+				debugPart.Resources[name] = newDebugResourceConfig(rsCfg)
+			}
+			bigIP.Partitions[partition] = debugPart
+		}
+		bigIPs = append(bigIPs, bigIP)
+	}
+	sort.Slice(bigIPs, func(i, j int) bool {
+		return bigIPs[i].BigIP.BigIpAddress < bigIPs[j].BigIP.BigIpAddress
+	})
+	return bigIPs
+}
+
+func newDebugResourceConfig(rsCfg *ResourceConfig) debugResourceConfig {
+	cfg := debugResourceConfig{
+		Virtual:        rsCfg.Virtual,
+		Pools:          rsCfg.Pools,
+		Policies:       rsCfg.Policies,
+		Monitors:       rsCfg.Monitors,
+		ServiceAddress: rsCfg.ServiceAddress,
+	}
+	for ref, rule := range rsCfg.IRulesMap {
+		if cfg.IRules == nil {
+			cfg.IRules = make(map[string]*IRule)
+		}
+		cfg.IRules[ref.Partition+"/"+ref.Name] = rule
+	}
+	for ref, dgMap := range rsCfg.IntDgMap {
+		if cfg.DataGroups == nil {
+			cfg.DataGroups = make(map[string]DataGroupNamespaceMap)
+		}
+		cfg.DataGroups[ref.Partition+"/"+ref.Name] = dgMap
+	}
+	for key, prof := range rsCfg.customProfiles {
+		if cfg.CustomProfiles == nil {
+			cfg.CustomProfiles = make(map[string]CustomProfile)
+		}
+		// only the certificates of the profile are reported, the keys are redacted
+		certs := make([]certificate, len(prof.Certificates))
+		for i, cert := range prof.Certificates {
+			certs[i] = certificate{Cert: cert.Cert, Key: redactedValue}
+		}
+		prof.Certificates = certs
+		cfg.CustomProfiles[key.ResourceName+"/"+key.Name] = prof
+	}
+	return cfg
+}
+
+func (ctlr *Controller) getDebugPoolMembers() interface{} {
+	poolMembers := make([]debugPoolMembers, 0, len(ctlr.resources.poolMemCache))
+	for svcKey, info := range ctlr.resources.poolMemCache {
+		members := debugPoolMembers{
+			Service: svcKey.namespace + "/" + svcKey.serviceName,
+			Cluster: svcKey.clusterName,
+			Members: make(map[string][]PoolMember),
+		}
+		if info != nil {
+			members.Type = info.svcType
+			members.Ports = info.portSpec
+			for port, mems := range info.memberMap {
+				members.Members[fmt.Sprintf("%v:%v", port.name, port.port)] = mems
+			}
+		}
+		poolMembers = append(poolMembers, members)
+	}
+	sort.Slice(poolMembers, func(i, j int) bool {
+		if poolMembers[i].Cluster != poolMembers[j].Cluster {
+			return poolMembers[i].Cluster < poolMembers[j].Cluster
+		}
+		return poolMembers[i].Service < poolMembers[j].Service
+	})
+	return poolMembers
+}
+
+func (ctlr *Controller) getDebugMultiClusterState() interface{} {
+	state := debugMultiClusterState{
+		ResourceServices: make(map[string][]debugClusterService),
+		ClusterServices:  make(map[string][]debugClusterService),
+	}
+	if ctlr.multiClusterResources == nil {
+		return state
+	}
+	ctlr.multiClusterResources.Lock()
+	defer ctlr.multiClusterResources.Unlock()
+	for rsRef, svcs := range ctlr.multiClusterResources.rscSvcMap {
+		key := rsRef.kind + "/" + rsRef.namespace + "/" + rsRef.name
+		for svcKey, svcCfg := range svcs {
+			state.ResourceServices[key] = append(state.ResourceServices[key], debugClusterService{
+				Cluster: svcKey.clusterName,
+				Service: svcKey.namespace + "/" + svcKey.serviceName,
+				Port:    svcCfg.svcPort.String(),
+			})
+		}
+		sortDebugClusterServices(state.ResourceServices[key])
+	}
+	for cluster, svcs := range ctlr.multiClusterResources.clusterSvcMap {
+		for svcKey, svcCfgs := range svcs {
+			for svcCfg, pools := range svcCfgs {
+				svc := debugClusterService{
+					Service: svcKey.namespace + "/" + svcKey.serviceName,
+					Port:    svcCfg.svcPort.String(),
+				}
+				for pool := range pools {
+					svc.Pools = append(svc.Pools, pool.partition+"/"+pool.poolName)
+				}
+				sort.Strings(svc.Pools)
+				state.ClusterServices[cluster] = append(state.ClusterServices[cluster], svc)
+			}
+		}
+		sortDebugClusterServices(state.ClusterServices[cluster])
+	}
+	return state
+}
+
+func sortDebugClusterServices(svcs []debugClusterService) {
+	sort.Slice(svcs, func(i, j int) bool {
+		if svcs[i].Cluster != svcs[j].Cluster {
+			return svcs[i].Cluster < svcs[j].Cluster
+		}
+		if svcs[i].Service != svcs[j].Service {
+			return svcs[i].Service < svcs[j].Service
+		}
+		return svcs[i].Port < svcs[j].Port
+	})
+}
+
+func (ctlr *Controller) getDebugIPAMCache() interface{} {
+	if ctlr.ipamHandler == nil {
+		return nil
+	}
+	return ctlr.ipamHandler.GetIPAMCacheEntries()
+}
+
+// getDebugStaticRoutes returns the static routes of each instance, the store is guarded by its lock
+func (ctlr *Controller) getDebugStaticRoutes() interface{} {
+	routes := make(map[string][]networkmanager.L3Forward)
+	if ctlr.networkManager == nil || ctlr.networkManager.L3ForwardStore == nil {
+		return routes
+	}
+	store := ctlr.networkManager.L3ForwardStore
+	store.RLock()
+	defer store.RUnlock()
+	for instanceId, routeMap := range store.InstanceStaticRoutes {
+		instanceRoutes := make([]networkmanager.L3Forward, 0, len(routeMap))
+		for _, route := range routeMap {
+			instanceRoutes = append(instanceRoutes, route)
+		}
+		sort.Slice(instanceRoutes, func(i, j int) bool {
+			return instanceRoutes[i].Name < instanceRoutes[j].Name
+		})
+		routes[instanceId] = instanceRoutes
+	}
+	return routes
+}
+
+// getDebugDeclarations returns the last rendered and applied declaration of each tenant for each bigip
+func (ctlr *Controller) getDebugDeclarations() interface{} {
+	declarations := make(map[string]map[string]debugTenantDeclaration)
+	if ctlr.RequestHandler == nil {
+		return declarations
+	}
+	ctlr.RequestHandler.PostManagers.RLock()
+	defer ctlr.RequestHandler.PostManagers.RUnlock()
+	for bigIpConfig, pm := range ctlr.RequestHandler.PostManagers.PostManagerMap {
+		tenants := make(map[string]debugTenantDeclaration)
+		pm.tenantCacheLock.RLock()
+		for tenant, decl := range pm.renderedTenantDeclMap {
+			tenants[tenant] = debugTenantDeclaration{Rendered: decl}
+		}
+		for tenant, decl := range pm.cachedTenantDeclMap {
+			tenantDecl := tenants[tenant]
+			tenantDecl.Applied = decl
+			tenants[tenant] = tenantDecl
+		}
+		pm.tenantCacheLock.RUnlock()
+		declarations[bigIpConfig.BigIpAddress] = tenants
+	}
+	return declarations
+}
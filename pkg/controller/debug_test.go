@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("Debug Endpoints", func() {
+	var mockCtlr *mockController
+	bigIpConfig := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+	BeforeEach(func() {
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("secret"), 0600)).To(Succeed())
+		mockCtlr = newMockController()
+		mockCtlr.adminTokenFile = tokenFile
+		mockCtlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "custom-resource-controller")
+		mockCtlr.resources = NewResourceStore()
+		mockCtlr.multiClusterResources = newMultiClusterResourceStore()
+	})
+	AfterEach(func() {
+		mockCtlr.resourceQueue.ShutDown()
+	})
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mockCtlr.adminHandler(mockCtlr.CISDebugHandler()).ServeHTTP(rec, req)
+		return rec
+	}
+	// processes the debug read enqueued by the request on the resource worker
+	getOnWorker := func(path string) *httptest.ResponseRecorder {
+		go mockCtlr.processResources()
+		return get(path, "secret")
+	}
+
+	It("Reject the requests without a valid token", func() {
+		Expect(get(debugPathPrefix+"declarations", "").Code).To(Equal(http.StatusUnauthorized))
+		Expect(get(debugPathPrefix+"declarations", "invalid").Code).To(Equal(http.StatusUnauthorized))
+		Expect(get(debugPathPrefix+"unknown", "secret").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("Dump the resource store with the keys redacted", func() {
+		rsCfg := &ResourceConfig{
+			Virtual: Virtual{Name: "crd_vs_172.13.14.15", Destination: "/test/172.13.14.15:80"},
+			Pools:   Pools{{Name: "pool1", Members: []PoolMember{{Address: "10.1.1.1", Port: 80}}}},
+			IRulesMap: IRulesMap{
+				NameRef{Name: "rule1", Partition: "test"}: &IRule{Name: "rule1", Code: "when HTTP_REQUEST {}"},
+			},
+			customProfiles: map[SecretKey]CustomProfile{
+				{Name: "clientssl", ResourceName: "crd_vs_172.13.14.15"}: {
+					Name: "clientssl", Certificates: []certificate{{Cert: "CERT", Key: "PRIVATE KEY"}},
+				},
+			},
+		}
+		mockCtlr.resources.bigIpMap[bigIpConfig] = BigIpResourceConfig{ltmConfig: LTMConfig{
+			"test": &PartitionConfig{ResourceMap: ResourceMap{"crd_vs_172.13.14.15": rsCfg}},
+		}}
+		rec := getOnWorker(debugPathPrefix + "resources")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("PRIVATE KEY"))
+		var bigIPs []debugBigIPResources
+		Expect(json.Unmarshal(rec.Body.Bytes(), &bigIPs)).To(Succeed())
+		Expect(bigIPs).To(HaveLen(1))
+		Expect(bigIPs[0].BigIP).To(Equal(bigIpConfig))
+		resource := bigIPs[0].Partitions["test"].Resources["crd_vs_172.13.14.15"]
+		Expect(resource.Virtual.Destination).To(Equal("/test/172.13.14.15:80"))
+		Expect(resource.Pools[0].Members[0].Address).To(Equal("10.1.1.1"))
+		Expect(resource.IRules["test/rule1"].Code).To(Equal("when HTTP_REQUEST {}"))
+		Expect(resource.CustomProfiles["crd_vs_172.13.14.15/clientssl"].Certificates).To(Equal(
+			[]certificate{{Cert: "CERT", Key: redactedValue}}))
+	})
+
+	It("Dump the pool members and multi cluster services", func() {
+		svcKey := MultiClusterServiceKey{serviceName: "svc1", namespace: "default", clusterName: "cluster2"}
+		mockCtlr.resources.poolMemCache[svcKey] = &poolMembersInfo{
+			svcType:   v1.ServiceTypeNodePort,
+			memberMap: map[portRef][]PoolMember{{name: "http", port: 80}: {{Address: "10.1.1.1", Port: 30080}}},
+		}
+		rec := getOnWorker(debugPathPrefix + "pool-members")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var members []debugPoolMembers
+		Expect(json.Unmarshal(rec.Body.Bytes(), &members)).To(Succeed())
+		Expect(members).To(Equal([]debugPoolMembers{{
+			Service: "default/svc1",
+			Cluster: "cluster2",
+			Type:    v1.ServiceTypeNodePort,
+			Members: map[string][]PoolMember{"http:80": {{Address: "10.1.1.1", Port: 30080}}},
+		}}))
+
+		rsRef := resourceRef{kind: VirtualServer, namespace: "default", name: "vs1"}
+		svcCfg := MultiClusterServiceConfig{}
+		mockCtlr.multiClusterResources.rscSvcMap[rsRef] = map[MultiClusterServiceKey]MultiClusterServiceConfig{svcKey: svcCfg}
+		mockCtlr.multiClusterResources.clusterSvcMap["cluster2"] = map[MultiClusterServiceKey]map[MultiClusterServiceConfig]map[PoolIdentifier]struct{}{
+			svcKey: {svcCfg: {PoolIdentifier{poolName: "pool1", partition: "test"}: {}}},
+		}
+		rec = getOnWorker(debugPathPrefix + "multicluster")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var state debugMultiClusterState
+		Expect(json.Unmarshal(rec.Body.Bytes(), &state)).To(Succeed())
+		Expect(state.ResourceServices["VirtualServer/default/vs1"][0].Service).To(Equal("default/svc1"))
+		Expect(state.ClusterServices["cluster2"][0].Pools).To(Equal([]string{"test/pool1"}))
+	})
+
+	It("Dump the rendered and applied declarations with the secrets redacted", func() {
+		tenant := as3Tenant{
+			"class": "Tenant",
+			"app": as3Application{
+				"class": "Application",
+				"cert":  &as3Certificate{Class: "Certificate", Certificate: "CERT", PrivateKey: "PRIVATE KEY"},
+			},
+		}
+		mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpConfig] = &PostManager{
+			cachedTenantDeclMap:   map[string]as3Tenant{"test": tenant},
+			renderedTenantDeclMap: map[string]as3Tenant{"test": tenant, "test2": {"class": "Tenant"}},
+		}
+		rec := get(debugPathPrefix+"declarations", "secret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("PRIVATE KEY"))
+		var declarations map[string]map[string]map[string]interface{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &declarations)).To(Succeed())
+		Expect(declarations["10.8.3.11"]).To(HaveKey("test"))
+		Expect(declarations["10.8.3.11"]["test"]).To(HaveKey("applied"))
+		Expect(declarations["10.8.3.11"]["test2"]).NotTo(HaveKey("applied"))
+	})
+})
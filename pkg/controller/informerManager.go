@@ -9,6 +9,7 @@ import (
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
 	"strings"
 )
 
@@ -57,7 +58,17 @@ func (ctlr *Controller) initController() {
 
 	// update the agent params
 	ctlr.PostParams.AS3Config = configCR.Spec.AS3Config
+	if ctlr.logConfig != nil {
+		if err := ctlr.logConfig.applyDeployConfig(configCR.Spec.LogConfig); err != nil {
+			log.Errorf("[LOG] Invalid log config in DeployConfig %v: %v", ctlr.CISConfigCRKey, err)
+		}
+	}
 	ctlr.PostParams.tokenManager = ctlr.CMTokenManager
+	// http client shared by the controller, the transport picks up the rotated trusted certificates
+	ctlr.PostParams.httpClient = &http.Client{
+		Transport: ctlr.CMTokenManager.Transport(),
+		Timeout:   timeoutLarge,
+	}
 	if ctlr.managedResources.ManageRoutes {
 		// initialize the processed host-path map
 		var processedHostPath ProcessedHostPath
@@ -204,7 +215,7 @@ func (ctlr *Controller) resetControllerForNamespaceLabel() {
 	ctlr.addInformers()
 	ctlr.startInformers()
 	// process the resources
-	ctlr.initState = true
+	ctlr.initState.Store(true)
 	ctlr.setInitialResourceCount()
 	// process the DeployConfig CR if present
 	if ctlr.CISConfigCRKey != "" {
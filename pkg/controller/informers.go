@@ -20,16 +20,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
 	"reflect"
+	"strings"
 	"time"
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	cisinfv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/informers/externalversions/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	routeapi "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -132,6 +135,11 @@ func (crInfr *CRInformer) start() {
 		go crInfr.ilInformer.Run(crInfr.stopCh)
 		cacheSyncs = append(cacheSyncs, crInfr.ilInformer.HasSynced)
 	}
+	if crInfr.cmInformer != nil {
+		log.Debugf("Starting configMap informer for namespace %v", crInfr.namespace)
+		go crInfr.cmInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.cmInformer.HasSynced)
+	}
 	cache.WaitForNamedCacheSync(
 		"F5 CIS Ingress Controller",
 		crInfr.stopCh,
@@ -140,7 +148,7 @@ func (crInfr *CRInformer) start() {
 }
 
 func (crInfr *CRInformer) stop() {
-	log.Debugf("Stopping  virtualServer, tlsProfile, transportServer and ingressLink informers for namespace %v", crInfr.namespace)
+	log.Debugf("Stopping  virtualServer, tlsProfile, transportServer, ingressLink and configMap informers for namespace %v", crInfr.namespace)
 	close(crInfr.stopCh)
 }
 
@@ -361,6 +369,20 @@ func (ctlr *Controller) newNamespacedCustomResourceInformer(
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 			crOptions,
 		)
+		// ConfigMaps hold the bodies of the fixed responses of the VirtualServers
+		crInf.cmInformer = cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return ctlr.clientsets.KubeClient.CoreV1().ConfigMaps(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return ctlr.clientsets.KubeClient.CoreV1().ConfigMaps(namespace).Watch(context.TODO(), options)
+				},
+			},
+			&corev1.ConfigMap{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
 	}
 
 	if ctlr.managedResources.ManageTLSProfile {
@@ -590,6 +612,17 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 		crInf.vsInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(VirtualServer, Local))
 	}
 
+	if crInf.cmInformer != nil {
+		crInf.cmInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueConfigMap(obj, Create) },
+				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedConfigMap(old, cur) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueConfigMap(obj, Delete) },
+			},
+		)
+		crInf.cmInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(ConfigMap, Local))
+	}
+
 	if crInf.tlsInformer != nil {
 		crInf.tlsInformer.AddEventHandler(
 			&cache.ResourceEventHandlerFuncs{
@@ -819,6 +852,7 @@ func (ctlr *Controller) enqueueUpdatedVirtualServer(oldObj, newObj interface{})
 		oldVS.Spec.VirtualServerHTTPSPort != newVS.Spec.VirtualServerHTTPSPort ||
 		oldVS.Spec.VirtualServerName != newVS.Spec.VirtualServerName ||
 		oldVS.Spec.Host != newVS.Spec.Host ||
+		!reflect.DeepEqual(oldVS.Spec.Hosts, newVS.Spec.Hosts) ||
 		oldVS.Spec.IPAMLabel != newVS.Spec.IPAMLabel ||
 		oldVS.Spec.HostGroup != newVS.Spec.HostGroup ||
 		oldVSPartition != newVSPartition {
@@ -1257,6 +1291,39 @@ func (ctlr *Controller) enqueueSecret(obj interface{}, event string) {
 
 }
 
+func (ctlr *Controller) enqueueConfigMap(obj interface{}, event string) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		// the ConfigMap is deleted while the informer is disconnected
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if cm, ok = tombstone.Obj.(*corev1.ConfigMap); !ok {
+			return
+		}
+	}
+	log.Debugf("Enqueueing ConfigMap: %v/%v", cm.Namespace, cm.Name)
+	key := &rqKey{
+		namespace: cm.ObjectMeta.Namespace,
+		kind:      ConfigMap,
+		rscName:   cm.ObjectMeta.Name,
+		rsc:       cm,
+		event:     event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedConfigMap(oldObj, newObj interface{}) {
+	oldCM := oldObj.(*corev1.ConfigMap)
+	newCM := newObj.(*corev1.ConfigMap)
+	// skip the resyncs and the updates of the metadata
+	if reflect.DeepEqual(oldCM.Data, newCM.Data) {
+		return
+	}
+	ctlr.enqueueConfigMap(newObj, Update)
+}
+
 func (ctlr *Controller) enqueueRoute(obj interface{}, event string) {
 	rt := obj.(*routeapi.Route)
 	log.Debugf("Enqueueing Route: %v/%v", rt.ObjectMeta.Namespace, rt.ObjectMeta.Name)
@@ -1445,6 +1512,73 @@ func (nodeInfr *NodeInformer) stop() {
 	close(nodeInfr.stopCh)
 }
 
+// setupTrustedCertsInformer creates and starts the informer for the configmap holding the CM trusted certificates
+func (ctlr *Controller) setupTrustedCertsInformer(cfgMapKey string) {
+	keys := strings.Split(cfgMapKey, "/")
+	if len(keys) != 2 {
+		return
+	}
+	ctlr.trustedCertsInformer = ctlr.newTrustedCertsInformer(keys[0], keys[1])
+	ctlr.trustedCertsInformer.start()
+}
+
+func (ctlr *Controller) newTrustedCertsInformer(namespace, name string) *CfgMapInformer {
+	resyncPeriod := 0 * time.Second
+	restClientv1 := ctlr.clientsets.KubeClient.CoreV1().RESTClient()
+	cfgMapOptions := func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+	cfgMapInf := &CfgMapInformer{
+		namespace: namespace,
+		name:      name,
+		stopCh:    make(chan struct{}),
+		cfgMapInformer: cache.NewSharedIndexInformer(
+			cache.NewFilteredListWatchFromClient(
+				restClientv1,
+				"configmaps",
+				namespace,
+				cfgMapOptions,
+			),
+			&corev1.ConfigMap{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		),
+	}
+	cfgMapInf.cfgMapInformer.AddEventHandler(
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctlr.updateTrustedCerts(obj) },
+			UpdateFunc: func(old, cur interface{}) { ctlr.updateTrustedCerts(cur) },
+		},
+	)
+	cfgMapInf.cfgMapInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(ConfigMap, Local))
+	return cfgMapInf
+}
+
+// updateTrustedCerts updates the CM trusted certificates when the trusted certs configmap changes
+func (ctlr *Controller) updateTrustedCerts(obj interface{}) {
+	cfgMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	// token manager re-login may block on retry, so don't block the informer
+	trustedCerts := tokenmanager.TrustedCertsFromConfigMapData(cfgMap.Data)
+	for _, tm := range ctlr.getCMTokenManagers() {
+		go tm.UpdateTrustedCerts(trustedCerts)
+	}
+}
+
+func (cfgMapInfr *CfgMapInformer) start() {
+	if cfgMapInfr.cfgMapInformer != nil {
+		log.Debugf("Starting configmap informer for %v/%v", cfgMapInfr.namespace, cfgMapInfr.name)
+		go cfgMapInfr.cfgMapInformer.Run(cfgMapInfr.stopCh)
+	}
+}
+
+func (cfgMapInfr *CfgMapInformer) stop() {
+	log.Debugf("Stopping configmap informer for %v/%v", cfgMapInfr.namespace, cfgMapInfr.name)
+	close(cfgMapInfr.stopCh)
+}
+
 func (ctlr *Controller) createNamespaceLabeledInformer(label string) error {
 	selector, err := createLabelSelector(label)
 	if err != nil {
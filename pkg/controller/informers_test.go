@@ -64,6 +64,16 @@ var _ = Describe("Informers Tests", func() {
 			err = mockCtlr.createNamespaceLabeledInformer("app=test")
 			Expect(err).To(BeNil(), "Failed to Create Namespace Informer")
 		})
+
+		It("Trusted Certificates ConfigMap Informer", func() {
+			mockCtlr.setupTrustedCertsInformer("invalid")
+			Expect(mockCtlr.trustedCertsInformer).To(BeNil(), "Informer created for invalid configmap")
+			mockCtlr.setupTrustedCertsInformer("kube-system/trusted-certs")
+			Expect(mockCtlr.trustedCertsInformer).ToNot(BeNil(), "Failed to Create ConfigMap Informer")
+			Expect(mockCtlr.trustedCertsInformer.namespace).To(Equal("kube-system"))
+			Expect(mockCtlr.trustedCertsInformer.name).To(Equal("trusted-certs"))
+			mockCtlr.trustedCertsInformer.stop()
+		})
 	})
 
 	Describe("Custom Resource Queueing", func() {
@@ -590,6 +600,30 @@ var _ = Describe("Informers Tests", func() {
 			Expect(mockCtlr.processResources()).To(Equal(true))
 		})
 
+		It("ConfigMap", func() {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pages", Namespace: namespace},
+				Data:       map[string]string{"404.html": "<h1>Not found</h1>"},
+			}
+			mockCtlr.enqueueConfigMap(cm, Create)
+			key, quit := mockCtlr.resourceQueue.Get()
+			Expect(key).ToNot(BeNil(), "Enqueue New ConfigMap Failed")
+			Expect(quit).To(BeFalse(), "Enqueue New ConfigMap Failed")
+			Expect(key.(*rqKey).kind).To(Equal(ConfigMap))
+			mockCtlr.resourceQueue.Done(key)
+
+			newCM := cm.DeepCopy()
+			newCM.Labels = map[string]string{"app": "test"}
+			mockCtlr.enqueueUpdatedConfigMap(cm, newCM)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "ConfigMap without changes in the data shouldn't be enqueued")
+			newCM.Data["404.html"] = "<h1>Page not found</h1>"
+			mockCtlr.enqueueUpdatedConfigMap(cm, newCM)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "Enqueue Updated ConfigMap Failed")
+
+			mockCtlr.enqueueConfigMap(cache.DeletedFinalStateUnknown{Key: "default/pages", Obj: cm}, Delete)
+			Expect(mockCtlr.processResources()).To(Equal(true))
+		})
+
 		It("Namespace", func() {
 			labels := make(map[string]string)
 			labels["app"] = "test"
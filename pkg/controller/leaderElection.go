@@ -0,0 +1,113 @@
+/*-
+ * Copyright (c) 2019-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// isLeader returns true when the leader election is disabled or the replica holds the lease,
+// only the leader posts the declarations to the central manager and writes the status
+func (ctlr *Controller) isLeader() bool {
+	return !ctlr.standby.Load()
+}
+
+// startLeaderElection runs the Lease based leader election, the replica runs as standby keeping the informers
+// and the resource store warm to take over posting once it acquires the lease
+func (ctlr *Controller) startLeaderElection(cfg *LeaderElectionConfig) error {
+	if cfg.LeaseName == "" || cfg.LeaseNamespace == "" || cfg.Identity == "" {
+		return fmt.Errorf("lease name, namespace and identity are required for the leader election")
+	}
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: cfg.LeaseName, Namespace: cfg.LeaseNamespace},
+		Client:     ctlr.clientsets.KubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: cfg.Identity},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		Name:          cfg.LeaseName,
+		LeaseDuration: cfg.LeaseDuration,
+		RenewDeadline: cfg.RenewDeadline,
+		RetryPeriod:   cfg.RetryPeriod,
+		// release the lease on shutdown so that a standby replica takes over without waiting for it to expire
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				log.Infof("[LEADER] %v acquired the lease %v/%v", cfg.Identity, cfg.LeaseNamespace, cfg.LeaseName)
+				ctlr.setStandby(false)
+				ctlr.enqueueLeaderElectedEvent()
+			},
+			OnStoppedLeading: func() {
+				ctlr.setStandby(true)
+				if ctx.Err() != nil {
+					log.Infof("[LEADER] %v released the lease %v/%v", cfg.Identity, cfg.LeaseNamespace, cfg.LeaseName)
+					return
+				}
+				// restart to rebuild the state as a standby replica, the new leader posts the declarations
+				log.Fatalf("[LEADER] %v lost the lease %v/%v", cfg.Identity, cfg.LeaseNamespace, cfg.LeaseName)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != cfg.Identity {
+					log.Infof("[LEADER] Running as standby, %v holds the lease %v/%v", identity, cfg.LeaseNamespace,
+						cfg.LeaseName)
+				}
+			},
+		},
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+	ctlr.leaderElection = &leaderElection{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(ctlr.leaderElection.done)
+		elector.Run(ctx)
+	}()
+	return nil
+}
+
+// stopLeaderElection releases the lease if held and waits for the leader election to stop
+func (ctlr *Controller) stopLeaderElection() {
+	if ctlr.leaderElection == nil {
+		return
+	}
+	ctlr.leaderElection.cancel()
+	<-ctlr.leaderElection.done
+}
+
+// setStandby updates the standby state of the controller, the status manager and the IPAM handler
+func (ctlr *Controller) setStandby(standby bool) {
+	ctlr.standby.Store(standby)
+	ctlr.CMTokenManager.StatusManager.SetStandby(standby)
+	if ctlr.ipamHandler != nil {
+		ctlr.ipamHandler.SetStandby(standby)
+	}
+}
+
+func (ctlr *Controller) enqueueLeaderElectedEvent() {
+	key := &rqKey{
+		kind: LeaderElected,
+	}
+	ctlr.resourceQueue.Add(key)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/F5Networks/f5-ipam-controller/pkg/ipammachinery"
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/ipmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("Leader Election", func() {
+	var mockCtlr *mockController
+	bigIpConfig := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		mockCtlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "custom-resource-controller")
+		mockCtlr.resources = NewResourceStore()
+		mockCtlr.requestMap = &requestMap{requestMap: make(map[cisapiv1.BigIpConfig]requestMeta)}
+		mockCtlr.TeemData = &teem.TeemsData{}
+		mockCtlr.ipamHandler = ipmanager.NewIpamHandler("test", &rest.Config{}, ipammachinery.NewFakeIPAMClient(nil, nil, nil),
+			"kube-system")
+		priority := 0
+		mockCtlr.resources.bigIpMap[bigIpConfig] = BigIpResourceConfig{ltmConfig: LTMConfig{
+			"test": &PartitionConfig{ResourceMap: ResourceMap{"crd_vs_172.13.14.15": &ResourceConfig{}}, Priority: &priority},
+		}, gtmConfig: make(GTMConfig)}
+	})
+	AfterEach(func() {
+		mockCtlr.resourceQueue.ShutDown()
+	})
+
+	It("Post the declarations only on the leader", func() {
+		mockCtlr.setStandby(true)
+		Expect(mockCtlr.isLeader()).To(BeFalse())
+		mockCtlr.resourceQueue.Add(&rqKey{kind: "unknown"})
+		Expect(mockCtlr.processResources()).To(BeTrue())
+		Expect(mockCtlr.RequestHandler.reqChan).To(BeEmpty(), "standby replica shouldn't post")
+		// caches are updated by the standby replica as well
+		Expect(mockCtlr.resources.isConfigUpdated(bigIpConfig)).To(BeFalse())
+		Expect(mockCtlr.ipamHandler.GetIPAMCR()).To(BeNil(), "standby replica shouldn't create the IPAM CR")
+
+		mockCtlr.setStandby(false)
+		mockCtlr.enqueueLeaderElectedEvent()
+		Expect(mockCtlr.processResources()).To(BeTrue())
+		Expect(mockCtlr.RequestHandler.reqChan).To(HaveLen(1), "new leader should post all the configs")
+		request := <-mockCtlr.RequestHandler.reqChan
+		Expect(request.bigIpConfig).To(Equal(bigIpConfig))
+		Expect(request.bigIpResourceConfig.ltmConfig).To(HaveKey("test"))
+		Expect(mockCtlr.ipamHandler.GetIPAMCR()).NotTo(BeNil(), "new leader should create the IPAM CR")
+	})
+
+	It("Acquire and release the lease", func() {
+		kubeClient := k8sfake.NewSimpleClientset()
+		mockCtlr.clientsets.KubeClient = kubeClient
+		mockCtlr.setStandby(true)
+		Expect(mockCtlr.startLeaderElection(&LeaderElectionConfig{})).NotTo(Succeed())
+		Expect(mockCtlr.startLeaderElection(&LeaderElectionConfig{
+			LeaseName:      "k8s-bigip-ctlr",
+			LeaseNamespace: "kube-system",
+			Identity:       "cis-1",
+			LeaseDuration:  3 * time.Second,
+			RenewDeadline:  2 * time.Second,
+			RetryPeriod:    100 * time.Millisecond,
+		})).To(Succeed())
+		Eventually(mockCtlr.isLeader, 5*time.Second, 50*time.Millisecond).Should(BeTrue())
+		Eventually(mockCtlr.resourceQueue.Len, time.Second, 50*time.Millisecond).Should(Equal(1))
+		key, _ := mockCtlr.resourceQueue.Get()
+		Expect(key.(*rqKey).kind).To(Equal(LeaderElected))
+		mockCtlr.resourceQueue.Done(key)
+		lease, err := kubeClient.CoordinationV1().Leases("kube-system").Get(context.TODO(), "k8s-bigip-ctlr", metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("cis-1"))
+
+		mockCtlr.stopLeaderElection()
+		Expect(mockCtlr.isLeader()).To(BeFalse())
+		lease, err = kubeClient.CoordinationV1().Leases("kube-system").Get(context.TODO(), "k8s-bigip-ctlr", metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(*lease.Spec.HolderIdentity).To(BeEmpty(), "lease should be released on shutdown")
+	})
+})
@@ -0,0 +1,200 @@
+/*-
+ * Copyright (c) 2019-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+)
+
+// loggers of the components, their log level can be changed at runtime
+var (
+	workerLog         = log.Component(logComponentWorker)
+	requestHandlerLog = log.Component(logComponentRequestHandler)
+	postManagerLog    = log.Component(logComponentPostManager)
+)
+
+func newLogConfigManager() *logConfigManager {
+	return &logConfigManager{
+		startupLogLevel: log.GetLogLevel(),
+		debugAS3:        &atomic.Bool{},
+	}
+}
+
+// validateLogConfig verifies the log levels and components of the log config and returns its duration
+func validateLogConfig(cfg cisapiv1.LogConfig) (time.Duration, error) {
+	if cfg.LogLevel != "" && log.NewLogLevel(cfg.LogLevel) == nil {
+		return 0, fmt.Errorf("invalid log level %v, allowed values are: debug, info, warning, error, critical", cfg.LogLevel)
+	}
+	for component, level := range cfg.Components {
+		switch component {
+		case logComponentWorker, logComponentRequestHandler, logComponentPostManager:
+		default:
+			return 0, fmt.Errorf("invalid component %v, allowed values are: %s, %s, %s", component,
+				logComponentWorker, logComponentRequestHandler, logComponentPostManager)
+		}
+		if log.NewLogLevel(level) == nil {
+			return 0, fmt.Errorf("invalid log level %v for component %v", level, component)
+		}
+	}
+	if cfg.Duration == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(cfg.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %v: %v", cfg.Duration, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %v, it should be positive", cfg.Duration)
+	}
+	return duration, nil
+}
+
+func isEmptyLogConfig(cfg cisapiv1.LogConfig) bool {
+	return cfg.LogLevel == "" && !cfg.DebugAS3 && len(cfg.Components) == 0
+}
+
+// apply replaces the current log config with cfg, an empty config reverts to the startup config
+func (lcm *logConfigManager) apply(cfg cisapiv1.LogConfig, source string) error {
+	duration, err := validateLogConfig(cfg)
+	if err != nil {
+		return err
+	}
+	lcm.Lock()
+	defer lcm.Unlock()
+	lcm.reset()
+	if isEmptyLogConfig(cfg) {
+		log.Infof("[LOG] Log config from %v reverted to the startup config", source)
+		return nil
+	}
+	lcm.current = *cfg.DeepCopy()
+	lcm.source = source
+	if cfg.LogLevel != "" {
+		log.SetLogLevel(*log.NewLogLevel(cfg.LogLevel))
+	}
+	for component, level := range cfg.Components {
+		log.SetComponentLogLevel(component, *log.NewLogLevel(level))
+	}
+	lcm.debugAS3.Store(cfg.DebugAS3)
+	if duration > 0 {
+		generation := lcm.generation
+		lcm.revertAt = time.Now().Add(duration)
+		lcm.revertTimer = time.AfterFunc(duration, func() {
+			lcm.Lock()
+			defer lcm.Unlock()
+			// the config has been replaced after the timer fired
+			if generation != lcm.generation {
+				return
+			}
+			lcm.reset()
+			log.Infof("[LOG] Log config from %v reverted to the startup config after %v", source, duration)
+		})
+	}
+	log.Infof("[LOG] Applied log config from %v: log level: %v, debugAS3: %v, components: %v, duration: %v",
+		source, log.GetLogLevel(), cfg.DebugAS3, cfg.Components, cfg.Duration)
+	return nil
+}
+
+// reset reverts to the startup config, the caller holds the lock
+func (lcm *logConfigManager) reset() {
+	if lcm.revertTimer != nil {
+		lcm.revertTimer.Stop()
+		lcm.revertTimer = nil
+	}
+	lcm.generation++
+	lcm.current = cisapiv1.LogConfig{}
+	lcm.source = ""
+	lcm.revertAt = time.Time{}
+	log.SetLogLevel(lcm.startupLogLevel)
+	log.ClearComponentLogLevels()
+	lcm.debugAS3.Store(false)
+}
+
+// applyDeployConfig applies the log config of the DeployConfig when it's changed, so that the config
+// reverted by the timer isn't applied again on every update of the DeployConfig
+func (lcm *logConfigManager) applyDeployConfig(cfg cisapiv1.LogConfig) error {
+	lcm.Lock()
+	if reflect.DeepEqual(lcm.deployConfigLogConfig, cfg) {
+		lcm.Unlock()
+		return nil
+	}
+	lcm.deployConfigLogConfig = *cfg.DeepCopy()
+	lcm.Unlock()
+	return lcm.apply(cfg, logConfigSourceDeployConfig)
+}
+
+// state returns the current log config
+func (lcm *logConfigManager) state() logConfigState {
+	lcm.Lock()
+	defer lcm.Unlock()
+	state := logConfigState{
+		LogLevel: log.GetLogLevel().String(),
+		DebugAS3: lcm.debugAS3.Load(),
+		Source:   lcm.source,
+	}
+	for component, level := range log.GetComponentLogLevels() {
+		if state.Components == nil {
+			state.Components = make(map[string]string)
+		}
+		state.Components[component] = level.String()
+	}
+	if !lcm.revertAt.IsZero() {
+		revertAt := lcm.revertAt
+		state.RevertAt = &revertAt
+	}
+	return state
+}
+
+// CISLogConfigHandler reports the runtime log config on GET, applies the log config in the body on PUT
+// and reverts to the startup config on DELETE
+func (ctlr *Controller) CISLogConfigHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lcm := ctlr.logConfig
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var cfg cisapiv1.LogConfig
+			decoder := json.NewDecoder(r.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&cfg); err != nil {
+				http.Error(w, fmt.Sprintf("invalid log config: %v", err), http.StatusBadRequest)
+				return
+			}
+			if err := lcm.apply(cfg, logConfigSourceAPI); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		case http.MethodDelete:
+			_ = lcm.apply(cisapiv1.LogConfig{}, logConfigSourceAPI)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		response, _ := json.Marshal(lcm.state())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	})
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runtime Log Config", func() {
+	var mockCtlr *mockController
+	var startupLevel log.LogLevel
+	BeforeEach(func() {
+		startupLevel = log.GetLogLevel()
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("secret\n"), 0600)).To(Succeed())
+		mockCtlr = newMockController()
+		mockCtlr.logConfig = newLogConfigManager()
+		mockCtlr.adminTokenFile = tokenFile
+	})
+	AfterEach(func() {
+		Expect(mockCtlr.logConfig.apply(cisapiv1.LogConfig{}, logConfigSourceAPI)).To(Succeed())
+		Expect(log.GetLogLevel()).To(Equal(startupLevel))
+	})
+
+	serve := func(method, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, logConfigPath, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mockCtlr.adminHandler(mockCtlr.CISLogConfigHandler()).ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("Reject the requests without a valid token", func() {
+		Expect(serve(http.MethodGet, "", "").Code).To(Equal(http.StatusUnauthorized))
+		Expect(serve(http.MethodGet, "", "invalid").Code).To(Equal(http.StatusUnauthorized))
+		Expect(serve(http.MethodPut, `{"logLevel":"critical"}`, "invalid").Code).To(Equal(http.StatusUnauthorized))
+		Expect(log.GetLogLevel()).To(Equal(startupLevel))
+	})
+
+	It("Change and revert the log config with the admin endpoint", func() {
+		rec := serve(http.MethodPut,
+			`{"logLevel":"error","debugAS3":true,"components":{"postmanager":"debug"},"duration":"10m"}`, "secret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var state logConfigState
+		Expect(json.Unmarshal(rec.Body.Bytes(), &state)).To(Succeed())
+		Expect(state.LogLevel).To(Equal("error"))
+		Expect(state.DebugAS3).To(BeTrue())
+		Expect(state.Components).To(Equal(map[string]string{logComponentPostManager: "debug"}))
+		Expect(state.Source).To(Equal(logConfigSourceAPI))
+		Expect(state.RevertAt).NotTo(BeNil())
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_ERROR)))
+
+		rec = serve(http.MethodGet, "", "secret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(json.Unmarshal(rec.Body.Bytes(), &state)).To(Succeed())
+		Expect(state.LogLevel).To(Equal("error"))
+
+		rec = serve(http.MethodDelete, "", "secret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		state = logConfigState{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &state)).To(Succeed())
+		Expect(state.LogLevel).To(Equal(startupLevel.String()))
+		Expect(state.DebugAS3).To(BeFalse())
+		Expect(state.Components).To(BeEmpty())
+		Expect(state.RevertAt).To(BeNil())
+	})
+
+	It("Reject the invalid log config", func() {
+		Expect(serve(http.MethodPut, `{"logLevel":"verbose"}`, "secret").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(http.MethodPut, `{"components":{"unknown":"debug"}}`, "secret").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(http.MethodPut, `{"logLevel":"debug","duration":"-1m"}`, "secret").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(http.MethodPut, `{"level":"debug"}`, "secret").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(http.MethodPost, `{}`, "secret").Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(log.GetLogLevel()).To(Equal(startupLevel))
+	})
+
+	It("Revert the log config after the duration", func() {
+		Expect(mockCtlr.logConfig.apply(cisapiv1.LogConfig{LogLevel: "critical", DebugAS3: true,
+			Duration: "100ms"}, logConfigSourceAPI)).To(Succeed())
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_CRITICAL)))
+		Expect(mockCtlr.logConfig.debugAS3.Load()).To(BeTrue())
+		Eventually(log.GetLogLevel, 2*time.Second, 20*time.Millisecond).Should(Equal(startupLevel))
+		Expect(mockCtlr.logConfig.debugAS3.Load()).To(BeFalse())
+		Expect(mockCtlr.logConfig.state().Source).To(BeEmpty())
+	})
+
+	It("Apply the log config of the DeployConfig when changed", func() {
+		cfg := cisapiv1.LogConfig{LogLevel: "critical", Duration: "100ms"}
+		Expect(mockCtlr.logConfig.applyDeployConfig(cfg)).To(Succeed())
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_CRITICAL)))
+		Expect(mockCtlr.logConfig.state().Source).To(Equal(logConfigSourceDeployConfig))
+		Eventually(log.GetLogLevel, 2*time.Second, 20*time.Millisecond).Should(Equal(startupLevel))
+		// the reverted config isn't applied again by the updates of the DeployConfig
+		Expect(mockCtlr.logConfig.applyDeployConfig(cfg)).To(Succeed())
+		Expect(log.GetLogLevel()).To(Equal(startupLevel))
+		Expect(mockCtlr.logConfig.applyDeployConfig(cisapiv1.LogConfig{LogLevel: "error"})).To(Succeed())
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_ERROR)))
+	})
+
+	It("Log the AS3 requests with the debugAS3 enabled at runtime", func() {
+		postMgr := &PostManager{AS3PostManager: &AS3PostManager{},
+			PostParams: PostParams{runtimeDebugAS3: mockCtlr.logConfig.debugAS3}}
+		Expect(postMgr.debugAS3()).To(BeFalse())
+		Expect(mockCtlr.logConfig.apply(cisapiv1.LogConfig{DebugAS3: true}, logConfigSourceAPI)).To(Succeed())
+		Expect(postMgr.debugAS3()).To(BeTrue())
+	})
+
+	It("Filter the messages with the log level of the component", func() {
+		Expect(mockCtlr.logConfig.apply(cisapiv1.LogConfig{LogLevel: "critical",
+			Components: map[string]string{logComponentPostManager: "debug"}}, logConfigSourceAPI)).To(Succeed())
+		Expect(log.GetComponentLogLevels()).To(Equal(map[string]log.LogLevel{logComponentPostManager: log.LL_DEBUG}))
+		Expect(postManagerLog.Fields()).To(HaveKeyWithValue(log.FieldComponent, logComponentPostManager))
+	})
+})
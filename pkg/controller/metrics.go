@@ -18,44 +18,211 @@ package controller
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
 	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// healthReport is the response of the /health endpoint
+	healthReport struct {
+		Status          string               `json:"status"`
+		Role            string               `json:"role,omitempty"`
+		KubeAPIServer   string               `json:"kubeAPIServer"`
+		CentralManagers []cmHealth           `json:"centralManagers"`
+		BigIPs          map[string]postState `json:"bigIps,omitempty"`
+	}
+
+	cmHealth struct {
+		URL        string `json:"url"`
+		Reachable  bool   `json:"reachable"`
+		Version    string `json:"version,omitempty"`
+		TokenValid bool   `json:"tokenValid"`
+		Error      string `json:"error,omitempty"`
+	}
+
+	// cmHealthCache holds the last reachability check of the central managers
+	cmHealthCache struct {
+		sync.Mutex
+		cmHealths []cmHealth
+		checked   time.Time
+	}
 )
 
 func (ctlr *Controller) enableHttpEndpoint(httpAddress string) {
 	// Expose Prometheus metrics
 	http.Handle("/metrics", promhttp.Handler())
 	bigIPPrometheus.RegisterMetrics(ctlr.RequestHandler.httpClientMetrics, ctlr.CMTokenManager.ServerURL)
-	// Expose cis health endpoint
+	// Expose cis health endpoints
 	http.Handle("/health", ctlr.CISHealthCheckHandler())
+	http.Handle("/livez", ctlr.CISLivenessHandler())
+	http.Handle("/readyz", ctlr.CISReadinessHandler())
+	// the admin and debug endpoints are enabled only with the bearer token to authenticate the requests
+	if ctlr.adminTokenFile != "" {
+		http.Handle(logConfigPath, ctlr.adminHandler(ctlr.CISLogConfigHandler()))
+		http.Handle(debugPathPrefix, ctlr.adminHandler(ctlr.CISDebugHandler()))
+	}
 	log.Fatal(http.ListenAndServe(httpAddress, nil).Error())
 }
 
+// adminHandler serves the requests authenticated with the bearer token of the admin token file, the file is
+// read for every request so that the token can be rotated without restarting CIS
+func (ctlr *Controller) adminHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := os.ReadFile(ctlr.adminTokenFile)
+		if err != nil {
+			log.Errorf("Unable to read the admin token file: %v", err)
+		}
+		expected := strings.TrimSpace(string(token))
+		auth := r.Header.Get("Authorization")
+		given := strings.TrimPrefix(auth, "Bearer ")
+		if expected == "" || given == auth || subtle.ConstantTimeCompare([]byte(given), []byte(expected)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CISHealthCheckHandler reports the reachability of kube-api server and central managers, the detailed report with
+// the state of the last post for each bigip and the role of the replica is returned in JSON with the verbose query
 func (ctlr *Controller) CISHealthCheckHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if ctlr.clientsets.KubeClient != nil {
-			var response string
-			// Check if kube-api server is reachable
-			_, err := ctlr.clientsets.KubeClient.Discovery().RESTClient().Get().AbsPath(clusterHealthPath).DoRaw(context.TODO())
-			if err != nil {
-				response = "kube-api server is not reachable."
+		report := healthReport{Status: Ok, KubeAPIServer: Ok}
+		// role of the replica is reported when the leader election is enabled
+		if ctlr.leaderElection != nil {
+			report.Role = leaderRole
+			if !ctlr.isLeader() {
+				report.Role = standbyRole
 			}
-			// TODO Check if Central Manager is reachable
-			// Check if big-ip server is reachable
-			//_, _, _, err2 := ctlr.Agent.GetBigipAS3Version()
-			//if err2 != nil {
-			//	response = response + "big-ip server is not reachable."
-			//}
-			// if err2 == nil && err == nil {
-			if err == nil {
-				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(Ok))
-			} else {
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(response))
+		}
+		if err := ctlr.checkKubeAPIServer(r.Context()); err != nil {
+			report.Status = "kube-api server is not reachable."
+			report.KubeAPIServer = err.Error()
+		}
+		report.CentralManagers = ctlr.checkCentralManagers(r.Context())
+		for _, cm := range report.CentralManagers {
+			if !cm.Reachable || !cm.TokenValid {
+				report.Status = "Central Manager is not reachable."
 			}
 		}
+		report.BigIPs = ctlr.getLastPostStates()
+		// plain status is returned by default to keep the existing probes and scripts working
+		response := []byte(report.Status)
+		if r.URL.Query().Has("verbose") {
+			response, _ = json.Marshal(report)
+			w.Header().Set("Content-Type", "application/json")
+		}
+		if report.Status == Ok {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write(response)
+	})
+}
+
+// CISLivenessHandler reports that the CIS process is running and serving requests
+func (ctlr *Controller) CISLivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(Ok))
 	})
 }
+
+// CISReadinessHandler reports CIS ready once the initial sync of resources is done and central managers are reachable
+func (ctlr *Controller) CISReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response string
+		// initState is reset once the resources existing at startup are processed
+		if ctlr.initState.Load() {
+			response = "initial sync of resources is in progress."
+		}
+		for _, cm := range ctlr.getCMReadiness(r.Context()) {
+			if !cm.Reachable || !cm.TokenValid {
+				response = response + fmt.Sprintf("Central Manager %v is not reachable.", cm.URL)
+			}
+		}
+		if response == "" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(Ok))
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(response))
+		}
+	})
+}
+
+func (ctlr *Controller) checkKubeAPIServer(ctx context.Context) error {
+	if ctlr.clientsets.KubeClient == nil {
+		return fmt.Errorf("kube client is not initialized")
+	}
+	_, err := ctlr.clientsets.KubeClient.Discovery().RESTClient().Get().AbsPath(clusterHealthPath).DoRaw(ctx)
+	return err
+}
+
+// checkCentralManagers fetches the version of the central managers to check that they are reachable
+func (ctlr *Controller) checkCentralManagers(ctx context.Context) []cmHealth {
+	if ctlr.CMTokenManager == nil {
+		return nil
+	}
+	tokenManagers := ctlr.getCMTokenManagers()
+	cmHealths := make([]cmHealth, len(tokenManagers))
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, tm := range tokenManagers {
+		wg.Add(1)
+		// check the central managers in parallel, so that an unreachable one doesn't delay the others
+		go func(i int, tm *tokenmanager.TokenManager) {
+			defer wg.Done()
+			health := cmHealth{URL: tm.ServerURL, TokenValid: tm.HasValidToken()}
+			version, err := tm.GetCMVersionWithContext(ctx)
+			if err != nil {
+				health.Error = err.Error()
+			} else {
+				health.Reachable = true
+				health.Version = version
+			}
+			cmHealths[i] = health
+		}(i, tm)
+	}
+	wg.Wait()
+	return cmHealths
+}
+
+// getCMReadiness returns the reachability of the central managers checked within the readinessCacheTTL, so that
+// the frequent readiness probes of the replicas don't query the central managers on every probe
+func (ctlr *Controller) getCMReadiness(ctx context.Context) []cmHealth {
+	ctlr.cmReadiness.Lock()
+	defer ctlr.cmReadiness.Unlock()
+	if !ctlr.cmReadiness.checked.IsZero() && time.Since(ctlr.cmReadiness.checked) < readinessCacheTTL {
+		return ctlr.cmReadiness.cmHealths
+	}
+	ctlr.cmReadiness.cmHealths = ctlr.checkCentralManagers(ctx)
+	ctlr.cmReadiness.checked = time.Now()
+	return ctlr.cmReadiness.cmHealths
+}
+
+// getLastPostStates returns the state of the last post for each bigip
+func (ctlr *Controller) getLastPostStates() map[string]postState {
+	postStates := make(map[string]postState)
+	if ctlr.RequestHandler == nil {
+		return postStates
+	}
+	ctlr.RequestHandler.PostManagers.RLock()
+	defer ctlr.RequestHandler.PostManagers.RUnlock()
+	for bigIpConfig, pm := range ctlr.RequestHandler.PostManagers.PostManagerMap {
+		postStates[bigIpConfig.BigIpAddress] = pm.getLastPost()
+	}
+	return postStates
+}
@@ -1,31 +1,35 @@
 package controller
 
 import (
+	"encoding/json"
 	"fmt"
+	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"io/ioutil"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"net/http"
+	"net/http/httptest"
 	"time"
 )
 
 var _ = Describe("Metrics", func() {
 	var mockCtlr *mockController
+	var server *ghttp.Server
 	BeforeEach(func() {
 		mockCtlr = newMockController()
-	})
-	It("Enable the metrics without http", func() {
-		// Create a mock Kubernetes API server
-		server := ghttp.NewServer()
-		defer server.Close()
-		server.AppendHandlers(
-			ghttp.CombineHandlers(
-				ghttp.VerifyRequest("GET", "/readyz"),
-				ghttp.RespondWithJSONEncoded(http.StatusOK, Ok),
-			))
+		// Create a mock Kubernetes API server and Central Manager
+		server = ghttp.NewServer()
+		server.RouteToHandler("GET", clusterHealthPath, ghttp.RespondWithJSONEncoded(http.StatusOK, Ok))
+		server.RouteToHandler("GET", tokenmanager.CMVersionURL,
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{"version": "BIG-IP-Next-CentralManager-20.3.0-0.1"}))
 		// Override the base URL of the client to point to the mock server
 		config := &rest.Config{
 			Host: server.URL(),
@@ -33,14 +37,159 @@ var _ = Describe("Metrics", func() {
 		client, err := kubernetes.NewForConfig(config)
 		Expect(err).NotTo(HaveOccurred())
 		mockCtlr.clientsets.KubeClient = client
+		mockCtlr.CMTokenManager = tokenmanager.NewTokenManager(server.URL(),
+			tokenmanager.Credentials{Username: "admin", Password: "admin"}, "", true, mockCtlr.CMTokenManager.StatusManager)
+		mockCtlr.CMTokenManager.SetAccessToken("token")
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Enable the metrics without http", func() {
+		mockCtlr.RequestHandler.PostManagers.PostManagerMap[cisapiv1.BigIpConfig{BigIpAddress: "10.10.10.1"}] = &PostManager{
+			lastPost: postState{StatusCode: http.StatusOK}}
 		go mockCtlr.enableHttpEndpoint("0.0.0.0:8080")
 		time.Sleep(3 * time.Second)
 		resp, err := makeHTTPRequest("http://0.0.0.0:8080/health")
 		Expect(err).To(BeNil())
 		Expect(resp).To(Equal(Ok))
+		resp, err = makeHTTPRequest("http://0.0.0.0:8080/health?verbose")
+		Expect(err).To(BeNil())
+		var report healthReport
+		Expect(json.Unmarshal([]byte(resp), &report)).To(Succeed())
+		Expect(report.Status).To(Equal(Ok))
+		Expect(report.CentralManagers).To(HaveLen(1))
+		Expect(report.CentralManagers[0].Reachable).To(BeTrue())
+		Expect(report.CentralManagers[0].TokenValid).To(BeTrue())
+		Expect(report.CentralManagers[0].Version).To(Equal("20.3.0"))
+		Expect(report.BigIPs["10.10.10.1"].StatusCode).To(Equal(http.StatusOK))
+		resp, err = makeHTTPRequest("http://0.0.0.0:8080/livez")
+		Expect(err).To(BeNil())
+		Expect(resp).To(Equal(Ok))
+	})
+
+	It("Report unhealthy when Central Manager is not reachable", func() {
+		server.RouteToHandler("GET", tokenmanager.CMVersionURL, ghttp.RespondWith(http.StatusUnauthorized, ""))
+		rec := httptest.NewRecorder()
+		mockCtlr.CISHealthCheckHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(Equal("Central Manager is not reachable."))
+		rec = httptest.NewRecorder()
+		mockCtlr.CISHealthCheckHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health?verbose", nil))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		var report healthReport
+		Expect(json.Unmarshal(rec.Body.Bytes(), &report)).To(Succeed())
+		Expect(report.KubeAPIServer).To(Equal(Ok))
+		Expect(report.CentralManagers[0].Reachable).To(BeFalse())
+		Expect(report.CentralManagers[0].Error).ToNot(BeEmpty())
+	})
+
+	It("Report ready after initial sync with Central Manager reachable", func() {
+		mockCtlr.initState.Store(true)
+		rec := httptest.NewRecorder()
+		mockCtlr.CISReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(ContainSubstring("initial sync"))
+
+		mockCtlr.initState.Store(false)
+		rec = httptest.NewRecorder()
+		mockCtlr.CISReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		server.RouteToHandler("GET", tokenmanager.CMVersionURL, ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+		// reachability of the central manager is cached for the readiness probes
+		rec = httptest.NewRecorder()
+		mockCtlr.CISReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		mockCtlr.cmReadiness.checked = time.Now().Add(-readinessCacheTTL)
+		rec = httptest.NewRecorder()
+		mockCtlr.CISReadinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(rec.Body.String()).To(ContainSubstring("Central Manager " + server.URL() + " is not reachable"))
 	})
 })
 
+var _ = Describe("Pipeline Metrics", func() {
+	It("Update the post metrics of tenants", func() {
+		cfg := &as3Config{
+			targetAddress: "10.10.10.10",
+			tenantResponseMap: map[string]tenantResponse{
+				"tenant1": {agentResponseCode: http.StatusOK},
+				"tenant2": {agentResponseCode: http.StatusUnprocessableEntity},
+			},
+			failedTenants: map[string]struct{}{"tenant2": {}},
+		}
+		updatePostMetrics(cfg, 2*time.Second)
+		Expect(getCounterValue(bigIPPrometheus.TenantPosts.WithLabelValues("10.10.10.10", "tenant1", bigIPPrometheus.PostSuccess))).To(Equal(1.0))
+		Expect(getCounterValue(bigIPPrometheus.TenantPosts.WithLabelValues("10.10.10.10", "tenant2", bigIPPrometheus.PostFailure))).To(Equal(1.0))
+		Expect(getGaugeValue(bigIPPrometheus.LastSuccessfulPost.WithLabelValues("10.10.10.10"))).To(BeZero())
+
+		cfg.failedTenants = map[string]struct{}{}
+		updatePostMetrics(cfg, time.Second)
+		Expect(getCounterValue(bigIPPrometheus.TenantPosts.WithLabelValues("10.10.10.10", "tenant2", bigIPPrometheus.PostSuccess))).To(Equal(1.0))
+		Expect(getGaugeValue(bigIPPrometheus.LastSuccessfulPost.WithLabelValues("10.10.10.10"))).To(BeNumerically(">", 0))
+	})
+
+	It("Update the IPAM allocation metrics", func() {
+		ipam := &ficV1.IPAM{
+			Spec: ficV1.IPAMSpec{HostSpecs: []*ficV1.HostSpec{{Key: "ns/vs1"}, {Key: "ns/vs2"}, {Key: "ns/vs3"}}},
+			Status: ficV1.IPAMStatus{IPStatus: []*ficV1.IPSpec{
+				{Key: "ns/vs1", IP: "10.1.1.1"},
+				{Key: "ns/vs2"},
+			}},
+		}
+		setIPAMMetrics(ipam)
+		Expect(getGaugeValue(bigIPPrometheus.IPAMAllocations.WithLabelValues(bigIPPrometheus.IPAMAllocated))).To(Equal(1.0))
+		Expect(getGaugeValue(bigIPPrometheus.IPAMAllocations.WithLabelValues(bigIPPrometheus.IPAMFailed))).To(Equal(1.0))
+		Expect(getGaugeValue(bigIPPrometheus.IPAMAllocations.WithLabelValues(bigIPPrometheus.IPAMPending))).To(Equal(1.0))
+	})
+})
+
+var _ = Describe("Configuration Warnings", func() {
+	It("Add and clear the configuration warnings of the resource", func() {
+		bigIPPrometheus.ConfigurationWarnings.Reset()
+		bigIPPrometheus.AddConfigurationWarning(VirtualServer, "default", "vs1", "TLSProfile tls1 does not exist")
+		bigIPPrometheus.AddConfigurationWarning(VirtualServer, "default", "vs1", "Service default/svc1 not found")
+		bigIPPrometheus.AddConfigurationWarning(VirtualServer, "default", "vs2", "Service default/svc2 not found")
+		Expect(countMetrics(bigIPPrometheus.ConfigurationWarnings)).To(Equal(3))
+		bigIPPrometheus.ClearConfigurationWarnings(VirtualServer, "default", "vs1")
+		Expect(countMetrics(bigIPPrometheus.ConfigurationWarnings)).To(Equal(1))
+		Expect(getGaugeValue(bigIPPrometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, "default", "vs2",
+			"Service default/svc2 not found"))).To(Equal(1.0))
+		bigIPPrometheus.ClearConfigurationWarnings(VirtualServer, "default", "vs2")
+		Expect(countMetrics(bigIPPrometheus.ConfigurationWarnings)).To(BeZero())
+	})
+
+	It("Add configuration warning for the missing service", func() {
+		mockCtlr := newMockController()
+		bigIPPrometheus.ConfigurationWarnings.Reset()
+		mockCtlr.addMissingServiceWarning(VirtualServer, "default", "vs1", "", "svc1")
+		Expect(getGaugeValue(bigIPPrometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, "default", "vs1",
+			"Service default/svc1 not found"))).To(Equal(1.0))
+		bigIPPrometheus.ClearConfigurationWarnings(VirtualServer, "default", "vs1")
+	})
+})
+
+func countMetrics(collector prometheus.Collector) int {
+	metrics := make(chan prometheus.Metric, 10)
+	collector.Collect(metrics)
+	close(metrics)
+	return len(metrics)
+}
+
+func getCounterValue(counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	Expect(counter.Write(metric)).To(Succeed())
+	return metric.GetCounter().GetValue()
+}
+
+func getGaugeValue(gauge prometheus.Gauge) float64 {
+	metric := &dto.Metric{}
+	Expect(gauge.Write(metric)).To(Succeed())
+	return metric.GetGauge().GetValue()
+}
+
 func makeHTTPRequest(url string) (string, error) {
 	// Make the HTTP GET request
 	resp, err := http.Get(url)
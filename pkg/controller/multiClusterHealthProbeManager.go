@@ -133,7 +133,7 @@ func (ctlr *Controller) httpGetReq(request *http.Request) *http.Response {
 //coverage:ignore
 func (ctlr *Controller) probePrimaryClusterHealthStatus() {
 	for {
-		if ctlr.initState {
+		if ctlr.initState.Load() {
 			continue
 		}
 		ctlr.getPrimaryClusterHealthStatus()
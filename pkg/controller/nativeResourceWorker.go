@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -585,6 +586,12 @@ func (ctlr *Controller) prepareResourceConfigFromRoute(
 		var rules *Rules
 		if isRouteABDeployment(route) || ctlr.haModeType == Ratio {
 			rules = ctlr.prepareABRouteLTMRules(route, poolName, allowSourceRange, wafPolicy)
+		} else if getRoutePathType(route) == PathTypeRegex {
+			// the pool of the regex path is selected by the iRule instead of the LTM policy
+			ruleName := formatVirtualServerRuleName(route.Spec.Host, route.Namespace, route.Spec.Path, poolName)
+			addRegexPathRecord(rsCfg, route.Namespace, route.Spec.Host, route.Spec.Path, poolName, ruleName,
+				allowSourceRange)
+			return nil
 		} else {
 			rules = ctlr.prepareRouteLTMRules(route, poolName, allowSourceRange, wafPolicy)
 		}
@@ -615,6 +622,9 @@ func (ctlr *Controller) prepareABRouteLTMRules(
 		log.Errorf("Error configuring rule: %v", err)
 		return nil
 	}
+	if getRoutePathType(route) == PathTypeExact {
+		setExactPathCondition(rl, path)
+	}
 
 	if route.Spec.Path == appRoot || route.Spec.Path == "" {
 		redirects = append(redirects, rl)
@@ -653,6 +663,33 @@ func (ctlr *Controller) prepareABRouteLTMRules(
 	return &rls
 }
 
+// getRoutePathType returns the path type of the path-type annotation of the route, defaults to Prefix
+func getRoutePathType(route *routeapi.Route) string {
+	pathType, ok := route.Annotations[F5VsPathTypeAnnotation]
+	if !ok {
+		return PathTypePrefix
+	}
+	switch pathType {
+	case PathTypePrefix, PathTypeExact:
+		return pathType
+	case PathTypeRegex:
+		if isRouteABDeployment(route) {
+			log.Warningf("Regex path type is not supported for the route %v/%v with alternate backends, using Prefix",
+				route.Namespace, route.Name)
+			return PathTypePrefix
+		}
+		if _, err := regexp.Compile(route.Spec.Path); err != nil {
+			log.Warningf("Invalid regex path %v of the route %v/%v, using Prefix: %v", route.Spec.Path,
+				route.Namespace, route.Name, err)
+			return PathTypePrefix
+		}
+		return pathType
+	default:
+		log.Warningf("Invalid path type %v of the route %v/%v, using Prefix", pathType, route.Namespace, route.Name)
+		return PathTypePrefix
+	}
+}
+
 // prepareRouteLTMRules prepares LTM Policy rules for VirtualServer
 func (ctlr *Controller) prepareRouteLTMRules(
 	route *routeapi.Route,
@@ -688,6 +725,9 @@ func (ctlr *Controller) prepareRouteLTMRules(
 		log.Errorf("Error configuring rule: %v", err)
 		return nil
 	}
+	if getRoutePathType(route) == PathTypeExact {
+		setExactPathCondition(rl, path)
+	}
 
 	// Handle url-rewrite annotation
 	if rewritePath, ok := route.Annotations[F5VsURLRewriteAnnotation]; ok {
@@ -1065,7 +1105,7 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es cisapiv1.ExtendedSpec,
 	}
 
 	// Global config cr once gets processed even before processing other native resources
-	if ctlr.initState {
+	if ctlr.initState.Load() {
 		ctlr.resources.extdSpecMap = newExtdSpecMap
 		for rg, _ := range newExtdSpecMap {
 			if !ctlr.namespaceLabelMode {
@@ -1220,7 +1260,7 @@ func (ctlr *Controller) processRouteConfigFromLocalConfigCR(es cisapiv1.Extended
 		// creation event
 		if spec.local == nil {
 			if !reflect.DeepEqual(*(spec.global), ergc.ExtendedRouteGroupSpec) {
-				if ctlr.initState {
+				if ctlr.initState.Load() {
 					spec.local = &ergc.ExtendedRouteGroupSpec
 					return nil, true
 				}
@@ -1556,6 +1596,9 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 	message string,
 	status v1.ConditionStatus,
 ) {
+	if !ctlr.isLeader() {
+		return
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			log.Errorf("CIS recovered from the panic caused by route status update: %v\n")
@@ -1646,6 +1689,9 @@ func (ctlr *Controller) GetHostFromHostPath(hostPath string) string {
 }
 
 func (ctlr *Controller) eraseRouteAdmitStatus(rscKey string) {
+	if !ctlr.isLeader() {
+		return
+	}
 	// Fetching the latest copy of route
 	route := ctlr.fetchRoute(rscKey)
 	if route == nil {
@@ -1700,6 +1746,8 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 	defer ctlr.processedHostPath.Unlock()
 	var key string
 	routeKey := fmt.Sprintf("%s/%s", route.Namespace, route.Name)
+	// clear the warnings of the earlier validation, warnings are added again if still invalid
+	prometheus.ClearConfigurationWarnings(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name)
 	if route.Spec.Path == "/" || len(route.Spec.Path) == 0 {
 		key = route.Spec.Host + "/"
 	} else {
@@ -1710,7 +1758,7 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 		if processedRouteTimestamp.Before(&route.ObjectMeta.CreationTimestamp) {
 			message := fmt.Sprintf("Discarding route %v as other route already exposes URI %v%v and is older ", route.Name, route.Spec.Host, route.Spec.Path)
 			log.Warningf(message)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			go ctlr.updateRouteAdmitStatus(routeKey, "HostAlreadyClaimed", message, v1.ConditionFalse)
 			return false
 		}
@@ -1723,14 +1771,14 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 		if len(plcSSLProfiles.serverSSLs) == 0 && route.Spec.TLS.Termination == routeapi.TLSTerminationReencrypt {
 			message := fmt.Sprintf("Missing server SSL profile in the policy %v/%v", plcSSLProfiles.plcNamespace, plcSSLProfiles.plcName)
 			go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	case AnnotationSSLOption:
 		if _, ok := route.ObjectMeta.Annotations[F5ServerSslProfileAnnotation]; !ok && route.Spec.TLS.Termination == routeapi.TLSTerminationReencrypt {
 			message := fmt.Sprintf("Missing server SSL profile in the annotation")
 			go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	case RouteCertificateSSLOption:
@@ -1740,26 +1788,26 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 			//Invalid certificate and key
 			message := fmt.Sprintf("Invalid certificate or key for %v in route: %v", route.Spec.Host, route.ObjectMeta.Name)
 			go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	case DefaultSSLOption:
 		if ctlr.resources.baseRouteConfig.DefaultTLS.ClientSSL == "" {
 			message := fmt.Sprintf("Missing client SSL profile %s reference in the ConfigCR - BaseRouteSpec", ctlr.resources.baseRouteConfig.DefaultTLS.Reference)
 			go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 		if ctlr.resources.baseRouteConfig.DefaultTLS.ServerSSL == "" && route.Spec.TLS.Termination == routeapi.TLSTerminationReencrypt {
 			message := fmt.Sprintf("Missing server SSL profile %s reference in the ConfigCR - BaseRouteSpec", ctlr.resources.baseRouteConfig.DefaultTLS.Reference)
 			go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	default:
 		message := fmt.Sprintf("Missing certificate/key/SSL profile annotation/defaultSSL for route: %v", route.ObjectMeta.Name)
 		go ctlr.updateRouteAdmitStatus(routeKey, "ExtendedValidationFailed", message, v1.ConditionFalse)
-		prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+		prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 		return false
 	}
 
@@ -1769,14 +1817,14 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 			message := fmt.Sprintf("Discarding route %v as annotation %v is empty", route.Name, F5VsAppRootAnnotation)
 			log.Warningf(message)
 			go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 		if route.Spec.Path != "" && route.Spec.Path != "/" {
 			message := fmt.Sprintf("Invalid annotation: %v=%v can not target path for app-root annotation for route %v, skipping", F5VsAppRootAnnotation, appRootPath, route.Name)
 			log.Warningf(message)
 			go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	}
@@ -1787,11 +1835,26 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 			message := fmt.Sprintf("Discarding route %v as annotation %v is empty", route.Name, F5VsWAFPolicy)
 			log.Warningf(message)
 			go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	}
 
+	// Validate the annotations of the regex path, the pool of the regex path is selected by an iRule which doesn't
+	// apply the WAF and rewrites of the LTM policy rules
+	if route.Annotations[F5VsPathTypeAnnotation] == PathTypeRegex {
+		for _, annotation := range []string{F5VsWAFPolicy, F5VsURLRewriteAnnotation, F5VsAppRootAnnotation} {
+			if _, ok := route.Annotations[annotation]; ok {
+				message := fmt.Sprintf("Discarding route %v as annotation %v is not supported with the %v path type",
+					route.Name, annotation, PathTypeRegex)
+				log.Warningf(message)
+				go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
+				prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
+				return false
+			}
+		}
+	}
+
 	// Validate AllowSourceRange annotation
 	if sourceRange, ok := route.Annotations[F5VsAllowSourceRangeAnnotation]; ok {
 		invalidAllowSourceRange := false
@@ -1808,7 +1871,7 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 				F5VsAllowSourceRangeAnnotation)
 			log.Warningf(message)
 			go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	}
@@ -1832,7 +1895,7 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 				message := fmt.Sprintf("unable to parse annotation %v for route %v/%v", MultiClusterServicesAnnotation, route.Name, route.Namespace)
 				log.Warningf(message)
 				go ctlr.updateRouteAdmitStatus(routeKey, "InvalidAnnotation", message, v1.ConditionFalse)
-				prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+				prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 				return false
 			}
 		}
@@ -1845,11 +1908,10 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 			log.Warningf(message)
 			go ctlr.updateRouteAdmitStatus(routeKey,
 				"ServiceNotFound", message, v1.ConditionFalse)
-			prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message).Set(1)
+			prometheus.AddConfigurationWarning(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, message)
 			return false
 		}
 	}
-	prometheus.ConfigurationWarnings.WithLabelValues(Route, route.ObjectMeta.Namespace, route.ObjectMeta.Name, "").Set(0)
 	return true
 }
 
@@ -200,6 +200,25 @@ var _ = Describe("Routes", func() {
 			route = mockCtlr.fetchRoute(rskey)
 			Expect(len(route.Status.Ingress)).To(BeEquivalentTo(0), "Incorrect route admit status")
 		})
+		It("Route Path Type", func() {
+			spec1 := routeapi.RouteSpec{
+				Host: "foo.com",
+				Path: "/api/v[0-9]+",
+				To: routeapi.RouteTargetReference{
+					Kind: "Service",
+					Name: "foo",
+				},
+			}
+			route1 := test.NewRoute("route1", "1", "default", spec1, nil)
+			Expect(getRoutePathType(route1)).To(Equal(PathTypePrefix), "Incorrect default path type")
+			route1.Annotations = map[string]string{F5VsPathTypeAnnotation: PathTypeRegex}
+			Expect(getRoutePathType(route1)).To(Equal(PathTypeRegex), "Incorrect path type")
+			route1.Annotations[F5VsPathTypeAnnotation] = "Suffix"
+			Expect(getRoutePathType(route1)).To(Equal(PathTypePrefix), "Invalid path type should fall back to Prefix")
+			route1.Annotations[F5VsPathTypeAnnotation] = PathTypeRegex
+			route1.Spec.Path = "/api/v[0-9"
+			Expect(getRoutePathType(route1)).To(Equal(PathTypePrefix), "Invalid regex should fall back to Prefix")
+		})
 		It("Check Valid Route", func() {
 			var configCR *cisapiv1.DeployConfig
 			configSpec := cisapiv1.DeployConfigSpec{}
@@ -1016,6 +1035,13 @@ var _ = Describe("Routes", func() {
 			sslProfiles.clientSSLs = []string{"\\Common\\plc-clientssl"}
 			// with ssl profile added route should get processed
 			Expect(mockCtlr.checkValidRoute(route3, sslProfiles)).To(BeTrue())
+			// WAF isn't supported with the regex path
+			annotations[F5VsPathTypeAnnotation] = PathTypeRegex
+			annotations[F5VsWAFPolicy] = "/Common/WAF_Policy"
+			Expect(mockCtlr.checkValidRoute(route3, sslProfiles)).To(BeFalse())
+			delete(annotations, F5VsWAFPolicy)
+			Expect(mockCtlr.checkValidRoute(route3, sslProfiles)).To(BeTrue())
+			delete(annotations, F5VsPathTypeAnnotation)
 			Expect(mockCtlr.prepareResourceConfigFromRoute(rsCfg, route1, intstr.IntOrString{IntVal: 443}, ps)).To(BeNil())
 
 			checkSSLProfiles := func(profiles ProfileRefs, profile string, ctxt string) bool {
@@ -2380,7 +2406,7 @@ var _ = Describe("Multi Cluster with Routes", func() {
 		mockCtlr.addSecret(sct3)
 		mockCtlr.addSecret(sct4)
 		mockCtlr.addConfigCR(configCR)
-		mockCtlr.initState = false
+		mockCtlr.initState.Store(false)
 		mockCtlr.clusterRatio = make(map[string]*int)
 
 	})
@@ -2712,7 +2738,7 @@ var _ = Describe("Multi Cluster with CRD", func() {
 		mockCtlr.addSecret(sct3)
 		mockCtlr.addSecret(sct4)
 		mockCtlr.addConfigCR(configCR)
-		mockCtlr.initState = false
+		mockCtlr.initState.Store(false)
 		mockCtlr.clusterRatio = make(map[string]*int)
 		mockCtlr.clusterAdminState = make(map[string]cisapiv1.AdminState)
 	})
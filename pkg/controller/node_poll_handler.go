@@ -18,7 +18,7 @@ import (
 
 func (ctlr *Controller) SetupNodeProcessing(clusterName string) error {
 
-	if !ctlr.initState {
+	if !ctlr.initState.Load() {
 		// external cluster config is not processed in init stage before local node informer state
 		// handle static routes update after external cluster config is processed
 		// So process nodes on updates after init state
@@ -55,7 +55,7 @@ func (ctlr *Controller) ProcessNodeUpdate(obj interface{}, clusterName string) {
 		return
 	}
 	// process the node and update the all pool members for the cluster
-	if !ctlr.initState {
+	if !ctlr.initState.Load() {
 		if nodeInf, ok := ctlr.multiClusterNodeInformers[clusterName]; ok {
 			// Compare last set of nodes with new one
 			if !reflect.DeepEqual(newNodes, nodeInf.oldNodes) {
@@ -181,6 +181,10 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 	if ctlr.multiClusterMode == SecondaryCIS && ctlr.RequestHandler.PrimaryClusterHealthProbeParams.statusRunning {
 		return
 	}
+	// only the leader posts the static routes
+	if !ctlr.isLeader() {
+		return
+	}
 	// Process the nodes networking for static route configuration in clusterIp and auto mode
 	if ctlr.StaticRoutingMode && ctlr.PoolMemberType != NodePort {
 		nodes := ctlr.getNodesFromAllClusters()
@@ -336,6 +340,7 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 			}
 			staticRouteMap[l3Forward.Config] = l3Forward
 		}
+		bigIPPrometheus.StaticRoutes.Set(float64(len(staticRouteMap)))
 		if len(staticRouteMap) > 0 {
 			routeStore := make(networkmanager.RouteStore)
 			ctlr.requestMap.RLock()
@@ -18,8 +18,7 @@ package controller
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"encoding/json"
 	"fmt"
 	cisv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
@@ -27,10 +26,12 @@ import (
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
+	"sort"
 	// "strings"
 	"time"
 
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -43,6 +44,7 @@ func NewPostManager(params PostParams, partition string) *PostManager {
 		},
 		tokenManager:           params.tokenManager,
 		cachedTenantDeclMap:    make(map[string]as3Tenant),
+		renderedTenantDeclMap:  make(map[string]as3Tenant),
 		postChan:               make(chan agentConfig, 1),
 		defaultPartition:       partition,
 		tenantDeclarationIDMap: make(map[string]string),
@@ -59,15 +61,22 @@ func NewPostManager(params PostParams, partition string) *PostManager {
 func (postMgr *PostManager) postManager() {
 	for config := range postMgr.postChan {
 		// For the very first post after starting controller, need not wait to post
-		if !postMgr.AS3PostManager.firstPost && postMgr.AS3PostManager.AS3Config.PostDelayAS3 != 0 {
+		if !postMgr.AS3PostManager.firstPost.Load() && postMgr.AS3PostManager.AS3Config.PostDelayAS3 != 0 {
 			// Time (in seconds) that CIS waits to post the AS3 declaration to BIG-IP.
-			log.Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", postMgr.AS3PostManager.AS3Config.PostDelayAS3)
+			postMgr.logger(&config.as3Config).Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", postMgr.AS3PostManager.AS3Config.PostDelayAS3)
 			_ = <-time.After(time.Duration(postMgr.AS3PostManager.AS3Config.PostDelayAS3) * time.Second)
 		}
 		// Set the target address for the as3 request
 		config.as3Config.targetAddress = config.BigIpConfig.BigIpAddress
 
+		// post the tenants concurrently if enabled
+		if postMgr.postSlots != nil && len(config.as3Config.incomingTenantDeclMap) > 0 {
+			postMgr.dispatchTenantConfigs(config)
+			continue
+		}
+
 		//Handle AS3 post
+		postStartTime := time.Now()
 		postMgr.publishConfig(&config.as3Config)
 		//TODO: L3 post manger handling
 		//TODO: after post check for failed state and update retry chan
@@ -83,34 +92,119 @@ func (postMgr *PostManager) postManager() {
 		// if !postMgr.AS3Config.DocumentAPI {
 		postMgr.pollTenantStatus(&config.as3Config)
 		// }
+		updatePostMetrics(&config.as3Config, time.Since(postStartTime))
 		// notify resourceStatusUpdate response handler on successful tenant update
 		postMgr.respChan <- &config
 	}
+	postMgr.stopTenantPostManagers()
 }
 
-func (postMgr *PostManager) setupBIGIPRESTClient() {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
+// dispatchTenantConfigs splits the config per tenant and puts them on the tenant post channels,
+// so that the slow tenants don't block the other tenants
+func (postMgr *PostManager) dispatchTenantConfigs(config agentConfig) {
+	postedPartitions := make(map[string]map[string]string)
+	for tenant, tenantDecl := range config.as3Config.incomingTenantDeclMap {
+		tenantConfig := config
+		tenantConfig.as3Config = as3Config{
+			data:                  string(postMgr.AS3PostManager.createAS3Declaration(map[string]as3Tenant{tenant: tenantDecl}, postMgr.UserAgent)),
+			targetAddress:         config.as3Config.targetAddress,
+			id:                    config.as3Config.id,
+			tenantResponseMap:     map[string]tenantResponse{tenant: {}},
+			failedTenants:         make(map[string]struct{}),
+			incomingTenantDeclMap: map[string]as3Tenant{tenant: tenantDecl},
+			deleted:               config.as3Config.deleted,
+			spanContext:           config.as3Config.spanContext,
+		}
+		tenantConfig.reqMeta = requestMeta{
+			id:           config.reqMeta.id,
+			partitionMap: map[string]map[string]string{tenant: config.reqMeta.partitionMap[tenant]},
+			spanContext:  config.reqMeta.spanContext,
+		}
+		postedPartitions[tenant] = config.reqMeta.partitionMap[tenant]
+		postMgr.updatePendingRequests(1)
+		postMgr.enqueueTenantConfig(tenant, tenantConfig)
 	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(postMgr.tokenManager.TrustedCerts)
+	// notify response handler for the unchanged tenants
+	unchangedConfig := config
+	unchangedConfig.as3Config.failedTenants = make(map[string]struct{})
+	unchangedConfig.reqMeta = requestMeta{id: config.reqMeta.id, partitionMap: make(map[string]map[string]string),
+		spanContext: config.reqMeta.spanContext}
+	for partition, meta := range config.reqMeta.partitionMap {
+		if _, ok := postedPartitions[partition]; !ok {
+			unchangedConfig.reqMeta.partitionMap[partition] = meta
+		}
+	}
+	if len(unchangedConfig.reqMeta.partitionMap) > 0 {
+		postMgr.updatePendingRequests(1)
+		postMgr.respChan <- &unchangedConfig
+	}
+	// the config is pending as the tenant configs now
+	postMgr.updatePendingRequests(-1)
+}
 
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debugf("[AS3]%v No certs appended, using only system certs", postMgr.postManagerPrefix)
+// enqueueTenantConfig puts the latest config of the tenant on its post channel, pending config of the tenant is replaced
+func (postMgr *PostManager) enqueueTenantConfig(tenant string, config agentConfig) {
+	if postMgr.tenantPostChans == nil {
+		postMgr.tenantPostChans = make(map[string]chan agentConfig)
+	}
+	tenantPostChan, ok := postMgr.tenantPostChans[tenant]
+	if !ok {
+		tenantPostChan = make(chan agentConfig, 1)
+		postMgr.tenantPostChans[tenant] = tenantPostChan
+		go postMgr.tenantPostManager(tenant, tenantPostChan)
 	}
+	select {
+	case tenantPostChan <- config:
+	default:
+		// replace the pending config of the tenant with the latest one
+		select {
+		case <-tenantPostChan:
+			postMgr.logger(&config.as3Config).Debugf("[AS3]%v Replacing pending configuration of tenant %v", postMgr.postManagerPrefix, tenant)
+			postMgr.updatePendingRequests(-1)
+		default:
+		}
+		tenantPostChan <- config
+	}
+}
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: postMgr.tokenManager.SslInsecure,
-			RootCAs:            rootCAs,
-		},
+// tenantPostManager posts the configs of the tenant in order, polling of the accepted task blocks only this tenant
+func (postMgr *PostManager) tenantPostManager(tenant string, tenantPostChan chan agentConfig) {
+	for config := range tenantPostChan {
+		// wait for a free post slot of the central manager
+		postMgr.postSlots <- struct{}{}
+		postMgr.logger(&config.as3Config).Debugf("%v[AS3]%v Posting tenant %v", getRequestPrefix(config.as3Config.id), postMgr.postManagerPrefix, tenant)
+		postStartTime := time.Now()
+		postMgr.publishConfig(&config.as3Config)
+		<-postMgr.postSlots
+		postMgr.updateTenantCache(&config.as3Config)
+		postMgr.pollTenantStatus(&config.as3Config)
+		updatePostMetrics(&config.as3Config, time.Since(postStartTime))
+		// notify resourceStatusUpdate response handler on successful tenant update
+		postMgr.respChan <- &config
 	}
+}
+
+// updatePendingRequests updates the count of the requests pending with the request handler
+func (postMgr *PostManager) updatePendingRequests(delta int64) {
+	if postMgr.pendingRequests != nil {
+		postMgr.pendingRequests.Add(delta)
+	}
+}
+
+// stopTenantPostManagers stops the tenant post managers
+func (postMgr *PostManager) stopTenantPostManagers() {
+	for tenant, tenantPostChan := range postMgr.tenantPostChans {
+		close(tenantPostChan)
+		delete(postMgr.tenantPostChans, tenant)
+	}
+}
+
+func (postMgr *PostManager) setupBIGIPRESTClient() {
+	// transport of the token manager picks up the rotated trusted certificates
+	tr := postMgr.tokenManager.Transport()
 
 	if postMgr.HTTPClientMetrics {
-		log.Debug("[BIGIP] Http client instrumented with metrics!")
+		postManagerLog.Debug("[BIGIP] Http client instrumented with metrics!")
 		instrumentedRoundTripper := promhttp.InstrumentRoundTripperInFlight(prometheus.ClientInFlightGauge,
 			promhttp.InstrumentRoundTripperCounter(prometheus.ClientAPIRequestsCounter,
 				promhttp.InstrumentRoundTripperTrace(prometheus.ClientTrace,
@@ -158,7 +252,7 @@ func (postMgr *PostManager) getAS3TaskIdURL(taskId string) string {
 
 // publishConfig posts incoming configuration to BIG-IP
 func (postMgr *PostManager) publishConfig(cfg *as3Config) {
-	log.Debugf("[AS3]%v PostManager Accepted the configuration", postMgr.postManagerPrefix)
+	postMgr.logger(cfg).Debugf("[AS3]%v PostManager Accepted the configuration", postMgr.postManagerPrefix)
 	// postConfig updates the tenantResponseMap with response codes
 	// if !postMgr.AS3Config.DocumentAPI {
 	postMgr.postConfig(cfg)
@@ -168,8 +262,12 @@ func (postMgr *PostManager) publishConfig(cfg *as3Config) {
 }
 
 func (postMgr *PostManager) postConfig(cfg *as3Config) {
+	ctx, span := tracing.StartFrom(cfg.spanContext, "as3.post",
+		tracing.RequestID.Int(cfg.id),
+		tracing.BigIPAddress.String(cfg.targetAddress))
+	defer span.End()
 	// log as3 request if it's set
-	if postMgr.AS3PostManager.AS3Config.DebugAS3 {
+	if postMgr.debugAS3() {
 		postMgr.logAS3Request(cfg.data)
 	}
 	httpReqBody := bytes.NewBuffer([]byte(cfg.data))
@@ -188,43 +286,48 @@ func (postMgr *PostManager) postConfig(cfg *as3Config) {
 		}
 	}
 	cfg.as3APIURL = postMgr.getAS3APIURL(cfg.targetAddress)
+	sort.Strings(tenants)
+	span.SetAttributes(tracing.Tenants.StringSlice(tenants), tracing.HTTPURL.String(cfg.as3APIURL))
 	req, err := http.NewRequest("POST", cfg.as3APIURL, httpReqBody)
 	if err != nil {
-		log.Errorf("%v[AS3]%v Creating new HTTP request error: %v ", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err)
+		postMgr.logger(cfg).Errorf("%v[AS3]%v Creating new HTTP request error: %v ", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err)
+		tracing.SetError(span, err)
 		return
 	}
-	log.Infof("%v[AS3]%v posting request to %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, cfg.as3APIURL)
+	postMgr.logger(cfg).Infof("%v[AS3]%v posting request to %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, cfg.as3APIURL)
 	// add authorization header to the req
-	req.Header.Add("Authorization", "Bearer "+postMgr.tokenManager.GetAccessToken())
+	postMgr.tokenManager.SetAuthorizationHeader(req)
 	// add content type header to the req
 	req.Header.Add("Content-Type", "application/json")
+	tracing.InjectHeaders(ctx, req.Header)
 	httpResp, responseMap := postMgr.httpPOST(req)
+	postMgr.updateLastPost(httpResp, responseMap)
 	if httpResp == nil || responseMap == nil {
+		tracing.SetError(span, fmt.Errorf("post to %v failed", cfg.as3APIURL))
 		return
 	}
+	span.SetAttributes(tracing.HTTPStatusCode.Int(httpResp.StatusCode))
 
-	if postMgr.AS3PostManager.firstPost {
-		postMgr.AS3PostManager.firstPost = false
-	}
+	postMgr.AS3PostManager.firstPost.Store(false)
 
 	switch httpResp.StatusCode {
 	case http.StatusOK:
-		log.Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleResponseStatusOK(responseMap, cfg)
 	case http.StatusCreated, http.StatusAccepted:
-		log.Infof("%v[AS3]%v post resulted in ACCEPTED", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in ACCEPTED", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleResponseAccepted(responseMap, cfg)
 	case http.StatusMultiStatus:
-		log.Infof("%v[AS3]%v post resulted in MULTI-STATUS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in MULTI-STATUS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleMultiStatus(responseMap, cfg)
 	case http.StatusServiceUnavailable:
-		log.Infof("%v[AS3]%v post resulted in RETRY", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in RETRY", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleResponseStatusServiceUnavailable(responseMap, cfg)
 	case http.StatusNotFound:
-		log.Infof("%v[AS3]%v post resulted in FAILURE", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in FAILURE", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleResponseStatusNotFound(responseMap, cfg)
 	default:
-		log.Infof("%v[AS3]%v post resulted in FAILURE", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+		postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in FAILURE", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		postMgr.handleResponseOthers(responseMap, cfg, httpResp.StatusCode)
 	}
 }
@@ -454,28 +557,70 @@ func updateTenantDeletion(tenant string, declaration map[string]interface{}) boo
 func (postMgr *PostManager) httpPOST(request *http.Request) (*http.Response, map[string]interface{}) {
 	httpResp, err := postMgr.httpClient.Do(request)
 	if err != nil {
-		log.Errorf("[AS3]%v REST call error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v REST call error: %v ", postMgr.postManagerPrefix, err)
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
 
 	body, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		log.Errorf("[AS3]%v REST call response error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v REST call response error: %v ", postMgr.postManagerPrefix, err)
 		return nil, nil
 	}
 	var response map[string]interface{}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		log.Errorf("[AS3]%v Response body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
-		if postMgr.AS3PostManager.AS3Config.DebugAS3 {
-			log.Errorf("[AS3]%v Raw response from Big-IP: %v", postMgr.postManagerPrefix, string(body))
+		postManagerLog.Errorf("[AS3]%v Response body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
+		if postMgr.debugAS3() {
+			postManagerLog.Errorf("[AS3]%v Raw response from Big-IP: %v", postMgr.postManagerPrefix, string(body))
 		}
 		return nil, nil
 	}
 	return httpResp, response
 }
 
+// updatePostMetrics updates the duration and outcome of the post for each tenant in the config
+func updatePostMetrics(cfg *as3Config, postDuration time.Duration) {
+	if len(cfg.tenantResponseMap) == 0 {
+		return
+	}
+	for tenant := range cfg.tenantResponseMap {
+		outcome := prometheus.PostSuccess
+		if _, ok := cfg.failedTenants[tenant]; ok {
+			outcome = prometheus.PostFailure
+		}
+		prometheus.TenantPostDuration.WithLabelValues(cfg.targetAddress, tenant).Observe(postDuration.Seconds())
+		prometheus.TenantPosts.WithLabelValues(cfg.targetAddress, tenant, outcome).Inc()
+	}
+	if len(cfg.failedTenants) == 0 {
+		prometheus.LastSuccessfulPost.WithLabelValues(cfg.targetAddress).SetToCurrentTime()
+	}
+}
+
+// updateLastPost updates the state of the last declaration posted for the bigip
+func (postMgr *PostManager) updateLastPost(httpResp *http.Response, responseMap map[string]interface{}) {
+	state := postState{Time: time.Now()}
+	switch {
+	case httpResp == nil:
+		state.Error = "unable to post the declaration to Central Manager"
+	case responseMap == nil:
+		state.StatusCode = httpResp.StatusCode
+		state.Error = "invalid response from Central Manager"
+	default:
+		state.StatusCode = httpResp.StatusCode
+	}
+	postMgr.lastPostLock.Lock()
+	postMgr.lastPost = state
+	postMgr.lastPostLock.Unlock()
+}
+
+// getLastPost returns the state of the last declaration posted for the bigip
+func (postMgr *PostManager) getLastPost() postState {
+	postMgr.lastPostLock.RLock()
+	defer postMgr.lastPostLock.RUnlock()
+	return postMgr.lastPost
+}
+
 func (postMgr *PostManager) updateTenantResponseCode(code int, cfg *as3Config, tenant string, isDeleted bool) {
 	// Update status for a specific tenant if mentioned, else update the response for all tenants
 	if tenant != "" {
@@ -498,7 +643,7 @@ func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interf
 				code, ok1 := v["code"].(float64)
 				tenant, ok2 := v["tenant"].(string)
 				if ok1 && ok2 {
-					log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
+					postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 					postMgr.updateTenantResponseCode(int(code), cfg, tenant, updateTenantDeletion(tenant, declaration))
 				} else {
 					unknownResponse = true
@@ -516,7 +661,7 @@ func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interf
 	if unknownResponse {
 		bigipStatus.AS3Status = &cisv1.AS3Status{
 			Message:       UnknownResponse,
-			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", responseMap),
+			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", redactedResponse(responseMap)),
 			LastSubmitted: metav1.Now(),
 		}
 	} else {
@@ -526,7 +671,7 @@ func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interf
 		}
 	}
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &bigipStatus)
-	if postMgr.AS3PostManager.AS3Config.DebugAS3 || unknownResponse {
+	if postMgr.debugAS3() || unknownResponse {
 		postMgr.logAS3Response(responseMap)
 	}
 }
@@ -556,7 +701,7 @@ func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interf
 
 // }
 
-func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config) {
+func (postMgr *PostManager) getTenantConfigStatus(ctx context.Context, id string, cfg *as3Config) {
 	var url string
 	//if !postMgr.AS3Config.DocumentAPI {
 	url = postMgr.getAS3TaskIdURL(id)
@@ -565,12 +710,13 @@ func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config) {
 	//}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
+		postMgr.logger(cfg).Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
 		return
 	}
-	log.Debugf("[AS3]%v posting request with taskId to %v", postMgr.postManagerPrefix, url)
+	postMgr.logger(cfg).Debugf("[AS3]%v posting request with taskId to %v", postMgr.postManagerPrefix, url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", "Bearer "+postMgr.tokenManager.GetAccessToken())
+	postMgr.tokenManager.SetAuthorizationHeader(req)
+	tracing.InjectHeaders(ctx, req.Header)
 
 	httpResp, responseMap := postMgr.httpPOST(req)
 	if httpResp == nil || responseMap == nil {
@@ -605,11 +751,11 @@ func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config) {
 				// reset task id, so that any failed tenants will go to post call in the next retry
 				postMgr.updateTenantResponseCode(int(v["code"].(float64)), cfg, v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
 				if _, ok := v["response"]; ok {
-					log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"], v["response"])
+					postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"], v["response"])
 				} else {
-					log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
+					postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 				}
-				log.Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+				postMgr.logger(cfg).Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 			}
 		}
 	} else if httpResp.StatusCode != http.StatusServiceUnavailable {
@@ -631,10 +777,10 @@ func (postMgr *PostManager) handleMultiStatus(responseMap map[string]interface{}
 				if ok1 && ok2 {
 					if code != 200 {
 						postMgr.updateTenantResponseCode(int(code), cfg, tenant, false)
-						log.Errorf("%v[AS3]%v Error response from BIG-IP: code: %v --- tenant:%v --- message: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
+						postMgr.logger(cfg).Errorf("%v[AS3]%v Error response from BIG-IP: code: %v --- tenant:%v --- message: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 					} else {
 						postMgr.updateTenantResponseCode(int(code), cfg, tenant, updateTenantDeletion(tenant, declaration))
-						log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
+						postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 					}
 				} else {
 					unknownResponse = true
@@ -652,18 +798,18 @@ func (postMgr *PostManager) handleMultiStatus(responseMap map[string]interface{}
 	if unknownResponse {
 		bigipStatus.AS3Status = &cisv1.AS3Status{
 			Message:       UnknownResponse,
-			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", responseMap),
+			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", redactedResponse(responseMap)),
 			LastSubmitted: metav1.Now(),
 		}
 	} else {
 		bigipStatus.AS3Status = &cisv1.AS3Status{
 			Message:       http.StatusText(http.StatusMultiStatus),
-			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", responseMap),
+			Error:         fmt.Sprintf("Unknown response from BIG-IP: %v", redactedResponse(responseMap)),
 			LastSubmitted: metav1.Now(),
 		}
 	}
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &bigipStatus)
-	if postMgr.AS3PostManager.AS3Config.DebugAS3 || unknownResponse {
+	if postMgr.debugAS3() || unknownResponse {
 		postMgr.logAS3Response(responseMap)
 	}
 }
@@ -672,7 +818,7 @@ func (postMgr *PostManager) handleResponseAccepted(responseMap map[string]interf
 	// traverse all response results
 	if respId, ok := (responseMap["id"]).(string); ok {
 		cfg.acceptedTaskId = respId
-		log.Debugf("[AS3]%v Response from BIG-IP: code 201/202 id %v, waiting %v seconds to poll response", postMgr.postManagerPrefix, respId, timeoutMedium)
+		postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: code 201/202 id %v, waiting %v seconds to poll response", postMgr.postManagerPrefix, respId, timeoutMedium)
 	}
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false,
 		&cisv1.BigIPStatus{
@@ -696,9 +842,9 @@ func (postMgr *PostManager) handleResponseStatusServiceUnavailable(responseMap m
 	if err, ok := (responseMap["error"]).(map[string]interface{}); ok {
 		errorMsg = fmt.Sprintf("%v[AS3]%v Big-IP Responded with error code: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err["code"])
 	} else {
-		errorMsg = fmt.Sprintf("%v[AS3]%v Unknown response from BIG-IP: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, responseMap)
+		errorMsg = fmt.Sprintf("%v[AS3]%v Unknown response from BIG-IP: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, redactedResponse(responseMap))
 	}
-	log.Debugf("[AS3]%v Response from BIG-IP: BIG-IP is busy, waiting %v seconds and re-posting the declaration", postMgr.postManagerPrefix, timeoutMedium)
+	postMgr.logger(cfg).Debugf("[AS3]%v Response from BIG-IP: BIG-IP is busy, waiting %v seconds and re-posting the declaration", postMgr.postManagerPrefix, timeoutMedium)
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false,
 		&cisv1.BigIPStatus{
 			BigIPAddress: cfg.targetAddress,
@@ -717,7 +863,7 @@ func (postMgr *PostManager) handleResponseStatusNotFound(responseMap map[string]
 	if err, ok := (responseMap["error"]).(map[string]interface{}); ok {
 		errorMsg = fmt.Sprintf("%v[AS3]%v Big-IP Responded with error code: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err["code"])
 	} else {
-		errorMsg = fmt.Sprintf("Unknown response from BIG-IP: %v", responseMap)
+		errorMsg = fmt.Sprintf("Unknown response from BIG-IP: %v", redactedResponse(responseMap))
 		unknownResponse = true
 	}
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false,
@@ -729,7 +875,7 @@ func (postMgr *PostManager) handleResponseStatusNotFound(responseMap map[string]
 				LastSubmitted: metav1.Now(),
 			},
 		})
-	if postMgr.AS3PostManager.AS3Config.DebugAS3 || unknownResponse {
+	if postMgr.debugAS3() || unknownResponse {
 		postMgr.logAS3Response(responseMap)
 	}
 	postMgr.updateTenantResponseCode(http.StatusNotFound, cfg, "", false)
@@ -768,7 +914,7 @@ func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interfac
 		}
 	}
 	if errorMsg == "" && unknownResponse {
-		errorMsg = fmt.Sprintf("%v[AS3]%v Unknown response from BIG-IP: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, responseMap)
+		errorMsg = fmt.Sprintf("%v[AS3]%v Unknown response from BIG-IP: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, redactedResponse(responseMap))
 	}
 
 	postMgr.tokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false,
@@ -780,7 +926,7 @@ func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interfac
 				LastSubmitted: metav1.Now(),
 			},
 		})
-	if postMgr.AS3PostManager.AS3Config.DebugAS3 || unknownResponse {
+	if postMgr.debugAS3() || unknownResponse {
 		postMgr.logAS3Response(responseMap)
 	}
 }
@@ -789,13 +935,13 @@ func (postMgr *PostManager) GetBigipAS3Version() (string, string, string, error)
 	url := postMgr.getAS3VersionURL()
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
 		return "", "", "", err
 	}
 
-	log.Debugf("[AS3]%v posting GET BIGIP AS3 Version request on %v", postMgr.postManagerPrefix, url)
+	postManagerLog.Debugf("[AS3]%v posting GET BIGIP AS3 Version request on %v", postMgr.postManagerPrefix, url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", postMgr.tokenManager.GetAccessToken())
+	postMgr.tokenManager.SetAuthorizationHeader(req)
 
 	httpResp, responseMap := postMgr.httpReq(req)
 	if httpResp == nil || responseMap == nil {
@@ -827,13 +973,13 @@ func (postMgr *PostManager) GetBigipRegKey() (string, error) {
 	url := postMgr.getBigipRegKeyURL()
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
 		return "", err
 	}
 
-	log.Debugf("[AS3]%v Posting GET BIGIP Reg Key request on %v", postMgr.postManagerPrefix, url)
+	postManagerLog.Debugf("[AS3]%v Posting GET BIGIP Reg Key request on %v", postMgr.postManagerPrefix, url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", postMgr.tokenManager.GetAccessToken())
+	postMgr.tokenManager.SetAuthorizationHeader(req)
 
 	httpResp, responseMap := postMgr.httpReq(req)
 	if httpResp == nil || responseMap == nil {
@@ -859,13 +1005,13 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 	url := postMgr.getAS3APIURL("")
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
 		return nil, err
 	}
 
-	log.Debugf("[AS3]%v posting GET BIGIP AS3 declaration request on %v", postMgr.postManagerPrefix, url)
+	postManagerLog.Debugf("[AS3]%v posting GET BIGIP AS3 declaration request on %v", postMgr.postManagerPrefix, url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", postMgr.tokenManager.GetAccessToken())
+	postMgr.tokenManager.SetAuthorizationHeader(req)
 
 	httpResp, responseMap := postMgr.httpReq(req)
 	if httpResp == nil || responseMap == nil {
@@ -888,22 +1034,22 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[string]interface{}) {
 	httpResp, err := postMgr.httpClient.Do(request)
 	if err != nil {
-		log.Errorf("[AS3]%v REST call error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v REST call error: %v ", postMgr.postManagerPrefix, err)
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
 
 	body, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		log.Errorf("[AS3]%v REST call response error: %v ", postMgr.postManagerPrefix, err)
+		postManagerLog.Errorf("[AS3]%v REST call response error: %v ", postMgr.postManagerPrefix, err)
 		return nil, nil
 	}
 	var response map[string]interface{}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		log.Errorf("[AS3]%v Response body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
-		if postMgr.AS3PostManager.AS3Config.DebugAS3 {
-			log.Errorf("[AS3]%v Raw response from Big-IP: %v", postMgr.postManagerPrefix, string(body))
+		postManagerLog.Errorf("[AS3]%v Response body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
+		if postMgr.debugAS3() {
+			postManagerLog.Errorf("[AS3]%v Raw response from Big-IP: %v", postMgr.postManagerPrefix, string(body))
 		}
 		return nil, nil
 	}
@@ -921,69 +1067,27 @@ func (postMgr *PostManager) getBigipRegKeyURL() string {
 }
 
 func (postMgr *PostManager) logAS3Response(responseMap map[string]interface{}) {
-	// removing the certificates/privateKey from response log
-	if declaration, ok := (responseMap["declaration"]).([]interface{}); ok {
-		for _, value := range declaration {
-			if tenantMap, ok := value.(map[string]interface{}); ok {
-				for _, value2 := range tenantMap {
-					if appMap, ok := value2.(map[string]interface{}); ok {
-						for _, obj := range appMap {
-							if crt, ok := obj.(map[string]interface{}); ok {
-								if crt["class"] == "Certificate" {
-									crt["certificate"] = ""
-									crt["privateKey"] = ""
-									crt["chainCA"] = ""
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-		decl, err := json.Marshal(declaration)
-		if err != nil {
-			log.Errorf("[AS3]%v error while reading declaration from AS3 response: %v\n", postMgr.postManagerPrefix, err)
-			return
-		}
-		responseMap["declaration"] = as3Declaration(decl)
-	}
-	log.Debugf("[AS3]%v Raw response from Big-IP: %v ", postMgr.postManagerPrefix, responseMap)
+	postManagerLog.Debugf("[AS3]%v Raw response from Big-IP: %v ", postMgr.postManagerPrefix, redactedResponse(responseMap))
 }
 
 func (postMgr *PostManager) logAS3Request(cfg string) {
-	var as3Config, adc map[string]interface{}
-	err := json.Unmarshal([]byte(cfg), &as3Config)
+	// masking the privateKey, passphrase and token values from request log
+	decl, err := redactJSON([]byte(cfg))
 	if err != nil {
-		log.Errorf("[AS3]%v Request body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
-	}
-	//if !postMgr.AS3Config.DocumentAPI {
-	adc = as3Config["declaration"].(map[string]interface{})
-	//} else {
-	//	adc = as3Config
-	//}
-	for _, value := range adc {
-		if tenantMap, ok := value.(map[string]interface{}); ok {
-			for _, value2 := range tenantMap {
-				if appMap, ok := value2.(map[string]interface{}); ok {
-					for _, obj := range appMap {
-						if crt, ok := obj.(map[string]interface{}); ok {
-							if crt["class"] == "Certificate" {
-								crt["certificate"] = ""
-								crt["privateKey"] = ""
-								crt["chainCA"] = ""
-							}
-						}
-					}
-				}
-			}
-		}
+		postManagerLog.Errorf("[AS3]%v Request body unmarshal failed: %v\n", postMgr.postManagerPrefix, err)
+		return
 	}
-	decl, err := json.Marshal(as3Config)
+	postManagerLog.Debugf("[AS3]%v Unified declaration: %v\n", postMgr.postManagerPrefix, as3Declaration(decl))
+}
+
+// redactedResponse returns the AS3 response with the secret values masked, so that it can be
+// logged and reported in the status
+func redactedResponse(responseMap map[string]interface{}) as3Declaration {
+	response, err := redactSecrets(responseMap)
 	if err != nil {
-		log.Errorf("[AS3]%v Unified declaration error: %v\n", postMgr.postManagerPrefix, err)
-		return
+		return redactedValue
 	}
-	log.Debugf("[AS3]%v Unified declaration: %v\n", postMgr.postManagerPrefix, as3Declaration(decl))
+	return as3Declaration(response)
 }
 
 func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
@@ -991,6 +1095,8 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 	 Non 200 ok tenants will be added to retryTenantDeclMap map
 	 Locks to update the map will be acquired in the calling method
 	*/
+	postMgr.tenantCacheLock.Lock()
+	defer postMgr.tenantCacheLock.Unlock()
 	// re-initialize the failed tenants map
 	cfg.failedTenants = make(map[string]struct{})
 	for tenant, resp := range cfg.tenantResponseMap {
@@ -999,6 +1105,7 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 			if resp.isDeleted {
 				// Update the cache tenant map if tenant is deleted.
 				delete(postMgr.cachedTenantDeclMap, tenant)
+				delete(postMgr.renderedTenantDeclMap, tenant)
 			} else {
 				postMgr.cachedTenantDeclMap[tenant] = cfg.incomingTenantDeclMap[tenant]
 			}
@@ -1010,6 +1117,19 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 }
 
 func (postMgr *PostManager) pollTenantStatus(cfg *as3Config) {
+	if cfg.acceptedTaskId == "" {
+		return
+	}
+	pollStartTime := time.Now()
+	ctx, span := tracing.StartFrom(cfg.spanContext, "as3.task.poll",
+		tracing.RequestID.Int(cfg.id),
+		tracing.BigIPAddress.String(cfg.targetAddress),
+		tracing.AS3TaskID.String(cfg.acceptedTaskId))
+	defer func() {
+		prometheus.AS3TaskPollDuration.WithLabelValues(cfg.targetAddress).Observe(time.Since(pollStartTime).Seconds())
+		span.SetAttributes(tracing.FailedTenants.StringSlice(getSortedTenants(cfg.failedTenants)))
+		span.End()
+	}()
 	// Keep retrying until accepted tenant statuses are updated
 	// This prevents agent from unlocking and thus any incoming post requests (config changes) also need to hold on
 	for cfg.acceptedTaskId != "" {
@@ -1019,11 +1139,40 @@ func (postMgr *PostManager) pollTenantStatus(cfg *as3Config) {
 		//	<-time.After(timeoutSmall)
 		//}
 		cfg.tenantResponseMap = make(map[string]tenantResponse)
-		postMgr.getTenantConfigStatus(cfg.acceptedTaskId, cfg)
+		postMgr.getTenantConfigStatus(ctx, cfg.acceptedTaskId, cfg)
 		postMgr.updateTenantCache(cfg)
 	}
 }
 
+// logger returns the log entry with the request id, bigip and tenant of the config as fields
+func (postMgr *PostManager) logger(cfg *as3Config) *log.Entry {
+	fields := log.Fields{log.FieldRequestID: cfg.id, log.FieldBigIP: cfg.targetAddress}
+	// configs posted per tenant hold a single tenant
+	if len(cfg.incomingTenantDeclMap) == 1 {
+		for tenant := range cfg.incomingTenantDeclMap {
+			fields[log.FieldTenant] = tenant
+		}
+	}
+	return postManagerLog.WithFields(fields)
+}
+
+// debugAS3 reports whether the AS3 requests and responses are logged, as configured in the
+// DeployConfig or enabled at runtime
+func (postMgr *PostManager) debugAS3() bool {
+	return postMgr.AS3PostManager.AS3Config.DebugAS3 ||
+		(postMgr.runtimeDebugAS3 != nil && postMgr.runtimeDebugAS3.Load())
+}
+
+// getSortedTenants returns the sorted tenant names of the map
+func getSortedTenants[T any](tenantMap map[string]T) []string {
+	tenants := make([]string, 0, len(tenantMap))
+	for tenant := range tenantMap {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
 // function for returning the prefix string for request id
 func getRequestPrefix(id int) string {
 	if id == 0 {
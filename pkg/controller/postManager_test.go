@@ -1,13 +1,26 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io"
 	"net/http"
+	"strings"
 )
 
+// roundTripperFunc serves the requests of the http client with the function
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 var _ = Describe("AS3PostManager Tests", func() {
 	var mockPM *mockPostManager
 	BeforeEach(func() {
@@ -20,6 +33,89 @@ var _ = Describe("AS3PostManager Tests", func() {
 		mockPM.setupBIGIPRESTClient()
 	})
 
+	Describe("Post Tenants Concurrently", func() {
+		BeforeEach(func() {
+			mockPM.AS3PostManager.AS3Config.PostDelayAS3 = 0
+			mockPM.postSlots = make(chan struct{}, 2)
+			mockPM.respChan = make(chan *agentConfig, 2)
+		})
+
+		AfterEach(func() {
+			mockPM.stopTenantPostManagers()
+		})
+
+		It("Post the changed tenants and notify the unchanged tenants", func() {
+			tnt := "test"
+			mockPM.setResponses([]responceCtx{{
+				tenant: tnt,
+				status: http.StatusOK,
+				body:   "",
+			}}, http.MethodPost)
+			config := agentConfig{
+				as3Config: as3Config{
+					id:                    1,
+					incomingTenantDeclMap: map[string]as3Tenant{tnt: {"class": "Tenant"}},
+				},
+				reqMeta: requestMeta{id: 1, partitionMap: map[string]map[string]string{
+					tnt:         {"default/vs1": VirtualServer},
+					"unchanged": {"default/vs2": VirtualServer},
+				}},
+			}
+			mockPM.dispatchTenantConfigs(config)
+			var responses []*agentConfig
+			Eventually(func() int {
+				select {
+				case resp := <-mockPM.respChan:
+					responses = append(responses, resp)
+				default:
+				}
+				return len(responses)
+			}).Should(Equal(2))
+			for _, resp := range responses {
+				Expect(resp.reqMeta.partitionMap).To(HaveLen(1))
+				if _, ok := resp.reqMeta.partitionMap[tnt]; ok {
+					Expect(resp.as3Config.tenantResponseMap[tnt].agentResponseCode).To(BeEquivalentTo(http.StatusOK))
+					Expect(resp.as3Config.failedTenants).To(BeEmpty())
+				} else {
+					Expect(resp.reqMeta.partitionMap).To(HaveKey("unchanged"))
+				}
+			}
+			mockPM.tenantCacheLock.RLock()
+			Expect(mockPM.cachedTenantDeclMap).To(HaveKey(tnt))
+			mockPM.tenantCacheLock.RUnlock()
+		})
+
+		It("Post the tenants of the first post concurrently", func() {
+			mockPM.AS3PostManager.firstPost.Store(true)
+			tenants := []string{"test1", "test2", "test3"}
+			var responses []responceCtx
+			config := agentConfig{
+				as3Config: as3Config{id: 1, incomingTenantDeclMap: make(map[string]as3Tenant)},
+				reqMeta:   requestMeta{id: 1, partitionMap: make(map[string]map[string]string)},
+			}
+			for _, tnt := range tenants {
+				responses = append(responses, responceCtx{tenant: tnt, status: http.StatusOK})
+				config.as3Config.incomingTenantDeclMap[tnt] = as3Tenant{"class": "Tenant"}
+				config.reqMeta.partitionMap[tnt] = map[string]string{"default/" + tnt: VirtualServer}
+			}
+			mockPM.setResponses(responses, http.MethodPost)
+			mockPM.dispatchTenantConfigs(config)
+			for range tenants {
+				Eventually(mockPM.respChan).Should(Receive())
+			}
+			Expect(mockPM.AS3PostManager.firstPost.Load()).To(BeFalse())
+		})
+
+		It("Replace the pending config of the tenant", func() {
+			tenantPostChan := make(chan agentConfig, 1)
+			mockPM.tenantPostChans = map[string]chan agentConfig{"test": tenantPostChan}
+			mockPM.enqueueTenantConfig("test", agentConfig{reqMeta: requestMeta{id: 1}})
+			mockPM.enqueueTenantConfig("test", agentConfig{reqMeta: requestMeta{id: 2}})
+			Expect(tenantPostChan).To(HaveLen(1))
+			Expect((<-tenantPostChan).reqMeta.id).To(Equal(2))
+		})
+	})
+
 	Describe("Post Config and Handle Response", func() {
 		var as3Cfg as3Config
 		BeforeEach(func() {
@@ -38,11 +134,51 @@ var _ = Describe("AS3PostManager Tests", func() {
 				status: http.StatusOK,
 				body:   "",
 			}}, http.MethodPost)
-			mockPM.AS3PostManager.firstPost = false
+			mockPM.AS3PostManager.firstPost.Store(false)
 			mockPM.publishConfig(&as3Cfg)
 			Expect(as3Cfg.tenantResponseMap[tnt].agentResponseCode).To(BeEquivalentTo(http.StatusOK), "Posting Failed")
 		})
 
+		It("Log with the request fields", func() {
+			as3Cfg.id = 2
+			as3Cfg.targetAddress = "10.8.0.1"
+			as3Cfg.incomingTenantDeclMap = map[string]as3Tenant{"test": {}}
+			Expect(mockPM.logger(&as3Cfg).Fields()).To(Equal(log.Fields{
+				log.FieldRequestID: 2,
+				log.FieldBigIP:     "10.8.0.1",
+				log.FieldTenant:    "test",
+				log.FieldComponent: logComponentPostManager,
+			}))
+		})
+
+		It("Trace the post of the declaration", func() {
+			exporter := tracetest.NewInMemoryExporter()
+			shutdown := tracing.InitWithExporter(exporter)
+			defer shutdown(context.Background())
+			tnt := "test"
+			mockPM.setResponses([]responceCtx{{
+				tenant: tnt,
+				status: http.StatusOK,
+				body:   "",
+			}}, http.MethodPost)
+			_, parent := tracing.Start(context.Background(), "declaration.create")
+			as3Cfg.spanContext = parent.SpanContext()
+			as3Cfg.id = 3
+			as3Cfg.incomingTenantDeclMap = map[string]as3Tenant{tnt: {"class": "Tenant"}}
+			mockPM.publishConfig(&as3Cfg)
+			parent.End()
+
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(2))
+			Expect(spans[0].Name).To(Equal("as3.post"))
+			Expect(spans[0].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			Expect(spans[0].Attributes).To(ContainElements(
+				tracing.RequestID.Int(3),
+				tracing.Tenants.StringSlice([]string{tnt}),
+				tracing.HTTPStatusCode.Int(http.StatusOK),
+			))
+		})
+
 		It("Handle HTTP StatusOK", func() {
 			tnt := "test"
 			mockPM.setResponses([]responceCtx{{
@@ -162,18 +298,32 @@ var _ = Describe("AS3PostManager Tests", func() {
 				id:                1,
 				tenantResponseMap: make(map[string]tenantResponse),
 			}
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			mockPM.getTenantConfigStatus(context.TODO(), "100", &as3Cfg)
 			Expect(len(as3Cfg.tenantResponseMap)).To(BeZero(), "Posting Failed")
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			mockPM.getTenantConfigStatus(context.TODO(), "100", &as3Cfg)
 			Expect(len(as3Cfg.tenantResponseMap)).To(Equal(1), "Posting Failed")
 			Expect(as3Cfg.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusOK))
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			mockPM.getTenantConfigStatus(context.TODO(), "100", &as3Cfg)
 			Expect(len(as3Cfg.tenantResponseMap)).To(Equal(1), "Posting Failed")
 			Expect(as3Cfg.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusUnprocessableEntity))
 		})
 	})
 
 	Describe("BIGIP AS3 Version", func() {
+		It("Authorize the requests with the bearer token", func() {
+			mockPM.tokenManager.SetAccessToken("test.token")
+			var authHeaders []string
+			mockPM.httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				authHeaders = append(authHeaders, req.Header.Get("Authorization"))
+				return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header),
+					Body: io.NopCloser(strings.NewReader(`{"version":"v1", "release":"r1", "schemaCurrent":"test"}`))}, nil
+			})}
+			_, _, _, _ = mockPM.GetBigipAS3Version()
+			_, _ = mockPM.GetBigipRegKey()
+			_, _ = mockPM.GetAS3DeclarationFromBigIP()
+			Expect(authHeaders).To(Equal([]string{"Bearer test.token", "Bearer test.token", "Bearer test.token"}))
+		})
+
 		It("Get BIG-IP AS3 Version", func() {
 			mockPM.setResponses([]responceCtx{
 				{
@@ -262,6 +412,18 @@ var _ = Describe("AS3PostManager Tests", func() {
 			as3config := "{\"$schema\":\"https://raw.githubusercontent.com/F5Networks/f5-appsvcs-extension/master/schema/3.38.0/as3-schema-3.38.0-4.json\",\"class\":\"AS3\",\"declaration\":{\"class\":\"ADC\",\"controls\":{\"class\":\"Controls\",\"userAgent\":\"\"},\"id\":\"urn:uuid:85626792-9ee7-46bb-8fc8-4ba708cfdc1d\",\"k8s\":{\"Shared\":{\"Openshift_insecure_routes\":{\"class\":\"Endpoint_Policy\",\"rules\":[{\"name\":\"url_rewrite_rule1\",\"conditions\":[{\"type\":\"httpHeader\",\"name\":\"host\",\"event\":\"request\",\"all\":{\"values\":[\"foo.com:443\",\"foo.com\"],\"operand\":\"equals\"}},{\"name\":\"0\",\"event\":\"request\",\"pathSegment\":{\"values\":[\"foo.com\"],\"operand\":\"equals\"}},{\"name\":\"0\",\"event\":\"request\",\"path\":{\"values\":[\"foo.com\"],\"operand\":\"equals\"}},{\"type\":\"tcp\",\"event\":\"request\",\"address\":{\"values\":[\"foo.com\"]}}],\"actions\":[{\"type\":\"httpHeader\",\"event\":\"request\",\"replace\":{\"value\":\"newhost.com\",\"name\":\"host\"}}]}]},\"Openshift_secure_routes\":{\"class\":\"Endpoint_Policy\",\"rules\":[{\"name\":\"url_rewrite_rule1\",\"conditions\":[{\"type\":\"httpHeader\",\"name\":\"host\",\"event\":\"request\",\"all\":{\"values\":[\"foo.com:443\",\"foo.com\"],\"operand\":\"equals\"}},{\"name\":\"0\",\"event\":\"request\",\"pathSegment\":{\"values\":[\"foo.com\"],\"operand\":\"equals\"}},{\"name\":\"0\",\"event\":\"request\",\"path\":{\"values\":[\"foo.com\"],\"operand\":\"equals\"}},{\"type\":\"tcp\",\"event\":\"request\",\"address\":{\"values\":[\"foo.com\"]}}],\"actions\":[{\"type\":\"httpHeader\",\"event\":\"request\",\"replace\":{\"value\":\"newhost.com\",\"name\":\"host\"}}]}]},\"class\":\"Application\",\"serverssl_ca_bundle\":{\"class\":\"CA_Bundle\",\"bundle\":\"\\ncert\"},\"template\":\"shared\",\"test_clientssl\":{\"class\":\"Certificate\",\"certificate\":\"cert\",\"privateKey\":\"key\",\"chainCA\":\"ca-file\"},\"test_datagroup\":{\"records\":[{\"key\":\"test_record\",\"value\":\"/Common/serverssl\"}],\"keyDataType\":\"string\",\"class\":\"Data_Group\"},\"test_irule\":{\"class\":\"iRule\",\"iRule\":\"Dummy Code\"},\"test_monitor\":{\"class\":\"Monitor\",\"interval\":10,\"monitorType\":\"tcp\",\"targetAddress\":\"\",\"timeUntilUp\":0,\"dscp\":0,\"receive\":\"none\",\"send\":\"GET /\",\"targetPort\":0},\"test_pool\":{\"class\":\"Pool\",\"members\":[{\"addressDiscovery\":\"static\",\"serverAddresses\":[\"192.168.1.1\"],\"servicePort\":80,\"shareNodes\":true}],\"monitors\":[{\"use\":\"/k8s/Shared/test_monitor\"}]},\"test_virtual_secure\":{\"source\":\"0.0.0.0/0\",\"translateServerAddress\":true,\"translateServerPort\":true,\"class\":\"Service_HTTPS\",\"virtualAddresses\":[\"1.2.3.4\"],\"virtualPort\":443,\"snat\":\"auto\",\"clientTLS\":{\"bigip\":\"/Common/serverssl\"},\"serverTLS\":[{\"bigip\":\"/Common/clientssl\"}],\"redirect80\":false,\"pool\":\"/k8s/Shared/test_pool\"},\"test_virtual_secure_tls_client\":{\"class\":\"TLS_Client\",\"trustCA\":{\"use\":\"serverssl_ca_bundle\"}},\"test_virtual_secure_tls_server\":{\"class\":\"TLS_Server\",\"certificates\":[{\"certificate\":\"test_clientssl\"}],\"renegotiationEnabled\":false}},\"class\":\"Tenant\",\"defaultRouteDomain\":0},\"label\":\"CIS Declaration\",\"remark\":\"Auto-generated by CIS\",\"schemaVersion\":\"3.38.0\"}}"
 			mockPM.logAS3Request(as3config)
 		})
+		It("Redact the secrets from the as3 response", func() {
+			response := redactedResponse(map[string]interface{}{
+				"declaration": map[string]interface{}{"test": map[string]interface{}{"app": map[string]interface{}{
+					"cert": map[string]interface{}{"class": "Certificate", "certificate": "cert", "privateKey": "key",
+						"passphrase": map[string]interface{}{"ciphertext": "cGFzcw==", "protected": "eyJhbGciOiJkaXIifQ"}},
+				}}},
+			})
+			Expect(string(response)).NotTo(ContainSubstring(`"key"`))
+			Expect(string(response)).NotTo(ContainSubstring("cGFzcw=="))
+			Expect(string(response)).To(ContainSubstring(`"privateKey":"` + redactedValue + `"`))
+			Expect(string(response)).To(ContainSubstring(`"certificate":"cert"`))
+		})
 	})
 
 	Describe("Get BIGIP AS3 Declaration", func() {
@@ -0,0 +1,80 @@
+/*-
+ * Copyright (c) 2019-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// redactedValue replaces the values of the secret properties
+const redactedValue = "<redacted>"
+
+// secretProperties are the properties holding secrets, compared in lower case
+var secretProperties = map[string]struct{}{
+	"privatekey": {},
+	"passphrase": {},
+	"password":   {},
+	"token":      {},
+	"ciphertext": {},
+	"secret":     {},
+}
+
+// redactSecrets returns the JSON encoding of v with the values of the secret properties masked
+func redactSecrets(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return redactJSON(data)
+}
+
+// redactJSON masks the values of the secret properties in the JSON document
+func redactJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	// not escaping the HTML characters keeps the redacted values readable in the logs
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactValue(doc)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// redactValue walks the decoded JSON value and masks the values of the secret properties
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, val := range value {
+			if _, ok := secretProperties[strings.ToLower(key)]; ok && val != nil && val != "" {
+				value[key] = redactedValue
+				continue
+			}
+			value[key] = redactValue(val)
+		}
+	case []interface{}:
+		for i, val := range value {
+			value[i] = redactValue(val)
+		}
+	}
+	return v
+}
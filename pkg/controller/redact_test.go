@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redact Secrets", func() {
+	It("Mask the values of the secret properties", func() {
+		response, err := redactSecrets(map[string]interface{}{
+			"cert": map[string]interface{}{
+				"class":      "Certificate",
+				"privateKey": "KEY",
+				"passphrase": map[string]interface{}{"ciphertext": "abc", "protected": "eyJhbGciOiJkaXIiLCJlbmMiOiJub25lIn0"},
+			},
+			"members": []interface{}{map[string]interface{}{"Password": "pass", "address": "10.1.1.1"}},
+			"token":   "",
+		})
+		Expect(err).To(BeNil())
+		var doc map[string]interface{}
+		Expect(json.Unmarshal(response, &doc)).To(Succeed())
+		Expect(doc).To(Equal(map[string]interface{}{
+			"cert": map[string]interface{}{
+				"class":      "Certificate",
+				"privateKey": redactedValue,
+				"passphrase": redactedValue,
+			},
+			"members": []interface{}{map[string]interface{}{"Password": redactedValue, "address": "10.1.1.1"}},
+			"token":   "",
+		}))
+	})
+})
@@ -1,21 +1,28 @@
 package controller
 
 import (
+	"context"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"reflect"
 	"time"
 )
 
 func (req *RequestHandler) startRequestHandler() {
-	log.Debug("Starting requestHandler")
+	requestHandlerLog.Debug("Starting requestHandler")
 	// requestHandler runs as a separate go routine
 	// blocks on reqChan to get new/updated configuration to be posted to BIG-IP
 	go req.requestHandler()
 }
 
 func (req *RequestHandler) stopPostManager(key cisapiv1.BigIpConfig) {
+	// lock to not close the post channel while the failed tenants are retried on it
+	req.PostManagers.Lock()
+	defer req.PostManagers.Unlock()
 	//stop post manager
 	if pm, ok := req.PostManagers.PostManagerMap[key]; ok {
 		//close the channels to stop the post channel
@@ -27,13 +34,17 @@ func (req *RequestHandler) stopPostManager(key cisapiv1.BigIpConfig) {
 	}
 }
 
-func (req *RequestHandler) startPostManager(config cisapiv1.BigIpConfig) {
+func (req *RequestHandler) startPostManager(config cisapiv1.BigIpConfig, tm *tokenmanager.TokenManager) {
 	//start agent
 	req.PostManagers.Lock()
 	if _, ok := req.PostManagers.PostManagerMap[config]; !ok {
-		pm := NewPostManager(req.PostParams, config.DefaultPartition)
+		// post manager posts to the central manager managing the bigip
+		params := req.PostParams
+		params.tokenManager = tm
+		pm := NewPostManager(params, config.DefaultPartition)
 		pm.respChan = req.respChan
-		pm.tokenManager = req.CMTokenManager
+		pm.pendingRequests = &req.pendingRequests
+		pm.postSlots = req.getCMPostSlots(tm.ServerURL, params.AS3Config.PostConcurrency)
 		// update agent Map
 		req.PostManagers.PostManagerMap[config] = pm
 		// increase the Agent Count
@@ -42,16 +53,62 @@ func (req *RequestHandler) startPostManager(config cisapiv1.BigIpConfig) {
 	req.PostManagers.Unlock()
 }
 
+// getCMPostSlots returns the post slots of the central manager, post managers of the bigips managed by
+// the same central manager share the slots. Locks to update the map will be acquired in the calling method
+func (req *RequestHandler) getCMPostSlots(cmURL string, postConcurrency int) chan struct{} {
+	if postConcurrency <= 1 {
+		return nil
+	}
+	if req.cmPostSlots == nil {
+		req.cmPostSlots = make(map[string]chan struct{})
+	}
+	if _, ok := req.cmPostSlots[cmURL]; !ok {
+		req.cmPostSlots[cmURL] = make(chan struct{}, postConcurrency)
+	}
+	return req.cmPostSlots[cmURL]
+}
+
 func (req *RequestHandler) EnqueueRequestConfig(rsConfig ResourceConfigRequest) {
 	// Always push latest activeConfig to channel
 	// Case1: Put latest config into the channel
 	// Case2: If channel is blocked because of earlier config, pop out earlier config and push latest config
 	// Either Case1 or Case2 executes, which ensures the above
 
+	req.pendingRequests.Add(1)
 	select {
 	case req.reqChan <- rsConfig:
 	case <-time.After(3 * time.Millisecond):
+		req.pendingRequests.Add(-1)
+	}
+}
+
+// waitForPendingRequests waits till the pending requests are posted and their responses are handled
+func (req *RequestHandler) waitForPendingRequests(ctx context.Context) {
+	err := wait.PollUntilContextCancel(ctx, shutdownPollInterval, true, func(context.Context) (bool, error) {
+		return req.pendingRequests.Load() <= 0, nil
+	})
+	if err != nil {
+		requestHandlerLog.Warningf("[SHUTDOWN] Timed out posting the declarations, %v requests are pending",
+			req.pendingRequests.Load())
+		return
 	}
+	requestHandlerLog.Debugf("[SHUTDOWN] Posted the pending declarations")
+}
+
+// retryConfig puts the config of the failed tenants back on the post channel after a delay. The config is dropped
+// if the post manager is stopped or a newer config is pending on the channel, which posts the failed tenants again
+func (req *RequestHandler) retryConfig(config agentConfig) {
+	<-time.After(timeoutMedium)
+	req.PostManagers.RLock()
+	defer req.PostManagers.RUnlock()
+	if pm, ok := req.PostManagers.PostManagerMap[config.BigIpConfig]; ok {
+		select {
+		case pm.postChan <- config:
+			return
+		default:
+		}
+	}
+	req.pendingRequests.Add(-1)
 }
 
 // RequestHandler blocks on reqChan
@@ -64,7 +121,11 @@ func (req *RequestHandler) requestHandler() {
 			cfg := req.createDeclarationForBIGIP(rsConfig, pm)
 			if !reflect.DeepEqual(cfg, agentConfig{}) {
 				pm.postChan <- cfg
+			} else {
+				req.pendingRequests.Add(-1)
 			}
+		} else {
+			req.pendingRequests.Add(-1)
 		}
 		req.PostManagers.RUnlock()
 	}
@@ -72,6 +133,11 @@ func (req *RequestHandler) requestHandler() {
 
 func (req *RequestHandler) createDeclarationForBIGIP(rsConfig ResourceConfigRequest, pm *PostManager) agentConfig {
 	var agentCfg agentConfig
+	_, span := tracing.StartFrom(rsConfig.reqMeta.spanContext, "declaration.create",
+		tracing.RequestID.Int(rsConfig.reqMeta.id),
+		tracing.BigIPAddress.String(rsConfig.bigIpConfig.BigIpAddress),
+		tracing.BigIPLabel.String(rsConfig.bigIpConfig.BigIpLabel))
+	defer span.End()
 	if req.HAMode {
 		// if endPoint is not empty means, cis is running in secondary mode
 		// check if the primary cis is up and running
@@ -88,15 +154,18 @@ func (req *RequestHandler) createDeclarationForBIGIP(rsConfig ResourceConfigRequ
 		}
 		// Delete the tenant which is monitored by CIS and current request does not contain it, if it's the first post or
 		// if it's secondary CIS and primary CIS is down and statusChanged is true
-		if pm.AS3PostManager.firstPost ||
+		if pm.AS3PostManager.firstPost.Load() ||
 			(req.PrimaryClusterHealthProbeParams.EndPoint != "" && !req.PrimaryClusterHealthProbeParams.statusRunning &&
 				req.PrimaryClusterHealthProbeParams.statusChanged) {
 			currentConfig, err := pm.GetAS3DeclarationFromBigIP()
 			if err != nil {
-				log.Errorf("[AS3] Could not fetch the latest AS3 declaration from BIG-IP")
+				requestHandlerLog.WithFields(log.Fields{
+					log.FieldRequestID: rsConfig.reqMeta.id,
+					log.FieldBigIP:     rsConfig.bigIpConfig.BigIpAddress,
+				}).Errorf("[AS3] Could not fetch the latest AS3 declaration from BIG-IP")
 			}
 			removeDeletedTenantsForBigIP(&rsConfig.bigIpResourceConfig, pm.defaultPartition, currentConfig, pm.defaultPartition)
-			pm.AS3PostManager.firstPost = false
+			pm.AS3PostManager.firstPost.Store(false)
 		}
 	}
 	//for each request config create AS3, L3 declaration
@@ -105,6 +174,10 @@ func (req *RequestHandler) createDeclarationForBIGIP(rsConfig ResourceConfigRequ
 	if len(rsConfig.bigIpResourceConfig.ltmConfig) == 0 {
 		as3cfg.deleted = true
 	}
+	// post pipeline continues the trace from the declaration span
+	as3cfg.spanContext = span.SpanContext()
+	rsConfig.reqMeta.spanContext = span.SpanContext()
+	span.SetAttributes(tracing.Tenants.StringSlice(getSortedTenants(as3cfg.incomingTenantDeclMap)))
 	// TODO : Create the L3 declaration for the bigip
 	agentCfg = agentConfig{
 		id:          rsConfig.reqMeta.id,
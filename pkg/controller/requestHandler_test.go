@@ -6,11 +6,13 @@ import (
 	"strings"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -718,6 +720,132 @@ var _ = Describe("Backend Tests", func() {
 			svc.addPersistenceMethod("pm2")
 			Expect(svc.PersistenceMethods).To(Equal(&[]as3MultiTypeParam{as3ResourcePointer{BigIP: "pm2"}}))
 		})
+		It("Creates the profiles for the connection limits of the virtual", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.15"
+			rsCfg.Virtual.IpProtocol = "tcp"
+			rsCfg.Virtual.RateLimit = 1000
+			rsCfg.Virtual.IdleTimeout = 300
+			rsCfg.Virtual.ConnectTimeout = 7
+			rsCfg.MetaData.baseResources = map[string]string{"default/vs1": VirtualServer}
+			app := as3Application{}
+			createServiceDecl(rsCfg, app, "test")
+			svc := app["crd_vs_172.13.14.15"].(*as3Service)
+			Expect(svc.RateLimit).To(BeEquivalentTo(1000))
+			Expect(svc.ProfileTCP).To(Equal(&as3ResourcePointer{
+				Use: "/test/crd_vs_172.13.14.15/crd_vs_172.13.14.15_tcp_profile"}))
+			Expect(app["crd_vs_172.13.14.15_tcp_profile"]).To(Equal(&as3ProtocolProfile{Class: "TCP_Profile",
+				IdleTimeout: 300, SynRtoBase: 2333, SynMaxRetrans: 1}))
+
+			// TCP profile of the policy takes precedence over the timeouts and it's reported for the resource
+			bigIPPrometheus.ConfigurationWarnings.Reset()
+			rsCfg.Virtual.TCP.Client = "/Common/f5-tcp-lan"
+			app = as3Application{}
+			createServiceDecl(rsCfg, app, "test")
+			svc = app["crd_vs_172.13.14.15"].(*as3Service)
+			Expect(svc.ProfileTCP).To(Equal(&as3ResourcePointer{BigIP: "/Common/f5-tcp-lan"}))
+			Expect(app).NotTo(HaveKey("crd_vs_172.13.14.15_tcp_profile"))
+			Expect(getGaugeValue(bigIPPrometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, "default", "vs1",
+				"idleTimeout and connectTimeout are ignored as the TCP_Profile is referenced by the policy"))).To(
+				BeEquivalentTo(1))
+			bigIPPrometheus.ClearConfigurationWarnings(VirtualServer, "default", "vs1")
+
+			rsCfg = &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_ts_172.13.14.16"
+			rsCfg.Virtual.Mode = "standard"
+			rsCfg.Virtual.IpProtocol = "udp"
+			rsCfg.Virtual.IdleTimeout = 60
+			app = as3Application{}
+			createTransportServiceDecl(rsCfg, app, "test")
+			svc = app["crd_ts_172.13.14.16"].(*as3Service)
+			Expect(svc.RateLimit).To(BeZero())
+			Expect(svc.ProfileUDP).To(Equal(&as3ResourcePointer{
+				Use: "/test/crd_ts_172.13.14.16/crd_ts_172.13.14.16_udp_profile"}))
+			Expect(app["crd_ts_172.13.14.16_udp_profile"]).To(Equal(&as3ProtocolProfile{Class: "UDP_Profile",
+				IdleTimeout: 60}))
+		})
+		It("Bounds the connection setup of the TCP profile by the connect timeout", func() {
+			rtoBase, retransmissions := getSynRetransmission(1)
+			Expect(rtoBase).To(BeEquivalentTo(333))
+			Expect(retransmissions).To(BeEquivalentTo(1))
+			rtoBase, retransmissions = getSynRetransmission(15)
+			Expect(rtoBase).To(BeEquivalentTo(5000))
+			Expect(retransmissions).To(BeEquivalentTo(1))
+			// the retransmissions grow once the initial timeout exceeds its limit
+			rtoBase, retransmissions = getSynRetransmission(75)
+			Expect(rtoBase).To(BeEquivalentTo(5000))
+			Expect(retransmissions).To(BeEquivalentTo(3))
+			rtoBase, retransmissions = getSynRetransmission(76)
+			Expect(rtoBase).To(BeEquivalentTo(2451))
+			Expect(retransmissions).To(BeEquivalentTo(4))
+		})
+		It("Prefers the pool members in the zone of the bigip", func() {
+			nodes := []Node{
+				{Name: "node1", Addr: "10.10.10.1", Labels: map[string]string{v1.LabelTopologyZone: "zone-a"}},
+				{Name: "node2", Addr: "10.10.10.2", Labels: map[string]string{v1.LabelTopologyZone: "zone-b"}},
+			}
+			Expect(getNodeZone(nodes, "node2")).To(Equal("zone-b"))
+			Expect(getNodeZone(nodes, "node3")).To(Equal(""))
+
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.15"
+			rsCfg.Pools = Pools{
+				Pool{
+					Name: "pool1",
+					Members: []PoolMember{
+						{Address: "1.2.3.5", Port: 8080, Zone: "zone-a", PriorityGroup: 1},
+						{Address: "1.2.3.6", Port: 8080, Zone: "zone-b", PriorityGroup: 1},
+						{Address: "1.2.3.7", Port: 8080, Zone: "zone-a"},
+					},
+				},
+				Pool{
+					Name:    "pool2",
+					Members: []PoolMember{mem1, mem2},
+				},
+			}
+			app := as3Application{}
+			createPoolDecl(rsCfg, app, false, "test", Cluster, "zone-a")
+			pool := app["pool1"].(*as3Pool)
+			Expect(pool.MinimumMembersActive).To(BeEquivalentTo(DefaultMinimumMembersActive))
+			Expect(pool.Members[0].PriorityGroup).To(BeEquivalentTo(3))
+			Expect(pool.Members[1].PriorityGroup).To(BeEquivalentTo(2))
+			Expect(pool.Members[2].PriorityGroup).To(BeEquivalentTo(1))
+			// pools without the zones of the members are not changed
+			pool = app["pool2"].(*as3Pool)
+			Expect(pool.MinimumMembersActive).To(BeZero())
+			Expect(pool.Members[0].PriorityGroup).To(BeZero())
+
+			app = as3Application{}
+			createPoolDecl(rsCfg, app, false, "test", Cluster, "")
+			pool = app["pool1"].(*as3Pool)
+			Expect(pool.MinimumMembersActive).To(BeZero())
+			Expect(pool.Members[0].PriorityGroup).To(BeEquivalentTo(1))
+			Expect(pool.Members[2].PriorityGroup).To(BeZero())
+		})
+		It("Skips the duplicate pool members by address and port", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.15"
+			rsCfg.Pools = Pools{
+				Pool{
+					Name: "pool1",
+					Members: []PoolMember{
+						{Address: "1.2.3.5", Port: 8080},
+						{Address: "1.2.3.6", Port: 8080, ConnectionLimit: 10},
+						{Address: "1.2.3.5", Port: 8080, PriorityGroup: 1},
+						{Address: "1.2.3.6", Port: 8080},
+						{Address: "1.2.3.6", Port: 8443},
+					},
+				},
+			}
+			app := as3Application{}
+			createPoolDecl(rsCfg, app, false, "test", Cluster, "")
+			pool := app["pool1"].(*as3Pool)
+			Expect(pool.Members).To(HaveLen(3))
+			Expect(pool.Members[0].ServerAddresses).To(Equal([]string{"1.2.3.5"}))
+			Expect(pool.Members[0].PriorityGroup).To(BeEquivalentTo(1), "member of the highest priority group should be kept")
+			Expect(pool.Members[1].ConnectionLimit).To(BeEquivalentTo(10), "first member should be kept on the same priority group")
+			Expect(pool.Members[2].ServicePort).To(BeEquivalentTo(8443))
+		})
 	})
 
 	Describe("Prepare AS3 Declaration with HAMode", func() {
@@ -750,7 +878,7 @@ var _ = Describe("Backend Tests", func() {
 					"test": &PartitionConfig{ResourceMap: make(ResourceMap)},
 				}},
 			}
-			pm.PostManager.AS3PostManager.firstPost = false
+			pm.PostManager.AS3PostManager.firstPost.Store(false)
 			agentCfg := requestHandler.createDeclarationForBIGIP(config, pm.PostManager)
 			Expect(agentCfg.as3Config.data).ToNot(Equal(""), "Failed to Create AS3 Declaration")
 			Expect(strings.Contains(agentCfg.as3Config.data, "\"class\":\"Tenant\"")).To(BeTrue())
@@ -761,7 +889,7 @@ var _ = Describe("Backend Tests", func() {
 			Expect(agentCfg.as3Config.data).To(Equal(""), "Failed to Create AS3 Declaration")
 
 			requestHandler.PrimaryClusterHealthProbeParams.statusRunning = false
-			pm.PostManager.AS3PostManager.firstPost = true
+			pm.PostManager.AS3PostManager.firstPost.Store(true)
 			agentCfg = requestHandler.createDeclarationForBIGIP(config, pm.PostManager)
 			Expect(agentCfg.as3Config.data).ToNot(Equal(""), "Failed to Create AS3 Declaration")
 			Expect(strings.Contains(agentCfg.as3Config.data, "\"class\":\"Tenant\"")).To(BeTrue())
@@ -935,4 +1063,13 @@ var _ = Describe("Backend Tests", func() {
 		})*/
 	})
 
+	It("Share the post slots of the central manager", func() {
+		requestHandler := &RequestHandler{}
+		Expect(requestHandler.getCMPostSlots("https://cm1", 0)).To(BeNil())
+		Expect(requestHandler.getCMPostSlots("https://cm1", 1)).To(BeNil())
+		postSlots := requestHandler.getCMPostSlots("https://cm1", 4)
+		Expect(cap(postSlots)).To(Equal(4))
+		Expect(requestHandler.getCMPostSlots("https://cm1", 4)).To(Equal(postSlots))
+		Expect(requestHandler.getCMPostSlots("https://cm2", 2)).ToNot(Equal(postSlots))
+	})
 })
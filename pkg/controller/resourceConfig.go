@@ -485,8 +485,12 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 				MinimumMonitors:   pl.MinimumMonitors,
 				ReselectTries:     pl.ReselectTries,
 				ServiceDownAction: pl.ServiceDownAction,
+				ConnectionLimit:   pl.ConnectionLimit,
 				Cluster:           SvcBackend.Cluster, // In all modes other than ratio, the cluster is ""
 			}
+			ctlr.updatePoolFallbackServices(rsCfg, rsRef, &pool, pl.FallbackServices, pl.MinimumMembersActive,
+				pl.ServicePort, pl.Path, bigipLabel)
+			updateVirtualConnectionLimits(rsCfg, pl.ConnectionLimits)
 
 			if ctlr.multiClusterMode != "" {
 				//check for external service reference
@@ -540,8 +544,13 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 				rsCfg.MetaData.Active = true
 			}
 
+			monitorPath := pl.Path
+			if pl.PathType == PathTypeRegex {
+				// regex isn't valid in the monitor name
+				monitorPath = ""
+			}
 			if !reflect.DeepEqual(pl.Monitor, cisapiv1.Monitor{}) {
-				ctlr.createVirtualServerMonitor(pl.Monitor, &pool, rsCfg, pl.ServicePort, vs.Spec.Host, pl.Path,
+				ctlr.createVirtualServerMonitor(pl.Monitor, &pool, rsCfg, pl.ServicePort, vs.Spec.Host, monitorPath,
 					vs.ObjectMeta.Namespace+"/"+vs.ObjectMeta.Name, SvcBackend.Cluster)
 			} else if pl.Monitors != nil {
 				var formatPort intstr.IntOrString
@@ -551,7 +560,7 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 					} else {
 						formatPort = pl.ServicePort
 					}
-					ctlr.createVirtualServerMonitor(monitor, &pool, rsCfg, formatPort, vs.Spec.Host, pl.Path,
+					ctlr.createVirtualServerMonitor(monitor, &pool, rsCfg, formatPort, vs.Spec.Host, monitorPath,
 						vs.ObjectMeta.Namespace+"/"+vs.ObjectMeta.Name, SvcBackend.Cluster)
 				}
 			}
@@ -565,7 +574,7 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 						vs.Namespace,
 						rsCfg.IntDgMap,
 						pl.ServicePort,
-						vs.Spec.Host,
+						getVirtualServerHosts(vs),
 						tlsTermination,
 					)
 					//path based AB deployment/Cluster ratio not supported for passthrough
@@ -588,7 +597,7 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 						vs.Namespace,
 						rsCfg.IntDgMap,
 						pl.ServicePort,
-						vs.Spec.Host,
+						getVirtualServerHosts(vs),
 						tlsTermination,
 					)
 					// Handle AB path based IRules for insecure virtualserver
@@ -681,6 +690,10 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 		policyName := formatPolicyName(vs.Spec.Host, vs.Spec.HostGroup, rsCfg.Virtual.Name)
 
 		rsCfg.AddRuleToPolicy(policyName, vs.Namespace, rules)
+
+		if err := ctlr.prepareVirtualServerErrorPages(vs, rsCfg); err != nil {
+			return fmt.Errorf("failed to create the error pages: %v", err)
+		}
 	}
 
 	// Attach user specified iRules
@@ -690,11 +703,46 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 
 	// Append all the hosts from a host group/ single host
 	if vs.Spec.Host != "" {
-		rsCfg.MetaData.hosts = append(rsCfg.MetaData.hosts, vs.Spec.Host)
+		rsCfg.MetaData.hosts = append(rsCfg.MetaData.hosts, getVirtualServerHosts(vs)...)
 	}
 	return nil
 }
 
+// updatePoolFallbackServices sets the fallback services and the minimum active members of the pool, the fallback
+// services are mapped to the pool to update the pool members on the changes of their endpoints
+func (ctlr *Controller) updatePoolFallbackServices(rsCfg *ResourceConfig, rsRef resourceRef, pool *Pool,
+	fallbackServices []cisapiv1.FallbackService, minimumMembersActive int32, servicePort intstr.IntOrString,
+	path string, bigipLabel string) {
+	if len(fallbackServices) == 0 {
+		return
+	}
+	pool.MinimumMembersActive = minimumMembersActive
+	if pool.MinimumMembersActive == 0 {
+		pool.MinimumMembersActive = DefaultMinimumMembersActive
+	}
+	for _, fb := range fallbackServices {
+		svcNamespace := rsRef.namespace
+		if fb.ServiceNamespace != "" {
+			svcNamespace = fb.ServiceNamespace
+		}
+		targetPort := ctlr.fetchTargetPort(svcNamespace, fb.Service, servicePort, "")
+		if (intstr.IntOrString{}) == targetPort {
+			targetPort = servicePort
+		}
+		pool.FallbackServices = append(pool.FallbackServices, FallbackService{
+			ServiceName:      fb.Service,
+			ServiceNamespace: svcNamespace,
+			ServicePort:      targetPort,
+		})
+		svcKey := MultiClusterServiceKey{
+			serviceName: fb.Service,
+			namespace:   svcNamespace,
+		}
+		ctlr.updatePoolIdentifierForService(svcKey, rsRef, servicePort, pool.Name, pool.Partition, rsCfg.Virtual.Name,
+			path, bigipLabel)
+	}
+}
+
 func (ctlr *Controller) createVirtualServerMonitor(monitor cisapiv1.Monitor, pool *Pool, rsCfg *ResourceConfig,
 	formatPort intstr.IntOrString, host, path, vsName string, cluster string) {
 	if !reflect.DeepEqual(monitor, Monitor{}) {
@@ -1180,7 +1228,7 @@ func (ctlr *Controller) handleVirtualServerTLS(
 			if len(tls.Spec.Hosts) > 1 {
 				poolPathRefs = append(poolPathRefs, poolPathRef{pl.Path, poolName, tls.Spec.Hosts})
 			} else {
-				poolPathRefs = append(poolPathRefs, poolPathRef{pl.Path, poolName, []string{vs.Spec.Host}})
+				poolPathRefs = append(poolPathRefs, poolPathRef{pl.Path, poolName, getVirtualServerHosts(vs)})
 			}
 		}
 	}
@@ -1880,7 +1928,9 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 		Balance:           vs.Spec.Pool.Balance,
 		ReselectTries:     vs.Spec.Pool.ReselectTries,
 		ServiceDownAction: vs.Spec.Pool.ServiceDownAction,
+		ConnectionLimit:   vs.Spec.Pool.ConnectionLimit,
 	}
+	updateVirtualConnectionLimits(rsCfg, vs.Spec.Pool.ConnectionLimits)
 	svcKey := MultiClusterServiceKey{
 		serviceName: vs.Spec.Pool.Service,
 		clusterName: "",
@@ -1896,6 +1946,8 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 	bigipLabel := BigIPLabel
 	// update the pool identifier for service
 	ctlr.updatePoolIdentifierForService(svcKey, rsRef, vs.Spec.Pool.ServicePort, pool.Name, pool.Partition, rsCfg.Virtual.Name, "", bigipLabel)
+	ctlr.updatePoolFallbackServices(rsCfg, rsRef, &pool, vs.Spec.Pool.FallbackServices, vs.Spec.Pool.MinimumMembersActive,
+		vs.Spec.Pool.ServicePort, "", bigipLabel)
 
 	if ctlr.multiClusterMode != "" {
 		//check for external service reference
@@ -2231,6 +2283,16 @@ func (ctlr *Controller) handlePoolResourceConfigForPolicy(
 	rsCfg *ResourceConfig,
 	plc *cisapiv1.Policy,
 ) error {
+	// the limits of the pools take precedence over the limits of the policy
+	if rsCfg.Virtual.RateLimit == 0 {
+		rsCfg.Virtual.RateLimit = plc.Spec.PoolSettings.RateLimit
+	}
+	if rsCfg.Virtual.IdleTimeout == 0 {
+		rsCfg.Virtual.IdleTimeout = plc.Spec.PoolSettings.IdleTimeout
+	}
+	if rsCfg.Virtual.ConnectTimeout == 0 {
+		rsCfg.Virtual.ConnectTimeout = plc.Spec.PoolSettings.ConnectTimeout
+	}
 	for i, pl := range rsCfg.Pools {
 		if pl.ReselectTries == 0 && plc.Spec.PoolSettings.ReselectTries != 0 {
 			pl.ReselectTries = plc.Spec.PoolSettings.ReselectTries
@@ -2241,11 +2303,33 @@ func (ctlr *Controller) handlePoolResourceConfigForPolicy(
 		if plc.Spec.PoolSettings.SlowRampTime != 0 {
 			pl.SlowRampTime = plc.Spec.PoolSettings.SlowRampTime
 		}
+		if pl.ConnectionLimit == 0 && plc.Spec.PoolSettings.ConnectionLimit != 0 {
+			pl.ConnectionLimit = plc.Spec.PoolSettings.ConnectionLimit
+			// pool members are already fetched for the pool
+			for j := range pl.Members {
+				pl.Members[j].ConnectionLimit = pl.ConnectionLimit
+			}
+		}
 		//update pool
 		rsCfg.Pools[i] = pl
 	}
 	return nil
 }
+
+// updateVirtualConnectionLimits updates the connection rate limit and the timeouts of the virtual, the lowest
+// rate limit and the highest timeouts of the pools are applied to the virtual
+func updateVirtualConnectionLimits(rsCfg *ResourceConfig, limits cisapiv1.ConnectionLimits) {
+	if limits.RateLimit > 0 && (rsCfg.Virtual.RateLimit == 0 || limits.RateLimit < rsCfg.Virtual.RateLimit) {
+		rsCfg.Virtual.RateLimit = limits.RateLimit
+	}
+	if limits.IdleTimeout > rsCfg.Virtual.IdleTimeout {
+		rsCfg.Virtual.IdleTimeout = limits.IdleTimeout
+	}
+	if limits.ConnectTimeout > rsCfg.Virtual.ConnectTimeout {
+		rsCfg.Virtual.ConnectTimeout = limits.ConnectTimeout
+	}
+}
+
 func getRSCfgResName(rsVSName, resName string) string {
 	return fmt.Sprintf("%s_%s", rsVSName, resName)
 }
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/clustermanager"
@@ -12,6 +13,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -313,6 +315,515 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(rsCfg.Virtual.IRules[0]).To(Equal("SampleIRule"))
 		})
 
+		It("Prepare Resource Config from a VirtualServer with match conditions", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:        "/foo",
+							Service:     "svc1-canary",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Match: &cisapiv1.PoolMatch{
+								Headers: []cisapiv1.HTTPMatchCondition{{Name: "x-canary", Value: "true"}},
+								Cookies: []cisapiv1.HTTPMatchCondition{{Name: "session", Type: MatchTypePresent}},
+							},
+						},
+						{
+							Path:        "/foo",
+							Service:     "svc1-v2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Match: &cisapiv1.PoolMatch{
+								QueryParameters: []cisapiv1.HTTPMatchCondition{{Name: "version", Type: MatchTypePrefix, Value: "v2"}},
+								Methods:         []string{"get", "POST"},
+							},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			rules := rsCfg.Policies[0].Rules
+			Expect(rules).To(HaveLen(3), "pools of the same path with different match should have a rule each")
+			Expect(rules[2].Conditions).To(HaveLen(2), "rule without the match should be the last")
+			Expect(rules[2].Actions[0].Pool).To(Equal("svc1_80_default_test_com"))
+			for _, rl := range rules[:2] {
+				Expect(rl.Conditions).To(HaveLen(4))
+				Expect(rl.Name).To(HavePrefix("vs_test_com_foo_"))
+				Expect(rl.Name).To(ContainSubstring("_match_"))
+			}
+
+			as3Rules := make(map[string]*as3Rule)
+			for _, rl := range rules[:2] {
+				rulesData := &as3Rule{}
+				createRuleCondition(rl, rulesData, 80)
+				as3Rules[rl.Actions[0].Pool] = rulesData
+			}
+			canary := as3Rules["svc1_canary_80_default_test_com"].Conditions
+			Expect(canary[2]).To(Equal(&as3Condition{Type: "httpHeader", Name: "x-canary", Event: "request",
+				All: &as3PolicyCompareString{Values: []string{"true"}, Operand: "equals"}}))
+			Expect(canary[3]).To(Equal(&as3Condition{Type: "httpCookie", Name: "session", Event: "request",
+				All: &as3PolicyCompareString{Operand: "exists"}}))
+			v2 := as3Rules["svc1_v2_80_default_test_com"].Conditions
+			Expect(v2[2]).To(Equal(&as3Condition{Type: "httpUri", Name: "version", Event: "request",
+				QueryParameter: &as3PolicyCompareString{Values: []string{"v2"}, Operand: "starts-with"}}))
+			Expect(v2[3]).To(Equal(&as3Condition{Type: "httpMethod", Event: "request",
+				All: &as3PolicyCompareString{Values: []string{"GET", "POST"}, Operand: "equals"}}))
+		})
+
+		It("Prepare Resource Config from a VirtualServer with header actions", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Rewrite:     "/bar",
+							RequestHeaders: &cisapiv1.HeaderActions{
+								Remove: []string{"x-internal"},
+							},
+						},
+					},
+					RequestHeaders: &cisapiv1.HeaderActions{
+						Replace: []cisapiv1.HTTPHeader{{Name: "X-Forwarded-Proto", Value: "https"}},
+					},
+					ResponseHeaders: &cisapiv1.HeaderActions{
+						Insert: []cisapiv1.HTTPHeader{{Name: "Strict-Transport-Security", Value: "max-age=31536000"}},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			Expect(rsCfg.Policies[0].Rules).To(HaveLen(1))
+			actions := rsCfg.Policies[0].Rules[0].Actions
+			Expect(actions).To(HaveLen(5), "forward, rewrite and header actions expected")
+			Expect(actions[2]).To(Equal(&action{Name: "2", HTTPHeader: true, HeaderName: "X-Forwarded-Proto",
+				Value: "https", Replace: true, Request: true}))
+			Expect(actions[3]).To(Equal(&action{Name: "3", HTTPHeader: true, HeaderName: "x-internal", Remove: true,
+				Request: true}))
+			Expect(actions[4]).To(Equal(&action{Name: "4", HTTPHeader: true, HeaderName: "Strict-Transport-Security",
+				Value: "max-age=31536000", Insert: true, Response: true}))
+
+			rulesData := &as3Rule{}
+			createRuleAction(rsCfg.Policies[0].Rules[0], rulesData)
+			Expect(rulesData.Actions[2]).To(Equal(&as3Action{Type: "httpHeader", Event: "request",
+				Replace: &as3ActionReplaceMap{Name: "X-Forwarded-Proto", Value: "https"}}))
+			Expect(rulesData.Actions[3]).To(Equal(&as3Action{Type: "httpHeader", Event: "request",
+				Remove: &as3ActionReplaceMap{Name: "x-internal"}}))
+			Expect(rulesData.Actions[4]).To(Equal(&as3Action{Type: "httpHeader", Event: "response",
+				Insert: &as3ActionReplaceMap{Name: "Strict-Transport-Security", Value: "max-age=31536000"}}))
+		})
+
+		It("Prepare Resource Config from a VirtualServer with redirect and fixed response rules", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			pages := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pages", Namespace: namespace},
+				Data:       map[string]string{"maintenance.html": "<h1>Under maintenance</h1>"},
+			}
+			_ = mockCtlr.crInformers[namespace].cmInformer.GetIndexer().Add(pages)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+					Rules: []cisapiv1.VSRule{
+						{
+							Path:     "/old",
+							Redirect: &cisapiv1.RuleRedirect{ReplacePrefix: "/new", StatusCode: 301},
+						},
+						{
+							Path:     "/moved",
+							Redirect: &cisapiv1.RuleRedirect{Scheme: "https", Host: "example.com", Path: "/"},
+						},
+						{
+							Path:          "/admin",
+							FixedResponse: &cisapiv1.FixedResponse{StatusCode: 403},
+						},
+						{
+							Path: "/shop",
+							FixedResponse: &cisapiv1.FixedResponse{StatusCode: 503, ContentType: "text/html",
+								BodyFrom: &cisapiv1.ConfigMapKeyReference{Name: "pages", Key: "maintenance.html"}},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			rules := make(map[string]*Rule)
+			for _, rl := range rsCfg.Policies[0].Rules {
+				rules[rl.Name] = rl
+			}
+			Expect(rules).To(HaveLen(5))
+			Expect(rules["vs_test_com_old_redirect"].Actions).To(Equal([]*action{{Name: "0", HttpReply: true,
+				Redirect: true, Request: true, Location: "tcl:/new[string range [HTTP::uri] 4 end]", StatusCode: 301}}))
+			Expect(rules["vs_test_com_moved_redirect"].Actions[0].Location).To(Equal("https://example.com/"))
+			Expect(rules["vs_test_com_moved_redirect"].Actions[0].StatusCode).To(Equal(DefaultRedirectStatusCode))
+			Expect(rules["vs_test_com_admin_fixed_response"].Actions).To(Equal([]*action{{Name: "0", Tcl: true,
+				Request: true, Variable: fixedResponseVariable, Value: "vs_test_com_admin_fixed_response"}}))
+			Expect(rules["vs_test_com_shop_fixed_response"].Conditions).To(HaveLen(2))
+
+			dg := rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, FixedResponseDgName),
+				Partition: "test"}][namespace]
+			Expect(dg.Records).To(Equal(InternalDataGroupRecords{
+				{Name: "vs_test_com_admin_fixed_response", Data: "403|text/plain|"},
+				{Name: "vs_test_com_shop_fixed_response", Data: "503|text/html|PGgxPlVuZGVyIG1haW50ZW5hbmNlPC9oMT4="},
+			}))
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, FixedResponseIRuleName)
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{Name: iRuleName, Partition: "test"}))
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)))
+
+			rulesData := &as3Rule{}
+			createRuleAction(rules["vs_test_com_old_redirect"], rulesData)
+			Expect(rulesData.Actions[0]).To(Equal(&as3Action{Type: "httpRedirect", Event: "request",
+				Location: "tcl:/new[string range [HTTP::uri] 4 end]", Code: 301}))
+			rulesData = &as3Rule{}
+			createRuleAction(rules["vs_test_com_admin_fixed_response"], rulesData)
+			Expect(rulesData.Actions[0]).To(Equal(&as3Action{Type: "tcl", Event: "request",
+				SetVariable: &as3ActionSetVariable{Name: fixedResponseVariable, Expression: "vs_test_com_admin_fixed_response"}}))
+
+			Expect(getVirtualServersForConfigMap([]*cisapiv1.VirtualServer{vs}, pages)).To(Equal(
+				[]*cisapiv1.VirtualServer{vs}), "VirtualServer should be processed when the ConfigMap changes")
+			Expect(getVirtualServersForConfigMap([]*cisapiv1.VirtualServer{vs}, &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace}})).To(BeEmpty())
+
+			// missing ConfigMap
+			_ = mockCtlr.crInformers[namespace].cmInformer.GetIndexer().Delete(pages)
+			Expect(mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")).NotTo(Succeed())
+		})
+
+		It("Prepare Resource Config from a VirtualServer with error pages", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			pages := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pages", Namespace: namespace},
+				Data:       map[string]string{"maintenance.html": "<h1>Under maintenance</h1>"},
+			}
+			_ = mockCtlr.crInformers[namespace].cmInformer.GetIndexer().Add(pages)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:  "test.com",
+					Hosts: []string{"*.example.com"},
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+					ErrorPages: &cisapiv1.ErrorPages{
+						MaintenancePage: &cisapiv1.FixedResponse{ContentType: "text/html",
+							BodyFrom: &cisapiv1.ConfigMapKeyReference{Name: "pages", Key: "maintenance.html"}},
+						Pages: []cisapiv1.ErrorPage{
+							{StatusCodes: []int32{502, 504}, Page: cisapiv1.FixedResponse{Body: "Try again later"}},
+							{StatusCodes: []int32{404}, Page: cisapiv1.FixedResponse{StatusCode: 200}},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			dg := rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, ErrorPagesDgName),
+				Partition: "test"}][namespace]
+			records := make(map[string]string)
+			for _, record := range dg.Records {
+				records[record.Name] = record.Data
+			}
+			Expect(records).To(HaveLen(8))
+			Expect(records).NotTo(HaveKey("test.com maintenance"), "Maintenance page should be served only in maintenance")
+			Expect(records["test.com unavailable"]).To(Equal("503|text/html|PGgxPlVuZGVyIG1haW50ZW5hbmNlPC9oMT4="))
+			Expect(records[".example.com 502"]).To(Equal("502|text/plain|VHJ5IGFnYWluIGxhdGVy"))
+			Expect(records[".example.com unavailable"]).To(Equal("503|text/html|PGgxPlVuZGVyIG1haW50ZW5hbmNlPC9oMT4="))
+			Expect(records).NotTo(HaveKey("*.example.com 502"), "Wildcard host should be keyed by its domain")
+			Expect(records["test.com 504"]).To(Equal("504|text/plain|VHJ5IGFnYWluIGxhdGVy"))
+			Expect(records["test.com 404"]).To(Equal("200|text/plain|"))
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ErrorPagesIRuleName)
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{Name: iRuleName, Partition: "test"}))
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)))
+
+			vs.Spec.ErrorPages.Maintenance = true
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(dg.Records).To(ContainElement(InternalDataGroupRecord{Name: "test.com maintenance",
+				Data: "503|text/html|PGgxPlVuZGVyIG1haW50ZW5hbmNlPC9oMT4="}))
+			Expect(dg.Records).To(ContainElement(InternalDataGroupRecord{Name: ".example.com maintenance",
+				Data: "503|text/html|PGgxPlVuZGVyIG1haW50ZW5hbmNlPC9oMT4="}))
+			Expect(getVirtualServersForConfigMap([]*cisapiv1.VirtualServer{vs}, pages)).To(Equal(
+				[]*cisapiv1.VirtualServer{vs}), "VirtualServer should be processed when the ConfigMap changes")
+
+			// maintenance page is updated with the ConfigMap
+			_ = mockCtlr.crInformers[namespace].cmInformer.GetIndexer().Update(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pages", Namespace: namespace},
+				Data:       map[string]string{"maintenance.html": "Back soon"},
+			})
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			dg = rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, ErrorPagesDgName),
+				Partition: "test"}][namespace]
+			Expect(dg.Records).To(ContainElement(InternalDataGroupRecord{Name: "test.com maintenance",
+				Data: "503|text/html|QmFjayBzb29u"}))
+		})
+
+		It("Prepare Resource Config from a VirtualServer with path types", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:        "/foo/bar",
+							PathType:    PathTypeExact,
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:        "/api/v[0-9]+/.*",
+							PathType:    PathTypeRegex,
+							Service:     "svc3",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			rules := rsCfg.Policies[0].Rules
+			Expect(rules).To(HaveLen(2))
+			// exact path precedes the prefix path
+			Expect(rules[0].Conditions).To(ContainElement(&condition{Equals: true, HTTPURI: true, Path: true,
+				Request: true, Values: []string{"/foo/bar"}}))
+			for _, cond := range rules[0].Conditions {
+				Expect(cond.PathSegment).To(BeFalse())
+			}
+			Expect(rules[1].Conditions[len(rules[1].Conditions)-1].PathSegment).To(BeTrue())
+
+			dg := rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, RegexPathDgName),
+				Partition: "test"}][namespace]
+			Expect(dg.Records).To(HaveLen(1))
+			Expect(dg.Records[0].Data).To(Equal(rsCfg.Pools[2].Name + " L2FwaS92WzAtOV0rLy4q"))
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, RegexPathIRuleName)
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{Name: iRuleName, Partition: "test"}))
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)))
+
+			// regex paths are evaluated in the order of the pools and allow only the source ranges
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			vs.Spec.AllowSourceRange = []string{"10.0.0.0/8", "192.168.0.0/16"}
+			vs.Spec.Pools = append(vs.Spec.Pools, cisapiv1.VSPool{
+				Path:        "/[a-z]+",
+				PathType:    PathTypeRegex,
+				Service:     "svc4",
+				ServicePort: intstr.IntOrString{IntVal: 80},
+			})
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			dg = rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, RegexPathDgName),
+				Partition: "test"}][namespace]
+			Expect(dg.Records).To(HaveLen(2))
+			Expect(dg.Records[0].Name).To(HavePrefix("test.com 0000_"))
+			Expect(dg.Records[0].Data).To(Equal(rsCfg.Pools[2].Name + " L2FwaS92WzAtOV0rLy4q 10.0.0.0/8 192.168.0.0/16"))
+			Expect(dg.Records[1].Name).To(HavePrefix("test.com 0001_"))
+			Expect(dg.Records[1].Data).To(HavePrefix("svc4_"))
+			Expect(rsCfg.IRulesMap[NameRef{Name: iRuleName, Partition: "test"}].Code).To(
+				ContainSubstring("[IP::addr [IP::client_addr] equals $source_range]"))
+		})
+
+		It("Prepare Resource Config from a VirtualServer with connection limits", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/cart",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							ConnectionLimits: cisapiv1.ConnectionLimits{ConnectionLimit: 100, RateLimit: 1000,
+								IdleTimeout: 300, ConnectTimeout: 5},
+						},
+						{
+							Path:        "/api",
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							ConnectionLimits: cisapiv1.ConnectionLimits{RateLimit: 500, IdleTimeout: 60,
+								ConnectTimeout: 10},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Pools).To(HaveLen(2))
+			Expect(rsCfg.Pools[0].ConnectionLimit).To(BeEquivalentTo(100), "Incorrect connection limit of the pool")
+			Expect(rsCfg.Pools[1].ConnectionLimit).To(BeZero(), "Incorrect connection limit of the pool")
+			Expect(rsCfg.Virtual.RateLimit).To(BeEquivalentTo(500), "Lowest rate limit should be set for the virtual")
+			Expect(rsCfg.Virtual.IdleTimeout).To(BeEquivalentTo(300), "Highest idle timeout should be set for the virtual")
+			Expect(rsCfg.Virtual.ConnectTimeout).To(BeEquivalentTo(10),
+				"Highest connect timeout should be set for the virtual")
+		})
+
+		It("Prepare Resource Config from a VirtualServer with pool persistence", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/cart",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Persistence: &cisapiv1.PoolPersistence{Method: PersistenceCookieInsert,
+								CookieName: "cart", Expiry: "1d"},
+						},
+						{
+							Path:        "/api",
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Persistence: &cisapiv1.PoolPersistence{Method: PersistenceHash,
+								HeaderName: "X-User", TimeOut: 600},
+						},
+						{
+							Path:        "/legacy",
+							Service:     "svc3",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Persistence: &cisapiv1.PoolPersistence{Method: PersistenceSourceAddress},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			rules := make(map[string]*Rule)
+			for _, rl := range rsCfg.Policies[0].Rules {
+				rules[rl.Conditions[1].Values[0]] = rl
+			}
+			Expect(rules).To(HaveLen(3))
+
+			rulesData := &as3Rule{}
+			createRuleAction(rules["cart"], rulesData)
+			Expect(rulesData.Actions).To(HaveLen(2))
+			Expect(json.Marshal(rulesData.Actions[1])).To(MatchJSON(
+				`{"type":"persist","event":"request","cookieInsert":{"name":"cart","expiry":"1d"}}`))
+			rulesData = &as3Rule{}
+			createRuleAction(rules["api"], rulesData)
+			Expect(json.Marshal(rulesData.Actions[1])).To(MatchJSON(
+				`{"type":"persist","event":"request","hash":{"key":"tcl:[HTTP::header value \"X-User\"]","timeout":600}}`))
+			rulesData = &as3Rule{}
+			createRuleAction(rules["legacy"], rulesData)
+			Expect(json.Marshal(rulesData.Actions[1])).To(MatchJSON(
+				`{"type":"persist","event":"request","sourceAddress":{"netmask":"255.255.255.255","timeout":180}}`))
+		})
+
+		It("Prepare Resource Config from a VirtualServer with multiple hosts", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:  "test.com",
+					Hosts: []string{"*.example.com", "test.com", "foo.com"},
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Pools).To(HaveLen(1), "Pools should be shared by the hosts")
+			Expect(rsCfg.MetaData.hosts).To(Equal([]string{"test.com", "*.example.com", "foo.com"}))
+			Expect(rsCfg.Policies).To(HaveLen(1))
+			rules := rsCfg.Policies[0].Rules
+			Expect(rules).To(HaveLen(3))
+			// wildcard host is ordered after the other hosts
+			Expect(rules[2].FullURI).To(Equal("*.example.com/foo"))
+			for _, rl := range rules {
+				Expect(rl.Actions[0].Pool).To(Equal(rsCfg.Pools[0].Name))
+			}
+		})
+
 		It("Validate Resource Config from a AB Deployment VirtualServer", func() {
 			rsCfg.MetaData.ResourceType = VirtualServer
 			rsCfg.Virtual.Enabled = true
@@ -567,6 +1078,59 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer with HTTP Monitor")
 			Expect(rsCfg.Pools[0].ServiceNamespace).To(Equal("test"), "Incorrect namespace defined for pool")
 		})
+
+		It("Prepare Resource Config from a TransportServer with connection limits", func() {
+			ts := test.NewTransportServer(
+				"SampleTS",
+				namespace,
+				cisapiv1.TransportServerSpec{
+					Pool: cisapiv1.TSPool{
+						Service:     "svc1",
+						ServicePort: intstr.IntOrString{IntVal: 80},
+						ConnectionLimits: cisapiv1.ConnectionLimits{
+							ConnectionLimit: 100,
+							RateLimit:       1000,
+							IdleTimeout:     300,
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromTransportServer(rsCfg, ts)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer with connection limits")
+			Expect(rsCfg.Pools[0].ConnectionLimit).To(BeEquivalentTo(100), "Incorrect connection limit of the pool")
+			Expect(rsCfg.Virtual.RateLimit).To(BeEquivalentTo(1000), "Incorrect rate limit of the virtual")
+			Expect(rsCfg.Virtual.IdleTimeout).To(BeEquivalentTo(300), "Incorrect idle timeout of the virtual")
+		})
+
+		It("Prepare Resource Config from a TransportServer with fallback services", func() {
+			ts := test.NewTransportServer(
+				"SampleTS",
+				namespace,
+				cisapiv1.TransportServerSpec{
+					Pool: cisapiv1.TSPool{
+						Service:     "svc1",
+						ServicePort: intstr.IntOrString{IntVal: 80},
+						FallbackServices: []cisapiv1.FallbackService{
+							{Service: "svc2"},
+							{Service: "svc3", ServiceNamespace: "backup"},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromTransportServer(rsCfg, ts)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer with fallback services")
+			Expect(rsCfg.Pools[0].MinimumMembersActive).To(BeEquivalentTo(DefaultMinimumMembersActive),
+				"Incorrect minimum active members of the pool")
+			Expect(rsCfg.Pools[0].FallbackServices).To(Equal([]FallbackService{
+				{ServiceName: "svc2", ServiceNamespace: namespace, ServicePort: intstr.IntOrString{IntVal: 80}},
+				{ServiceName: "svc3", ServiceNamespace: "backup", ServicePort: intstr.IntOrString{IntVal: 80}},
+			}), "Incorrect fallback services of the pool")
+
+			pool := rsCfg.Pools[0]
+			pool.Members = []PoolMember{{Address: "10.1.1.1", Port: 80}}
+			mockCtlr.updateFallbackPoolMembers(&pool)
+			Expect(pool.Members[0].PriorityGroup).To(BeEquivalentTo(2), "Incorrect priority group of the pool member")
+		})
 		It("Prepare Resource Config from a TransportServer", func() {
 			ts := test.NewTransportServer(
 				"SampleTS",
@@ -1480,6 +2044,45 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(rsCfg.IntDgMap[nameRef]["default"].Records[0].Name).To(Equal("test.com"), "Failed to Process TLS for AB Virtual Server")
 
 		})
+
+		It("Handle AB Virtual Server with hosts", func() {
+			weight1 := int32(70)
+			weight2 := int32(30)
+			vs1 := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:  "test.com",
+					Hosts: []string{"foo.com", "*.example.com"},
+					Pools: []cisapiv1.VSPool{
+						{
+							Path:    "/foo",
+							Service: "svc1",
+							Weight:  &weight1,
+							AlternateBackends: []cisapiv1.AlternateBackend{
+								{
+									Service: "svc1-b",
+									Weight:  &weight2,
+								},
+							},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs1, false, "")
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+
+			nameRef := NameRef{
+				Name: "My_VS_80_ab_deployment_dg",
+			}
+			records := rsCfg.IntDgMap[nameRef][namespace].Records
+			Expect(records).To(HaveLen(3), "Failed to Process AB Deployment for the hosts of Virtual Server")
+			Expect(records[0].Name).To(Equal(".example.com/foo"))
+			Expect(records[1].Name).To(Equal("foo.com/foo"))
+			Expect(records[2].Name).To(Equal("test.com/foo"))
+			Expect(records[0].Data).To(Equal(records[2].Data), "Hosts should share the pools of the Virtual Server")
+			Expect(records[1].Data).To(Equal(records[2].Data), "Hosts should share the pools of the Virtual Server")
+		})
 	})
 
 	Describe("SNAT in policy CRD", func() {
@@ -1631,6 +2234,26 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(rsCfg.Pools[1].ServiceDownAction).To(Equal(plc.Spec.PoolSettings.ServiceDownAction), "ServiceDownAction should be set to reset")
 			Expect(rsCfg.Pools[1].SlowRampTime).To(Equal(plc.Spec.PoolSettings.SlowRampTime), "SlowRampTime should be set to 300")
 		})
+		It("Verifies connection limits are set properly for a policy", func() {
+			rsCfg.Pools[0].Members = []PoolMember{{Address: "10.1.1.1", Port: 80}}
+			rsCfg.Pools[1].ConnectionLimit = 50
+			rsCfg.Virtual.IdleTimeout = 600
+			plc.Spec.PoolSettings.ConnectionLimits = cisapiv1.ConnectionLimits{
+				ConnectionLimit: 100,
+				RateLimit:       1000,
+				IdleTimeout:     300,
+				ConnectTimeout:  5,
+			}
+			err := mockCtlr.handlePoolResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle pool resource config for policy")
+			Expect(rsCfg.Pools[0].ConnectionLimit).To(BeEquivalentTo(100), "ConnectionLimit should be set to 100")
+			Expect(rsCfg.Pools[0].Members[0].ConnectionLimit).To(BeEquivalentTo(100),
+				"ConnectionLimit of the pool members should be set to 100")
+			Expect(rsCfg.Pools[1].ConnectionLimit).To(BeEquivalentTo(50), "ConnectionLimit of the pool should be retained")
+			Expect(rsCfg.Virtual.RateLimit).To(BeEquivalentTo(1000), "RateLimit should be set to 1000")
+			Expect(rsCfg.Virtual.IdleTimeout).To(BeEquivalentTo(600), "IdleTimeout of the pools should be retained")
+			Expect(rsCfg.Virtual.ConnectTimeout).To(BeEquivalentTo(5), "ConnectTimeout should be set to 5")
+		})
 	})
 })
 
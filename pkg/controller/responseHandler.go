@@ -4,9 +4,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"strings"
 	"sync"
-	"time"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 )
 
@@ -43,16 +43,17 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 		ctlr.requestMap.Lock()
 		latestRequestMeta, _ := ctlr.requestMap.requestMap[config.BigIpConfig]
 		ctlr.requestMap.Unlock()
-		if len(config.as3Config.failedTenants) > 0 && latestRequestMeta.id == config.id {
-			// if the current request id is same as the failed tenant request id, then retry the failed tenants
-			ctlr.RequestHandler.PostManagers.RLock()
-			pm := ctlr.RequestHandler.PostManagers.PostManagerMap[config.BigIpConfig]
-			// Delay the retry of failed tenants
-			<-time.After(timeoutMedium)
-			pm.postChan <- *config
-			ctlr.RequestHandler.PostManagers.RUnlock()
+		retry := len(config.as3Config.failedTenants) > 0 && latestRequestMeta.id == config.id
+		if retry {
+			// if the current request id is same as the failed tenant request id, then retry the failed tenants.
+			// Retry doesn't block the response handler as the post managers may be blocked on sending the responses
+			go ctlr.RequestHandler.retryConfig(*config)
 		}
 		if latestRequestMeta.id >= config.id && len(config.as3Config.failedTenants) == 0 {
+			_, span := tracing.StartFrom(config.reqMeta.spanContext, "status.update",
+				tracing.RequestID.Int(config.id),
+				tracing.BigIPAddress.String(config.BigIpConfig.BigIpAddress),
+				tracing.Tenants.StringSlice(getSortedTenants(config.reqMeta.partitionMap)))
 			// Handle the network routes after successful post of tenants
 			ctlr.processStaticRouteUpdate()
 			// if the current request id is less than or equal to the latest request id, then udpate the status for current request
@@ -69,6 +70,11 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 						ctlr.ipamHandler.RemoveUnusedIPAMEntries()
 					}
 					ns := strings.Split(rscKey, "/")[0]
+					rscLog := resourceLogger(kind, ns, strings.TrimPrefix(rscKey, ns+"/"), "").WithFields(log.Fields{
+						log.FieldRequestID: config.id,
+						log.FieldBigIP:     config.BigIpConfig.BigIpAddress,
+						log.FieldTenant:    partition,
+					})
 					switch kind {
 					//case VirtualServer:
 					//	// update status
@@ -111,16 +117,16 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 						// update status
 						crInf, ok := ctlr.getNamespacedCRInformer(ns)
 						if !ok {
-							log.Debugf("TransportServer Informer not found for namespace: %v", ns)
+							rscLog.Debugf("TransportServer Informer not found for namespace: %v", ns)
 							continue
 						}
 						obj, exist, err := crInf.tsInformer.GetIndexer().GetByKey(rscKey)
 						if err != nil {
-							log.Debugf("Could not fetch TransportServer: %v: %v", rscKey, err)
+							rscLog.Debugf("Could not fetch TransportServer: %v: %v", rscKey, err)
 							continue
 						}
 						if !exist {
-							log.Debugf("TransportServer Not Found: %v", rscKey)
+							rscLog.Debugf("TransportServer Not Found: %v", rscKey)
 							continue
 						}
 						virtual := obj.(*cisapiv1.TransportServer)
@@ -145,16 +151,16 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 						// update status
 						crInf, ok := ctlr.getNamespacedCRInformer(ns)
 						if !ok {
-							log.Debugf("IngressLink Informer not found for namespace: %v", ns)
+							rscLog.Debugf("IngressLink Informer not found for namespace: %v", ns)
 							continue
 						}
 						obj, exist, err := crInf.ilInformer.GetIndexer().GetByKey(rscKey)
 						if err != nil {
-							log.Debugf("Could not fetch IngressLink: %v: %v", rscKey, err)
+							rscLog.Debugf("Could not fetch IngressLink: %v: %v", rscKey, err)
 							continue
 						}
 						if !exist {
-							log.Debugf("IngressLink Not Found: %v", rscKey)
+							rscLog.Debugf("IngressLink Not Found: %v", rscKey)
 							continue
 						}
 						il := obj.(*cisapiv1.IngressLink)
@@ -174,6 +180,11 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 					}
 				}
 			}
+			span.End()
+		}
+		if !retry {
+			// the request is handled, failed tenants are retried as the same request
+			ctlr.RequestHandler.pendingRequests.Add(-1)
 		}
 	}
 }
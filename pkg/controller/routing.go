@@ -20,9 +20,12 @@ import (
 	"fmt"
 
 	routeapi "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"encoding/base64"
 	"encoding/json"
+	"hash/fnv"
 	"net/url"
 	"sort"
 	"strconv"
@@ -42,14 +45,87 @@ func (ctlr *Controller) prepareVirtualServerRules(
 	wildcards := make(ruleMap)
 	var redirects []*Rule
 
+	for _, host := range getVirtualServerHosts(vs) {
+		hostRedirects, ok := ctlr.prepareVirtualServerHostRules(vs, host, rsCfg, rlMap, wildcards)
+		if !ok {
+			return nil
+		}
+		redirects = append(redirects, hostRedirects...)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	sortrules := func(r ruleMap, rls *Rules, ordinal int) {
+		for _, v := range r {
+			*rls = append(*rls, v)
+		}
+		//sort.Sort(sort.Reverse(*rls))
+		for _, v := range *rls {
+			v.Ordinal = ordinal
+			ordinal++
+		}
+		wg.Done()
+	}
+
+	rls := Rules{}
+	go sortrules(rlMap, &rls, 0)
+
+	w := Rules{}
+	go sortrules(wildcards, &w, len(rlMap))
+
+	wg.Wait()
+
+	rls = append(rls, w...)
+
+	sort.Sort(rls)
+	rls = append(redirects, rls...)
+	return &rls
+}
+
+// getVirtualServerHosts returns the host followed by the additional hosts of the VirtualServer, the host is
+// empty for the VirtualServer without hosts
+func getVirtualServerHosts(vs *cisapiv1.VirtualServer) []string {
+	hosts := []string{vs.Spec.Host}
+	for _, host := range vs.Spec.Hosts {
+		duplicate := false
+		for _, h := range hosts {
+			if h == host {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// prepareVirtualServerHostRules adds the LTM Policy rules of the host of the VirtualServer to the rule maps and
+// returns the rules of the root path which are ordered ahead of the other rules
+func (ctlr *Controller) prepareVirtualServerHostRules(
+	vs *cisapiv1.VirtualServer,
+	host string,
+	rsCfg *ResourceConfig,
+	rlMap ruleMap,
+	wildcards ruleMap,
+) ([]*Rule, bool) {
+	var redirects []*Rule
+	// rule names of the additional hosts are formatted with the host as the host group is shared by the hosts
+	hostGroup := vs.Spec.HostGroup
+	if host != vs.Spec.Host {
+		hostGroup = ""
+	}
+
 	appRoot := "/"
 
 	if vs.Spec.RewriteAppRoot != "" {
-		ruleName := formatVirtualServerRuleName(vs.Spec.Host, vs.Spec.HostGroup, "redirectto", vs.Spec.RewriteAppRoot)
-		rl, err := createRedirectRule(vs.Spec.Host+appRoot, vs.Spec.RewriteAppRoot, ruleName, rsCfg.Virtual.AllowSourceRange)
+		ruleName := formatVirtualServerRuleName(host, hostGroup, "redirectto", vs.Spec.RewriteAppRoot)
+		rl, err := createRedirectRule(host+appRoot, vs.Spec.RewriteAppRoot, ruleName, rsCfg.Virtual.AllowSourceRange)
 		if nil != err {
 			log.Errorf("Error configuring redirect rule: %v", err)
-			return nil
+			return nil, false
 		}
 		redirects = append(redirects, rl)
 
@@ -66,12 +142,12 @@ func (ctlr *Controller) prepareVirtualServerRules(
 			wafPolicy = pl.WAF
 		}
 
-		uri := vs.Spec.Host + pl.Path
+		uri := host + pl.Path
 
 		path := pl.Path
 
 		if pl.Path == "/" {
-			uri = vs.Spec.Host + vs.Spec.RewriteAppRoot
+			uri = host + vs.Spec.RewriteAppRoot
 			path = vs.Spec.RewriteAppRoot
 		}
 		poolBackends := ctlr.GetPoolBackends(&pl)
@@ -86,13 +162,27 @@ func (ctlr *Controller) prepareVirtualServerRules(
 				vs.Spec.Host,
 				backend,
 			)
-			ruleName := formatVirtualServerRuleName(vs.Spec.Host, vs.Spec.HostGroup, path, poolName)
+			ruleName := formatVirtualServerRuleName(host, hostGroup, path, poolName)
+			matchKey := poolMatchKey(pl.Match)
+			if matchKey != "" {
+				// pools of the same path differ in the match conditions
+				ruleName = AS3NameFormatter(fmt.Sprintf("%s_match_%x", ruleName, hashString(matchKey)))
+			}
+			if pl.PathType == PathTypeRegex {
+				// the pool of the regex path is selected by the iRule instead of the LTM policy
+				addRegexPathRecord(rsCfg, vs.Namespace, host, pl.Path, poolName, ruleName, rsCfg.Virtual.AllowSourceRange)
+				continue
+			}
 			var err error
 			rl, err := createRule(uri, poolName, ruleName, rsCfg.Virtual.AllowSourceRange, wafPolicy, skipPool)
 			if nil != err {
 				log.Errorf("Error configuring rule: %v", err)
-				return nil
+				return nil, false
 			}
+			if pl.PathType == PathTypeExact {
+				setExactPathCondition(rl, path)
+			}
+			rl.Conditions = append(rl.Conditions, createMatchConditions(pl.Match)...)
 			if pl.HostRewrite != "" {
 				hostRewriteActions, err := getHostRewriteActions(
 					pl.HostRewrite,
@@ -100,7 +190,7 @@ func (ctlr *Controller) prepareVirtualServerRules(
 				)
 				if nil != err {
 					log.Errorf("Error configuring rule: %v", err)
-					return nil
+					return nil, false
 				}
 				rl.Actions = append(rl.Actions, hostRewriteActions...)
 			}
@@ -112,12 +202,25 @@ func (ctlr *Controller) prepareVirtualServerRules(
 				)
 				if nil != err {
 					log.Errorf("Error configuring rule: %v", err)
-					return nil
+					return nil, false
 				}
 				rl.Actions = append(rl.Actions, rewriteActions...)
 			}
-
-			if pl.Path == "/" {
+			// headers of the VirtualServer are applied before the headers of the pool
+			rl.Actions = append(rl.Actions, getHeaderActions(vs.Spec.RequestHeaders, false, len(rl.Actions))...)
+			rl.Actions = append(rl.Actions, getHeaderActions(pl.RequestHeaders, false, len(rl.Actions))...)
+			rl.Actions = append(rl.Actions, getHeaderActions(vs.Spec.ResponseHeaders, true, len(rl.Actions))...)
+			rl.Actions = append(rl.Actions, getHeaderActions(pl.ResponseHeaders, true, len(rl.Actions))...)
+			rl.Actions = append(rl.Actions, getPersistAction(pl.Persistence, len(rl.Actions))...)
+
+			if matchKey != "" {
+				// rules with the match conditions are ordered ahead of the rules of the same host and path
+				if strings.HasPrefix(uri, "*.") {
+					wildcards[uri+matchKey] = rl
+				} else {
+					rlMap[uri+matchKey] = rl
+				}
+			} else if pl.Path == "/" {
 				redirects = append(redirects, rl)
 			} else if true == strings.HasPrefix(uri, "*.") {
 				wildcards[uri] = rl
@@ -127,15 +230,28 @@ func (ctlr *Controller) prepareVirtualServerRules(
 		}
 	}
 
+	for _, vsRule := range vs.Spec.Rules {
+		rl, err := ctlr.createVirtualServerRule(vs, host, hostGroup, vsRule, rsCfg)
+		if nil != err {
+			log.Errorf("Error configuring rule: %v", err)
+			return nil, false
+		}
+		if strings.HasPrefix(rl.FullURI, "*.") {
+			wildcards[rl.FullURI] = rl
+		} else {
+			rlMap[rl.FullURI] = rl
+		}
+	}
+
 	if vs.Spec.RewriteAppRoot != "" && len(redirects) != 2 {
 		log.Error("AppRoot path not found for rewriting")
-		return nil
+		return nil, false
 	}
 
-	if rlMap[vs.Spec.Host] == nil && len(redirects) == 2 {
+	if rlMap[host] == nil && len(redirects) == 2 {
 		rl := &Rule{
-			Name:    formatVirtualServerRuleName(vs.Spec.Host, vs.Spec.HostGroup, "", redirects[1].Actions[0].Pool),
-			FullURI: vs.Spec.Host,
+			Name:    formatVirtualServerRuleName(host, hostGroup, "", redirects[1].Actions[0].Pool),
+			FullURI: host,
 			Actions: redirects[1].Actions,
 			Conditions: []*condition{
 				redirects[1].Conditions[0],
@@ -143,35 +259,185 @@ func (ctlr *Controller) prepareVirtualServerRules(
 		}
 		redirects = append(redirects, rl)
 	}
+	return redirects, true
+}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	sortrules := func(r ruleMap, rls *Rules, ordinal int) {
-		for _, v := range r {
-			*rls = append(*rls, v)
+// createVirtualServerRule creates the LTM policy rule redirecting or responding to the requests to the path of
+// the VirtualServer rule
+func (ctlr *Controller) createVirtualServerRule(
+	vs *cisapiv1.VirtualServer,
+	host string,
+	hostGroup string,
+	vsRule cisapiv1.VSRule,
+	rsCfg *ResourceConfig,
+) (*Rule, error) {
+	var ruleAction *action
+	var ruleName string
+	if vsRule.Redirect != nil {
+		ruleName = formatVirtualServerRuleName(host, hostGroup, vsRule.Path, "redirect")
+		statusCode := vsRule.Redirect.StatusCode
+		if statusCode == 0 {
+			statusCode = DefaultRedirectStatusCode
 		}
-		//sort.Sort(sort.Reverse(*rls))
-		for _, v := range *rls {
-			v.Ordinal = ordinal
-			ordinal++
+		ruleAction = &action{
+			Name:       "0",
+			HttpReply:  true,
+			Redirect:   true,
+			Request:    true,
+			Location:   getRedirectLocation(vsRule.Path, vsRule.Redirect),
+			StatusCode: int(statusCode),
 		}
-		wg.Done()
+	} else if vsRule.FixedResponse != nil {
+		ruleName = formatVirtualServerRuleName(host, hostGroup, vsRule.Path, "fixed_response")
+		body, err := ctlr.getResponseBody(vs.Namespace, vsRule.FixedResponse)
+		if err != nil {
+			return nil, err
+		}
+		// the iRule of the VirtualServer responds to the requests for which the LTM policy sets the variable
+		rsVSName := rsCfg.Virtual.Name
+		updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsVSName, FixedResponseDgName), rsCfg.Virtual.Partition,
+			vs.Namespace, ruleName, formatResponseRecord(vsRule.FixedResponse.StatusCode,
+				vsRule.FixedResponse.ContentType, body), DataGroupType)
+		rsCfg.addIRule(getRSCfgResName(rsVSName, FixedResponseIRuleName), rsCfg.Virtual.Partition,
+			fixedResponseIRule(rsVSName, rsCfg.Virtual.Partition))
+		rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, getRSCfgResName(rsVSName, FixedResponseIRuleName)))
+		ruleAction = &action{
+			Name:     "0",
+			Tcl:      true,
+			Request:  true,
+			Variable: fixedResponseVariable,
+			Value:    ruleName,
+		}
+	} else {
+		return nil, fmt.Errorf("redirect or fixedResponse is required for the rule of path %v", vsRule.Path)
+	}
+	rl, err := createRule(host+vsRule.Path, "", ruleName, rsCfg.Virtual.AllowSourceRange, "", false)
+	if err != nil {
+		return nil, err
 	}
+	rl.Actions = []*action{ruleAction}
+	return rl, nil
+}
 
-	rls := Rules{}
-	go sortrules(rlMap, &rls, 0)
+// getRedirectLocation returns the location of the redirect of the requests to the path, the location is a Tcl
+// expression keeping the scheme, host and path of the request which aren't set in the redirect
+func getRedirectLocation(path string, redirect *cisapiv1.RuleRedirect) string {
+	var location string
+	if redirect.Scheme != "" {
+		location = redirect.Scheme + "://"
+		if redirect.Host == "" {
+			location += "[HTTP::host]"
+		}
+	} else if redirect.Host != "" {
+		// network-path reference keeping the scheme of the request
+		location = "//"
+	}
+	location += redirect.Host
+	if redirect.Path != "" {
+		location += redirect.Path
+	} else if redirect.ReplacePrefix != "" {
+		// the path of the request starts with the path of the rule
+		location += fmt.Sprintf("%s[string range [HTTP::uri] %d end]", redirect.ReplacePrefix,
+			len(strings.TrimSuffix(path, "/")))
+	} else {
+		location += "[HTTP::uri]"
+	}
+	if strings.Contains(location, "[") {
+		location = "tcl:" + location
+	}
+	return location
+}
 
-	w := Rules{}
-	go sortrules(wildcards, &w, len(rlMap))
+// getResponseBody returns the body of the fixed response, the body is read from the ConfigMap if referenced
+func (ctlr *Controller) getResponseBody(namespace string, response *cisapiv1.FixedResponse) (string, error) {
+	if response.BodyFrom == nil {
+		return response.Body, nil
+	}
+	return ctlr.getConfigMapValue(namespace, response.BodyFrom)
+}
 
-	wg.Wait()
+// formatResponseRecord returns the data group record of the response served by the iRules, the record is the status
+// code, the content type and the base64 encoded body of the response separated by "|"
+func formatResponseRecord(statusCode int32, contentType, body string) string {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	return fmt.Sprintf("%d|%s|%s", statusCode, contentType, base64.StdEncoding.EncodeToString([]byte(body)))
+}
 
-	rls = append(rls, w...)
+// prepareVirtualServerErrorPages adds the maintenance and error pages of the VirtualServer to the data group of the
+// error pages iRule, the key is the host followed by maintenance, unavailable or the status code of the response
+func (ctlr *Controller) prepareVirtualServerErrorPages(vs *cisapiv1.VirtualServer, rsCfg *ResourceConfig) error {
+	errorPages := vs.Spec.ErrorPages
+	if errorPages == nil {
+		return nil
+	}
+	records := make(map[string]string)
+	if page := errorPages.MaintenancePage; page != nil {
+		body, err := ctlr.getResponseBody(vs.Namespace, page)
+		if err != nil {
+			return err
+		}
+		statusCode := page.StatusCode
+		if statusCode == 0 {
+			statusCode = DefaultMaintenanceStatusCode
+		}
+		records["unavailable"] = formatResponseRecord(statusCode, page.ContentType, body)
+		if errorPages.Maintenance {
+			records["maintenance"] = records["unavailable"]
+		}
+	}
+	for _, errorPage := range errorPages.Pages {
+		body, err := ctlr.getResponseBody(vs.Namespace, &errorPage.Page)
+		if err != nil {
+			return err
+		}
+		for _, code := range errorPage.StatusCodes {
+			statusCode := errorPage.Page.StatusCode
+			if statusCode == 0 {
+				statusCode = code
+			}
+			records[strconv.Itoa(int(code))] = formatResponseRecord(statusCode, errorPage.Page.ContentType, body)
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	rsVSName := rsCfg.Virtual.Name
+	for _, host := range getVirtualServerHosts(vs) {
+		// the iRule looks up the wildcard hosts by the domain following the "*"
+		prefix := strings.TrimPrefix(host, "*") + " "
+		for key, record := range records {
+			updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsVSName, ErrorPagesDgName), rsCfg.Virtual.Partition,
+				vs.Namespace, prefix+key, record, DataGroupType)
+		}
+	}
+	rsCfg.addIRule(getRSCfgResName(rsVSName, ErrorPagesIRuleName), rsCfg.Virtual.Partition,
+		errorPagesIRule(rsVSName, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, getRSCfgResName(rsVSName, ErrorPagesIRuleName)))
+	return nil
+}
 
-	sort.Sort(rls)
-	rls = append(redirects, rls...)
-	return &rls
+// getConfigMapValue returns the value of the key of the ConfigMap from the informer cache, the VirtualServers are
+// processed again when the ConfigMap changes
+func (ctlr *Controller) getConfigMapValue(namespace string, ref *cisapiv1.ConfigMapKeyReference) (string, error) {
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok || crInf.cmInformer == nil {
+		return "", fmt.Errorf("configMap informer not found for namespace %v", namespace)
+	}
+	obj, found, err := crInf.cmInformer.GetIndexer().GetByKey(namespace + "/" + ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the ConfigMap %v/%v: %v", namespace, ref.Name, err)
+	}
+	if !found {
+		return "", fmt.Errorf("ConfigMap %v/%v not found", namespace, ref.Name)
+	}
+	cm := obj.(*corev1.ConfigMap)
+	value, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %v not found in the ConfigMap %v/%v", ref.Key, namespace, ref.Name)
+	}
+	return value, nil
 }
 
 // format the rule name for VirtualServer
@@ -287,6 +553,143 @@ func createRule(uri, poolName, ruleName string, allowSourceRange []string, wafPo
 	return &rl, nil
 }
 
+// poolMatchKey returns a key identifying the match conditions of the pool, empty if the pool has none
+func poolMatchKey(match *cisapiv1.PoolMatch) string {
+	if match == nil {
+		return ""
+	}
+	var key []string
+	for _, hdr := range match.Headers {
+		key = append(key, fmt.Sprintf("header:%s:%s:%s", hdr.Name, hdr.Type, hdr.Value))
+	}
+	for _, cookie := range match.Cookies {
+		key = append(key, fmt.Sprintf("cookie:%s:%s:%s", cookie.Name, cookie.Type, cookie.Value))
+	}
+	for _, param := range match.QueryParameters {
+		key = append(key, fmt.Sprintf("query:%s:%s:%s", param.Name, param.Type, param.Value))
+	}
+	if len(match.Methods) > 0 {
+		key = append(key, "method:"+strings.Join(match.Methods, ","))
+	}
+	return strings.Join(key, ";")
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// createMatchConditions creates the LTM policy conditions of the header, cookie, query parameter and method
+// match of the pool
+func createMatchConditions(match *cisapiv1.PoolMatch) []*condition {
+	if match == nil {
+		return nil
+	}
+	var conditions []*condition
+	for _, hdr := range match.Headers {
+		cond := newMatchCondition(hdr)
+		cond.HTTPHeader = true
+		conditions = append(conditions, cond)
+	}
+	for _, cookie := range match.Cookies {
+		cond := newMatchCondition(cookie)
+		cond.HTTPCookie = true
+		conditions = append(conditions, cond)
+	}
+	for _, param := range match.QueryParameters {
+		cond := newMatchCondition(param)
+		cond.QueryParameter = true
+		conditions = append(conditions, cond)
+	}
+	if len(match.Methods) > 0 {
+		var methods []string
+		for _, method := range match.Methods {
+			methods = append(methods, strings.ToUpper(method))
+		}
+		conditions = append(conditions, &condition{
+			HTTPMethod: true,
+			Equals:     true,
+			Request:    true,
+			Values:     methods,
+		})
+	}
+	return conditions
+}
+
+func newMatchCondition(match cisapiv1.HTTPMatchCondition) *condition {
+	cond := &condition{
+		Name:    match.Name,
+		Request: true,
+	}
+	switch match.Type {
+	case MatchTypePresent:
+		cond.Present = true
+	case MatchTypePrefix:
+		cond.StartsWith = true
+		cond.Values = []string{match.Value}
+	default:
+		cond.Equals = true
+		cond.Values = []string{match.Value}
+	}
+	return cond
+}
+
+// setExactPathCondition replaces the path segment conditions of the rule with the condition matching the exact path
+func setExactPathCondition(rl *Rule, path string) {
+	var conditions []*condition
+	for _, cond := range rl.Conditions {
+		if !cond.PathSegment {
+			conditions = append(conditions, cond)
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	rl.Conditions = append(conditions, &condition{
+		Equals:  true,
+		HTTPURI: true,
+		Path:    true,
+		Request: true,
+		Values:  []string{path},
+	})
+}
+
+// addRegexPathRecord adds the pool of the regex path to the data group of the regex path iRule. The key is the host
+// followed by the order of the regex path and the rule name, the iRule evaluates the regex paths of the host in the
+// order they are added. The value is the pool followed by the base64 encoded regex and the allowed source ranges
+func addRegexPathRecord(rsCfg *ResourceConfig, namespace, host, path, poolName, ruleName string, allowSourceRange []string) {
+	rsVSName := rsCfg.Virtual.Name
+	dgName := getRSCfgResName(rsVSName, RegexPathDgName)
+	value := strings.Join(append([]string{poolName, base64.StdEncoding.EncodeToString([]byte(path))},
+		allowSourceRange...), " ")
+	updateDataGroup(rsCfg.IntDgMap, dgName, rsCfg.Virtual.Partition, namespace,
+		getRegexPathRecordKey(rsCfg.IntDgMap[NameRef{Name: dgName, Partition: rsCfg.Virtual.Partition}], host, ruleName),
+		value, DataGroupType)
+	rsCfg.addIRule(getRSCfgResName(rsVSName, RegexPathIRuleName), rsCfg.Virtual.Partition,
+		regexPathIRule(rsVSName, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, getRSCfgResName(rsVSName, RegexPathIRuleName)))
+}
+
+// getRegexPathRecordKey returns the key of the regex path record of the rule, the key of a new record orders it after
+// the records of the host
+func getRegexPathRecordKey(nsDg DataGroupNamespaceMap, host, ruleName string) string {
+	prefix := strings.TrimPrefix(host, "*") + " "
+	order := 0
+	for _, dg := range nsDg {
+		for _, record := range dg.Records {
+			if !strings.HasPrefix(record.Name, prefix) {
+				continue
+			}
+			if strings.HasSuffix(record.Name, "_"+ruleName) {
+				return record.Name
+			}
+			order++
+		}
+	}
+	return fmt.Sprintf("%s%04d_%s", prefix, order, ruleName)
+}
+
 func createPathSegmentConditions(u *url.URL) []*condition {
 
 	var c []*condition
@@ -375,6 +778,77 @@ func getHostRewriteActions(rwHost string, actionNameIndex int) ([]*action, error
 	}}, nil
 }
 
+// getPersistAction creates the action persisting the requests to the pool, the universal and hash persistence
+// are keyed by the value of the header. Persistence profiles apply to the whole virtual, so the persistence of
+// each pool is the persist action of the policy rule selecting the pool
+func getPersistAction(persistence *cisapiv1.PoolPersistence, actionNameIndex int) []*action {
+	if persistence == nil {
+		return nil
+	}
+	a := &action{
+		Name:          fmt.Sprintf("%d", actionNameIndex),
+		Persist:       true,
+		Request:       true,
+		PersistMethod: persistence.Method,
+	}
+	switch persistence.Method {
+	case PersistenceCookieInsert:
+		a.CookieName = persistence.CookieName
+		a.Expiry = persistence.Expiry
+		return []*action{a}
+	case PersistenceSourceAddress:
+		a.Netmask = persistence.Netmask
+		if a.Netmask == "" {
+			a.Netmask = DefaultPersistenceNetmask
+		}
+	case PersistenceUniversal, PersistenceHash:
+		a.Value = fmt.Sprintf("tcl:[HTTP::header value \"%s\"]", persistence.HeaderName)
+	}
+	a.Timeout = persistence.TimeOut
+	if a.Timeout == 0 {
+		a.Timeout = DefaultPersistenceTimeout
+	}
+	return []*action{a}
+}
+
+// getHeaderActions creates the actions inserting, replacing and removing the request or response headers
+func getHeaderActions(headers *cisapiv1.HeaderActions, response bool, actionNameIndex int) []*action {
+	if headers == nil {
+		return nil
+	}
+	var actions []*action
+	newAction := func(name, value string) *action {
+		a := &action{
+			Name:       fmt.Sprintf("%d", actionNameIndex+len(actions)),
+			HTTPHeader: true,
+			HeaderName: name,
+			Value:      value,
+		}
+		if response {
+			a.Response = true
+		} else {
+			a.Request = true
+		}
+		return a
+	}
+	for _, hdr := range headers.Remove {
+		a := newAction(hdr, "")
+		a.Remove = true
+		actions = append(actions, a)
+	}
+	for _, hdr := range headers.Replace {
+		a := newAction(hdr.Name, hdr.Value)
+		a.Replace = true
+		actions = append(actions, a)
+	}
+	for _, hdr := range headers.Insert {
+		a := newAction(hdr.Name, hdr.Value)
+		a.Insert = true
+		actions = append(actions, a)
+	}
+	return actions
+}
+
 func createRedirectRule(source, target, ruleName string, allowSourceRange []string) (*Rule, error) {
 	_u := "scheme://" + source
 	_u = strings.TrimSuffix(_u, "/")
@@ -451,6 +925,19 @@ func (rules Rules) Len() int {
 func (rules Rules) Less(i, j int) bool {
 	ruleI := rules[i]
 	ruleJ := rules[j]
+	// Strategy 0: Rule matching the exact path precedes the rules matching the path prefix
+	exactPath := func(rule *Rule) bool {
+		for _, cnd := range rule.Conditions {
+			if cnd.Path && cnd.Equals {
+				return true
+			}
+		}
+		return false
+	}
+	if exactI, exactJ := exactPath(ruleI), exactPath(ruleJ); exactI != exactJ {
+		return exactI
+	}
+
 	// Strategy 1: Rule with Highest number of conditions
 	l1 := len(ruleI.Conditions)
 	l2 := len(ruleJ.Conditions)
@@ -468,11 +955,8 @@ func (rules Rules) Less(i, j int) bool {
 		}
 		return false
 	}
-	if pathExists(ruleI) {
-		return true
-	}
-	if pathExists(ruleJ) {
-		return true
+	if pathI, pathJ := pathExists(ruleI), pathExists(ruleJ); pathI != pathJ {
+		return pathI
 	}
 
 	// Strategy 3: "equal" match type takes more priority than others
@@ -609,6 +1093,117 @@ func httpRedirectIRule(port int32, rsVSName string, partition string) string {
 	return iRuleCode
 }
 
+// fixedResponseIRule responds to the requests for which the LTM policy set the fixed response rule,
+// the status code, content type and base64 encoded body of the rule are looked up in the data group
+func fixedResponseIRule(rsVSName string, partition string) string {
+	dgName := "/" + partition + "/" + rsVSName + "/" + getRSCfgResName(rsVSName, FixedResponseDgName)
+	iRuleCode := fmt.Sprintf(`
+		when HTTP_REQUEST {
+			if {[info exists %[1]s]} {
+				set response [class match -value $%[1]s equals %[2]s]
+				unset %[1]s
+				if {$response ne ""} {
+					set fields [split $response "|"]
+					HTTP::respond [lindex $fields 0] content [b64decode [lindex $fields 2]] "Content-Type" [lindex $fields 1]
+					return
+				}
+			}
+		}`, fixedResponseVariable, dgName)
+
+	return iRuleCode
+}
+
+// errorPagesIRule serves the maintenance page to all the requests in maintenance and when no pool member is available,
+// and replaces the responses of the pool members with the error pages of their status codes. The pages of the host
+// are looked up before the pages of the wildcard host and the virtual servers without host
+func errorPagesIRule(rsVSName string, partition string) string {
+	dgName := "/" + partition + "/" + rsVSName + "/" + getRSCfgResName(rsVSName, ErrorPagesDgName)
+	iRuleCode := fmt.Sprintf(`
+		when HTTP_REQUEST {
+			if {[info exists cis_error_pages_host]} {
+				unset cis_error_pages_host
+			}
+			set host [string tolower [getfield [HTTP::host] ":" 1]]
+			foreach prefix [list $host [string range $host [string first "." $host] end] ""] {
+				if {[class search -- %[1]s starts_with "$prefix "]} {
+					set cis_error_pages_host $prefix
+					break
+				}
+			}
+			if {[info exists cis_error_pages_host]} {
+				set page [class match -value -- "$cis_error_pages_host maintenance" equals %[1]s]
+				if {$page ne "" && ![HTTP::has_responded]} {
+					set fields [split $page "|"]
+					HTTP::respond [lindex $fields 0] content [b64decode [lindex $fields 2]] "Content-Type" [lindex $fields 1]
+					return
+				}
+			}
+		}
+
+		when LB_FAILED {
+			if {[info exists cis_error_pages_host]} {
+				set page [class match -value -- "$cis_error_pages_host unavailable" equals %[1]s]
+				if {$page ne ""} {
+					set fields [split $page "|"]
+					HTTP::respond [lindex $fields 0] content [b64decode [lindex $fields 2]] "Content-Type" [lindex $fields 1]
+				}
+			}
+		}
+
+		when HTTP_RESPONSE {
+			if {[info exists cis_error_pages_host]} {
+				set page [class match -value -- "$cis_error_pages_host [HTTP::status]" equals %[1]s]
+				if {$page ne ""} {
+					set fields [split $page "|"]
+					HTTP::respond [lindex $fields 0] content [b64decode [lindex $fields 2]] "Content-Type" [lindex $fields 1]
+				}
+			}
+		}`, dgName)
+
+	return iRuleCode
+}
+
+// regexPathIRule selects the pool of the first regex path matching the path of the request, the regex paths of the
+// host are looked up before the regex paths of the wildcard host and the virtual servers without host. The requests
+// of the regex path from the clients outside its allowed source ranges are rejected
+func regexPathIRule(rsVSName string, partition string) string {
+	dgName := "/" + partition + "/" + rsVSName + "/" + getRSCfgResName(rsVSName, RegexPathDgName)
+	iRuleCode := fmt.Sprintf(`
+		when HTTP_REQUEST {
+			set host [string tolower [getfield [HTTP::host] ":" 1]]
+			set paths [class search -all -value %[1]s starts_with "$host "]
+			if {$paths eq ""} {
+				set paths [class search -all -value %[1]s starts_with "[string range $host [string first "." $host] end] "]
+			}
+			if {$paths eq ""} {
+				set paths [class search -all -value %[1]s starts_with " "]
+			}
+			foreach path $paths {
+				if {[regexp -- [b64decode [lindex $path 1]] [HTTP::path]]} {
+					set source_ranges [lrange $path 2 end]
+					if {[llength $source_ranges] > 0} {
+						set allowed 0
+						foreach source_range $source_ranges {
+							if {[IP::addr [IP::client_addr] equals $source_range]} {
+								set allowed 1
+								break
+							}
+						}
+						if {!$allowed} {
+							reject
+							event disable all
+							return
+						}
+					}
+					pool [lindex $path 0]
+					break
+				}
+			}
+		}`, dgName)
+
+	return iRuleCode
+}
+
 func (ctlr *Controller) getPathBasedABDeployIRule(rsVSName string, partition string, multiPoolPersistence MultiPoolPersistence) string {
 	dgPath := strings.Join([]string{partition, rsVSName}, "/")
 
@@ -1533,6 +2128,7 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 }
 
 // updateDataGroupForABVirtualServer updates the data group map based on alternativeBackends of route.
+// The records are added for each host of the VirtualServer, the pools are named after the first host.
 func (ctlr *Controller) updateDataGroupForABVirtualServer(
 	pool *cisapiv1.VSPool,
 	dgName string,
@@ -1540,7 +2136,7 @@ func (ctlr *Controller) updateDataGroupForABVirtualServer(
 	namespace string,
 	dgMap InternalDataGroupMap,
 	port intstr.IntOrString,
-	host string,
+	hosts []string,
 	termination string,
 ) {
 	if !isVSABDeployment(pool) && ctlr.haModeType != Ratio {
@@ -1570,11 +2166,13 @@ func (ctlr *Controller) updateDataGroupForABVirtualServer(
 	if path == "/" {
 		path = ""
 	}
-	key := host + path
+	host := hosts[0]
 
 	if weightTotal == 0 {
 		// If all services have 0 weight, 503 will be returned
-		updateDataGroup(dgMap, dgName, partition, namespace, key, "", "")
+		for _, h := range hosts {
+			updateDataGroup(dgMap, dgName, partition, namespace, h+path, "", "")
+		}
 	} else {
 		// Place each service in a segment between 0.0 and 1.0 that corresponds to
 		// it's ratio percentage.  The order does not matter in regards to which
@@ -1603,7 +2201,9 @@ func (ctlr *Controller) updateDataGroupForABVirtualServer(
 			entries = append(entries, entry)
 		}
 		value := strings.Join(entries, ";")
-		updateDataGroup(dgMap, dgName,
-			partition, namespace, key, value, "string")
+		for _, h := range hosts {
+			updateDataGroup(dgMap, dgName,
+				partition, namespace, h+path, value, "string")
+		}
 	}
 }
@@ -17,12 +17,17 @@
 package controller
 
 import (
+	"context"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/ipmanager"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 
@@ -58,7 +63,7 @@ type (
 		RequestHandler         *RequestHandler
 		PoolMemberType         string
 		UseNodeInternal        bool
-		initState              bool
+		initState              atomic.Bool
 		shareNodes             bool
 		ipamHandler            *ipmanager.IPAMHandler
 		defaultRouteDomain     int
@@ -81,6 +86,20 @@ type (
 		respChan               chan *agentConfig
 		networkManager         *networkmanager.NetworkManager
 		ControllerIdentifier   string
+		trustedCertsInformer   *CfgMapInformer
+		centralManagers        CentralManagers
+		logConfig              *logConfigManager
+		// adminTokenFile holds the bearer token of the admin and debug endpoints
+		adminTokenFile string
+		// cmReadiness caches the reachability of central managers for the readiness probes
+		cmReadiness cmHealthCache
+		// standby is set when the replica doesn't hold the lease of the leader election
+		standby        atomic.Bool
+		leaderElection *leaderElection
+		// shutdownTimeout bounds the posting of the pending declarations on shutdown
+		shutdownTimeout time.Duration
+		// stopCh stops the token manager and the credentials watch of the central manager on shutdown
+		stopCh chan struct{}
 		resourceContext
 	}
 	ClientSets struct {
@@ -132,18 +151,44 @@ type (
 		MultiClusterMode      string
 		CMConfigDetails       *CMConfig
 		CMTrustedCerts        string
+		CMTrustedCertsCfgMap  string
 		CMSSLInsecure         bool
 		HttpAddress           string
 		ManageCustomResources bool
 		httpClientMetrics     bool
 		IPAMNamespace         string
+		AdminTokenFile        string
+		LeaderElection        *LeaderElectionConfig
+		ShutdownTimeout       time.Duration
+	}
+
+	// LeaderElectionConfig defines the Lease based leader election of the CIS replicas
+	LeaderElectionConfig struct {
+		LeaseName      string
+		LeaseNamespace string
+		Identity       string
+		LeaseDuration  time.Duration
+		RenewDeadline  time.Duration
+		RetryPeriod    time.Duration
+	}
+
+	// leaderElection stops the leader election on shutdown
+	leaderElection struct {
+		cancel context.CancelFunc
+		done   chan struct{}
 	}
 
 	// CMConfig defines the Central Manager config
 	CMConfig struct {
-		URL      string
-		UserName string
-		Password string
+		URL            string
+		UserName       string
+		Password       string
+		AuthType       string
+		ClientCertFile string
+		ClientKeyFile  string
+		APIToken       string
+		TokenFile      string
+		CredentialsDir string
 	}
 
 	// CRInformer defines the structure of Custom Resource Informer
@@ -154,6 +199,7 @@ type (
 		tlsInformer cache.SharedIndexInformer
 		tsInformer  cache.SharedIndexInformer
 		ilInformer  cache.SharedIndexInformer
+		cmInformer  cache.SharedIndexInformer
 	}
 
 	CommonInformer struct {
@@ -187,6 +233,31 @@ type (
 		cluster    string
 		nsInformer cache.SharedIndexInformer
 	}
+
+	// CentralManagers holds the additional central managers referenced in the bigip config
+	CentralManagers struct {
+		sync.Mutex
+		cmMap map[CMKey]*centralManager
+	}
+
+	// CMKey identifies the central manager with its credentials secret
+	CMKey struct {
+		URL               string
+		CredentialsSecret string
+	}
+
+	centralManager struct {
+		tokenManager *tokenmanager.TokenManager
+		stopCh       chan struct{}
+	}
+
+	// CfgMapInformer watches a single ConfigMap
+	CfgMapInformer struct {
+		namespace      string
+		name           string
+		stopCh         chan struct{}
+		cfgMapInformer cache.SharedIndexInformer
+	}
 	rqKey struct {
 		namespace      string
 		kind           string
@@ -249,6 +320,9 @@ type (
 		AutoLastHop                string                `json:"lastHop,omitempty"`
 		AnalyticsProfiles          AnalyticsProfiles     `json:"analyticsProfiles,omitempty"`
 		MultiPoolPersistence       MultiPoolPersistence  `json:"multiPoolPersistence,omitempty"`
+		RateLimit                  int32                 `json:"rateLimit,omitempty"`
+		IdleTimeout                int32                 `json:"idleTimeout,omitempty"`
+		ConnectTimeout             int32                 `json:"connectTimeout,omitempty"`
 	}
 	MultiPoolPersistence struct {
 		Method  string `json:"method,omitempty"`
@@ -434,6 +508,8 @@ type (
 		MultiClusterServices []cisapiv1.MultiClusterServiceReference `json:"_"`
 		Cluster              string                                  `json:"-"`
 		ConnectionLimit      int32                                   `json:"-"`
+		MinimumMembersActive int32                                   `json:"minimumMembersActive,omitempty"`
+		FallbackServices     []FallbackService                       `json:"-"`
 	}
 	CacheIPAM struct {
 		IPAM *ficV1.IPAM
@@ -445,6 +521,12 @@ type (
 		ServiceNamespace string `json:"serviceNamespace,omitempty"`
 		Weight           int32  `json:"weight,omitempty"`
 	}
+	// FallbackService is the service of the members of the pool in a lower priority group
+	FallbackService struct {
+		ServiceName      string
+		ServiceNamespace string
+		ServicePort      intstr.IntOrString
+	}
 
 	// Pools is slice of pool
 	Pools []Pool
@@ -542,6 +624,23 @@ type (
 		Enabled   *bool  `json:"enabled,omitempty"`
 		Log       bool   `json:"log,omitempty"`
 		Message   string `json:"message,omitempty"`
+		// HTTPHeader actions insert, replace or remove the header HeaderName in the request or response
+		HTTPHeader bool   `json:"httpHeader,omitempty"`
+		HeaderName string `json:"headerName,omitempty"`
+		Insert     bool   `json:"insert,omitempty"`
+		Remove     bool   `json:"remove,omitempty"`
+		Response   bool   `json:"response,omitempty"`
+		StatusCode int    `json:"statusCode,omitempty"`
+		// Tcl actions set the Tcl variable Variable to Value
+		Tcl      bool   `json:"tcl,omitempty"`
+		Variable string `json:"variable,omitempty"`
+		// Persist actions persist the requests with PersistMethod keyed by Value
+		Persist       bool   `json:"persist,omitempty"`
+		PersistMethod string `json:"persistMethod,omitempty"`
+		CookieName    string `json:"cookieName,omitempty"`
+		Expiry        string `json:"expiry,omitempty"`
+		Netmask       string `json:"netmask,omitempty"`
+		Timeout       int32  `json:"timeout,omitempty"`
 	}
 
 	// condition config for a Rule
@@ -555,6 +654,10 @@ type (
 		HTTPHost        bool     `json:"httpHost,omitempty"`
 		Host            bool     `json:"host,omitempty"`
 		HTTPURI         bool     `json:"httpUri,omitempty"`
+		HTTPHeader      bool     `json:"httpHeader,omitempty"`
+		HTTPCookie      bool     `json:"httpCookie,omitempty"`
+		HTTPMethod      bool     `json:"httpMethod,omitempty"`
+		QueryParameter  bool     `json:"queryParameter,omitempty"`
 		Index           int      `json:"index,omitempty"`
 		Matches         bool     `json:"matches,omitempty"`
 		Path            bool     `json:"path,omitempty"`
@@ -563,6 +666,7 @@ type (
 		Remote          bool     `json:"remote,omitempty"`
 		Request         bool     `json:"request,omitempty"`
 		Scheme          bool     `json:"scheme,omitempty"`
+		StartsWith      bool     `json:"startsWith,omitempty"`
 		Tcp             bool     `json:"tcp,omitempty"`
 		Values          []string `json:"values"`
 
@@ -671,6 +775,8 @@ type (
 	requestMeta struct {
 		partitionMap map[string]map[string]string
 		id           int
+		// spanContext is the span of the request, the trace continues with it across the post pipeline
+		spanContext trace.SpanContext
 	}
 
 	Node struct {
@@ -727,6 +833,10 @@ type (
 		HAMode                          bool
 		PrimaryClusterHealthProbeParams PrimaryClusterHealthProbeParams
 		httpClientMetrics               bool
+		// cmPostSlots holds the post slots shared by the post managers of a central manager
+		cmPostSlots map[string]chan struct{}
+		// pendingRequests counts the requests queued, being posted or waiting for the response handling
+		pendingRequests atomic.Int64
 	}
 
 	PostManager struct {
@@ -735,12 +845,30 @@ type (
 		tokenManager   *tokenmanager.TokenManager
 		// cachedTenantDeclMap,incomingTenantDeclMap hold tenant names and corresponding AS3 config
 		cachedTenantDeclMap map[string]as3Tenant
-		postChan            chan agentConfig
-		defaultPartition    string
-		respChan            chan *agentConfig
+		// renderedTenantDeclMap holds the last declaration rendered for each tenant, posted or not
+		renderedTenantDeclMap map[string]as3Tenant
+		tenantCacheLock       sync.RWMutex
+		postChan              chan agentConfig
+		defaultPartition      string
+		respChan              chan *agentConfig
 		PostParams
 		postManagerPrefix      string
 		tenantDeclarationIDMap map[string]string
+		// postSlots bounds the tenants posted concurrently to the central manager, nil posts tenants in a single declaration
+		postSlots chan struct{}
+		// tenantPostChans hold the post channel of each tenant, tenant configs are posted in order
+		tenantPostChans map[string]chan agentConfig
+		// lastPost holds the state of the last declaration posted for the bigip
+		lastPost     postState
+		lastPostLock sync.RWMutex
+		// pendingRequests is shared with the request handler to track the declarations till they are handled
+		pendingRequests *atomic.Int64
+	}
+
+	postState struct {
+		Time       time.Time `json:"time"`
+		StatusCode int       `json:"statusCode,omitempty"`
+		Error      string    `json:"error,omitempty"`
 	}
 
 	PostManagers struct {
@@ -751,8 +879,9 @@ type (
 		AS3VersionInfo  as3VersionInfo
 		AS3Config       cisapiv1.AS3Config
 		bigIPAS3Version float64
-		firstPost       bool
-		bigipLabel      string
+		// firstPost is read by the request handler and written by the post managers of the tenants
+		firstPost  atomic.Bool
+		bigipLabel string
 	}
 
 	PrimaryClusterHealthProbeParams struct {
@@ -771,6 +900,32 @@ type (
 		AS3Config         cisapiv1.AS3Config
 		tokenManager      *tokenmanager.TokenManager
 		UserAgent         string
+		// runtimeDebugAS3 enables the logging of AS3 requests and responses at runtime
+		runtimeDebugAS3 *atomic.Bool
+	}
+
+	// logConfigManager applies the log config changed at runtime and reverts it to the startup config
+	logConfigManager struct {
+		sync.Mutex
+		startupLogLevel log.LogLevel
+		debugAS3        *atomic.Bool
+		current         cisapiv1.LogConfig
+		source          string
+		revertAt        time.Time
+		revertTimer     *time.Timer
+		// generation discards the revert timers of the replaced log configs
+		generation int
+		// deployConfigLogConfig is the log config last seen in the DeployConfig, it's applied only when changed
+		deployConfigLogConfig cisapiv1.LogConfig
+	}
+
+	// logConfigState is the response of the admin log config endpoint
+	logConfigState struct {
+		LogLevel   string            `json:"logLevel"`
+		DebugAS3   bool              `json:"debugAS3"`
+		Components map[string]string `json:"components,omitempty"`
+		Source     string            `json:"source,omitempty"`
+		RevertAt   *time.Time        `json:"revertAt,omitempty"`
 	}
 
 	tenantResponse struct {
@@ -797,6 +952,7 @@ type (
 		failedTenants         map[string]struct{}
 		incomingTenantDeclMap map[string]as3Tenant
 		deleted               bool
+		spanContext           trace.SpanContext
 	}
 
 	//TODO L3Config to put into post channel. Handle with L3Postmanager implementation
@@ -853,14 +1009,46 @@ type (
 
 	// as3Action maps to Policy_Action in AS3 Resources
 	as3Action struct {
-		Type     string                  `json:"type,omitempty"`
-		Event    string                  `json:"event,omitempty"`
-		Select   *as3ActionForwardSelect `json:"select,omitempty"`
-		Policy   *as3ResourcePointer     `json:"policy,omitempty"`
-		Enabled  *bool                   `json:"enabled,omitempty"`
-		Location string                  `json:"location,omitempty"`
-		Replace  *as3ActionReplaceMap    `json:"replace,omitempty"`
-		Write    *as3LogMessage          `json:"write,omitempty"`
+		Type          string                  `json:"type,omitempty"`
+		Event         string                  `json:"event,omitempty"`
+		Select        *as3ActionForwardSelect `json:"select,omitempty"`
+		Policy        *as3ResourcePointer     `json:"policy,omitempty"`
+		Enabled       *bool                   `json:"enabled,omitempty"`
+		Location      string                  `json:"location,omitempty"`
+		Replace       *as3ActionReplaceMap    `json:"replace,omitempty"`
+		Insert        *as3ActionReplaceMap    `json:"insert,omitempty"`
+		Remove        *as3ActionReplaceMap    `json:"remove,omitempty"`
+		Write         *as3LogMessage          `json:"write,omitempty"`
+		Code          int                     `json:"code,omitempty"`
+		SetVariable   *as3ActionSetVariable   `json:"setVariable,omitempty"`
+		CookieInsert  *as3PersistCookie       `json:"cookieInsert,omitempty"`
+		SourceAddress *as3PersistAddress      `json:"sourceAddress,omitempty"`
+		Universal     *as3PersistKey          `json:"universal,omitempty"`
+		Hash          *as3PersistKey          `json:"hash,omitempty"`
+	}
+
+	// as3PersistCookie maps to the cookieInsert of Policy_Action_Persist in AS3 Resources
+	as3PersistCookie struct {
+		Name   string `json:"name"`
+		Expiry string `json:"expiry"`
+	}
+
+	// as3PersistAddress maps to the sourceAddress of Policy_Action_Persist in AS3 Resources
+	as3PersistAddress struct {
+		Netmask string `json:"netmask"`
+		Timeout int32  `json:"timeout"`
+	}
+
+	// as3PersistKey maps to the universal and hash of Policy_Action_Persist in AS3 Resources
+	as3PersistKey struct {
+		Key     string `json:"key"`
+		Timeout int32  `json:"timeout"`
+	}
+
+	// as3ActionSetVariable maps to the setVariable of Policy_Action_TCL in AS3 Resources
+	as3ActionSetVariable struct {
+		Name       string `json:"name"`
+		Expression string `json:"expression"`
 	}
 
 	as3ActionReplaceMap struct {
@@ -875,16 +1063,17 @@ type (
 
 	// as3Condition maps to Policy_Condition in AS3 Resources
 	as3Condition struct {
-		Type        string                  `json:"type,omitempty"`
-		Name        string                  `json:"name,omitempty"`
-		Event       string                  `json:"event,omitempty"`
-		All         *as3PolicyCompareString `json:"all,omitempty"`
-		Index       int                     `json:"index,omitempty"`
-		Host        *as3PolicyCompareString `json:"host,omitempty"`
-		PathSegment *as3PolicyCompareString `json:"pathSegment,omitempty"`
-		Path        *as3PolicyCompareString `json:"path,omitempty"`
-		ServerName  *as3PolicyCompareString `json:"serverName,omitempty"`
-		Address     *as3PolicyAddressString `json:"address,omitempty"`
+		Type           string                  `json:"type,omitempty"`
+		Name           string                  `json:"name,omitempty"`
+		Event          string                  `json:"event,omitempty"`
+		All            *as3PolicyCompareString `json:"all,omitempty"`
+		Index          int                     `json:"index,omitempty"`
+		Host           *as3PolicyCompareString `json:"host,omitempty"`
+		PathSegment    *as3PolicyCompareString `json:"pathSegment,omitempty"`
+		Path           *as3PolicyCompareString `json:"path,omitempty"`
+		QueryParameter *as3PolicyCompareString `json:"queryParameter,omitempty"`
+		ServerName     *as3PolicyCompareString `json:"serverName,omitempty"`
+		Address        *as3PolicyAddressString `json:"address,omitempty"`
 	}
 
 	// as3ActionForwardSelect maps to Policy_Action_Forward_Select in AS3 Resources
@@ -911,11 +1100,12 @@ type (
 
 	// as3Pool maps to Pool in AS3 Resources
 	as3Pool struct {
-		Class             string               `json:"class,omitempty"`
-		LoadBalancingMode string               `json:"loadBalancingMode,omitempty"`
-		Members           []as3PoolMember      `json:"members,omitempty"`
-		Monitors          []as3ResourcePointer `json:"monitors,omitempty"`
-		SlowRampTime      int32                `json:"slowRampTime,omitempty"`
+		Class                string               `json:"class,omitempty"`
+		LoadBalancingMode    string               `json:"loadBalancingMode,omitempty"`
+		Members              []as3PoolMember      `json:"members,omitempty"`
+		Monitors             []as3ResourcePointer `json:"monitors,omitempty"`
+		SlowRampTime         int32                `json:"slowRampTime,omitempty"`
+		MinimumMembersActive int32                `json:"minimumMembersActive,omitempty"`
 	}
 
 	// as3PoolMember maps to Pool_Member in AS3 Resources
@@ -926,6 +1116,7 @@ type (
 		ShareNodes       bool     `json:"shareNodes,omitempty"`
 		AdminState       string   `json:"adminState,omitempty"`
 		ConnectionLimit  int32    `json:"connectionLimit,omitempty"`
+		PriorityGroup    int32    `json:"priorityGroup,omitempty"`
 	}
 
 	// as3ResourcePointer maps to following in AS3 Resources
@@ -970,6 +1161,15 @@ type (
 		ProfileHTTP2         as3MultiTypeParam    `json:"profileHTTP2,omitempty"`
 		ProfileMultiplex     as3MultiTypeParam    `json:"profileMultiplex,omitempty"`
 		HttpAnalyticsProfile *as3ResourcePointer  `json:"profileAnalytics,omitempty"`
+		RateLimit            int32                `json:"rateLimit,omitempty"`
+	}
+
+	// as3ProtocolProfile maps to TCP_Profile and UDP_Profile in AS3 Resources
+	as3ProtocolProfile struct {
+		Class         string `json:"class"`
+		IdleTimeout   int32  `json:"idleTimeout,omitempty"`
+		SynRtoBase    int32  `json:"synRtoBase,omitempty"`
+		SynMaxRetrans int32  `json:"synMaxRetrans,omitempty"`
 	}
 
 	// as3ServiceAddress maps to VirtualAddress in AS3 Resources
@@ -1065,6 +1265,8 @@ type (
 		Session         string `json:"session,omitempty"`
 		AdminState      string `json:"adminState,omitempty"`
 		ConnectionLimit int32  `json:"connectionLimit,omitempty"`
+		PriorityGroup   int32  `json:"priorityGroup,omitempty"`
+		Zone            string `json:"zone,omitempty"`
 	}
 )
 
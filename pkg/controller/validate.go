@@ -22,6 +22,9 @@ import (
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"net"
+	"regexp"
+	"strings"
 )
 
 func (ctlr *Controller) checkValidVirtualServer(
@@ -65,7 +68,47 @@ func (ctlr *Controller) checkValidVirtualServer(
 			return false
 		}
 	}
+	if err := validateVirtualServerHosts(vsResource); err != nil {
+		log.Warningf("Invalid hosts for VirtualServer %v: %v", vsName, err)
+		return false
+	}
+	for _, headers := range []*cisapiv1.HeaderActions{vsResource.Spec.RequestHeaders, vsResource.Spec.ResponseHeaders} {
+		if err := validateHeaderActions(headers); err != nil {
+			log.Warningf("Invalid headers for VirtualServer %v: %v", vsName, err)
+			return false
+		}
+	}
+	if err := validateVirtualServerRules(vsResource); err != nil {
+		log.Warningf("Invalid rules for VirtualServer %v: %v", vsName, err)
+		return false
+	}
+	if err := validateVirtualServerErrorPages(vsResource.Spec.ErrorPages); err != nil {
+		log.Warningf("Invalid error pages for VirtualServer %v: %v", vsName, err)
+		return false
+	}
 	for _, pool := range vsResource.Spec.Pools {
+		if err := validatePoolPathType(pool); err != nil {
+			log.Warningf("Invalid path of the pool %v of VirtualServer %v: %v", pool.Path, vsName, err)
+			return false
+		}
+		if err := validatePoolMatch(pool.Match); err != nil {
+			log.Warningf("Invalid match for the pool %v of VirtualServer %v: %v", pool.Path, vsName, err)
+			return false
+		}
+		if err := validatePoolPersistence(pool.Persistence); err != nil {
+			log.Warningf("Invalid persistence for the pool %v of VirtualServer %v: %v", pool.Path, vsName, err)
+			return false
+		}
+		if err := validatePoolFallbackServices(pool.FallbackServices, pool.MinimumMembersActive); err != nil {
+			log.Warningf("Invalid fallback services for the pool %v of VirtualServer %v: %v", pool.Path, vsName, err)
+			return false
+		}
+		for _, headers := range []*cisapiv1.HeaderActions{pool.RequestHeaders, pool.ResponseHeaders} {
+			if err := validateHeaderActions(headers); err != nil {
+				log.Warningf("Invalid headers for the pool %v of VirtualServer %v: %v", pool.Path, vsName, err)
+				return false
+			}
+		}
 		if pool.MultiClusterServices == nil {
 			continue
 		}
@@ -82,6 +125,299 @@ func (ctlr *Controller) checkValidVirtualServer(
 	return true
 }
 
+// validateVirtualServerHosts validates the additional hosts of the VirtualServer, the host is required with the
+// additional hosts as the pools, monitors and IP address of the VirtualServer are named after the host
+func validateVirtualServerHosts(vs *cisapiv1.VirtualServer) error {
+	if len(vs.Spec.Hosts) == 0 {
+		return nil
+	}
+	if vs.Spec.Host == "" {
+		return fmt.Errorf("host is required with the hosts")
+	}
+	for _, host := range vs.Spec.Hosts {
+		if host == "" {
+			return fmt.Errorf("empty host in the hosts")
+		}
+		if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return fmt.Errorf("invalid wildcard host %v", host)
+		}
+	}
+	return nil
+}
+
+// cookieExpiryRegex matches the cookie expiry expressed as [Nd][HH:MM[:SS]]
+var cookieExpiryRegex = regexp.MustCompile(`^(\d+d)?(\d{1,2}:\d{2}(:\d{2})?)?$`)
+
+// headerNameRegex matches the header name as an RFC 7230 token, the header name is used in the Tcl expression of
+// the persist action
+var headerNameRegex = regexp.MustCompile(`^[!#$%&'*+.^_|~0-9A-Za-z-]+$`)
+
+// validatePoolPersistence validates the persistence method of the pool and its settings
+func validatePoolPersistence(persistence *cisapiv1.PoolPersistence) error {
+	if persistence == nil {
+		return nil
+	}
+	switch persistence.Method {
+	case PersistenceCookieInsert:
+		if persistence.CookieName == "" {
+			return fmt.Errorf("cookieName is required for the %v persistence", persistence.Method)
+		}
+		if persistence.Expiry == "" {
+			return fmt.Errorf("expiry is required for the %v persistence", persistence.Method)
+		}
+		if !cookieExpiryRegex.MatchString(persistence.Expiry) {
+			return fmt.Errorf("invalid expiry %q of the %v persistence, expected [Nd][HH:MM[:SS]]",
+				persistence.Expiry, persistence.Method)
+		}
+	case PersistenceSourceAddress:
+		if persistence.Netmask != "" && net.ParseIP(persistence.Netmask) == nil {
+			return fmt.Errorf("invalid netmask %v of the %v persistence", persistence.Netmask, persistence.Method)
+		}
+	case PersistenceUniversal, PersistenceHash:
+		if persistence.HeaderName == "" {
+			return fmt.Errorf("headerName is required for the %v persistence", persistence.Method)
+		}
+		if !headerNameRegex.MatchString(persistence.HeaderName) {
+			return fmt.Errorf("invalid headerName %q of the %v persistence", persistence.HeaderName, persistence.Method)
+		}
+	default:
+		return fmt.Errorf("invalid persistence method %v", persistence.Method)
+	}
+	if persistence.TimeOut < 0 || persistence.TimeOut > MaxPersistenceTimeout {
+		return fmt.Errorf("invalid timeOut %v of the %v persistence", persistence.TimeOut, persistence.Method)
+	}
+	return nil
+}
+
+// validatePoolFallbackServices validates the fallback services and the minimum active members of the pool
+func validatePoolFallbackServices(fallbackServices []cisapiv1.FallbackService, minimumMembersActive int32) error {
+	if minimumMembersActive < 0 {
+		return fmt.Errorf("invalid minimumMembersActive %v", minimumMembersActive)
+	}
+	if minimumMembersActive > 0 && len(fallbackServices) == 0 {
+		return fmt.Errorf("fallbackServices are required with the minimumMembersActive")
+	}
+	for _, fb := range fallbackServices {
+		if fb.Service == "" {
+			return fmt.Errorf("service is required for the fallback service")
+		}
+	}
+	return nil
+}
+
+// validatePoolPathType validates the path type of the pool, the regex paths are matched by an iRule which doesn't
+// support the other routing options of the pool
+func validatePoolPathType(pool cisapiv1.VSPool) error {
+	switch pool.PathType {
+	case "", PathTypePrefix, PathTypeExact:
+		return nil
+	case PathTypeRegex:
+		if _, err := regexp.Compile(pool.Path); err != nil {
+			return fmt.Errorf("invalid regex path %v: %v", pool.Path, err)
+		}
+		if pool.Match != nil || pool.Rewrite != "" || pool.HostRewrite != "" || pool.RequestHeaders != nil ||
+			pool.ResponseHeaders != nil || pool.Persistence != nil || pool.WAF != "" || len(pool.AlternateBackends) > 0 {
+			return fmt.Errorf("match, rewrite, hostRewrite, requestHeaders, responseHeaders, persistence, waf and " +
+				"alternateBackends are not supported with the regex path")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid path type %v", pool.PathType)
+	}
+}
+
+// validatePoolMatch validates the header, cookie, query parameter and method conditions of the pool
+func validatePoolMatch(match *cisapiv1.PoolMatch) error {
+	if match == nil {
+		return nil
+	}
+	validate := func(kind string, conditions []cisapiv1.HTTPMatchCondition) error {
+		for _, cond := range conditions {
+			if cond.Name == "" {
+				return fmt.Errorf("name is required for the %v match", kind)
+			}
+			switch cond.Type {
+			case "", MatchTypeExact, MatchTypePrefix:
+				if cond.Value == "" {
+					return fmt.Errorf("value is required for the %v match %v", kind, cond.Name)
+				}
+			case MatchTypePresent:
+			default:
+				return fmt.Errorf("invalid type %v of the %v match %v", cond.Type, kind, cond.Name)
+			}
+		}
+		return nil
+	}
+	if err := validate("header", match.Headers); err != nil {
+		return err
+	}
+	if err := validate("cookie", match.Cookies); err != nil {
+		return err
+	}
+	if err := validate("query parameter", match.QueryParameters); err != nil {
+		return err
+	}
+	for _, method := range match.Methods {
+		if method == "" {
+			return fmt.Errorf("empty method in the match")
+		}
+	}
+	return nil
+}
+
+// validateVirtualServerErrorPages validates the maintenance and error pages of the VirtualServer
+func validateVirtualServerErrorPages(errorPages *cisapiv1.ErrorPages) error {
+	if errorPages == nil {
+		return nil
+	}
+	if errorPages.Maintenance && errorPages.MaintenancePage == nil {
+		return fmt.Errorf("maintenancePage is required with the maintenance")
+	}
+	if page := errorPages.MaintenancePage; page != nil {
+		if err := validateErrorPage(page); err != nil {
+			return fmt.Errorf("invalid maintenancePage: %v", err)
+		}
+	}
+	statusCodes := make(map[int32]bool)
+	for _, errorPage := range errorPages.Pages {
+		if len(errorPage.StatusCodes) == 0 {
+			return fmt.Errorf("statusCodes are required for the error page")
+		}
+		for _, code := range errorPage.StatusCodes {
+			if code < 400 || code > 599 {
+				return fmt.Errorf("invalid status code %v of the error page", code)
+			}
+			if statusCodes[code] {
+				return fmt.Errorf("duplicate status code %v of the error pages", code)
+			}
+			statusCodes[code] = true
+		}
+		if err := validateErrorPage(&errorPage.Page); err != nil {
+			return fmt.Errorf("invalid error page of the status codes %v: %v", errorPage.StatusCodes, err)
+		}
+	}
+	return nil
+}
+
+// validateErrorPage validates the status code and the body of the page
+func validateErrorPage(page *cisapiv1.FixedResponse) error {
+	if page.StatusCode != 0 && (page.StatusCode < 100 || page.StatusCode > 599) {
+		return fmt.Errorf("invalid status code %v", page.StatusCode)
+	}
+	// the fields of the response record are separated by "|"
+	if strings.Contains(page.ContentType, "|") {
+		return fmt.Errorf("invalid content type %q", page.ContentType)
+	}
+	if page.BodyFrom != nil {
+		if page.Body != "" {
+			return fmt.Errorf("only one of body and bodyFrom is allowed")
+		}
+		if page.BodyFrom.Name == "" || page.BodyFrom.Key == "" {
+			return fmt.Errorf("name and key of the ConfigMap are required")
+		}
+	}
+	return nil
+}
+
+// redirectInvalidChars are the Tcl substitution and whitespace characters which aren't allowed in the redirect
+const redirectInvalidChars = "[]$\\ \t\r\n\v\f"
+
+// validateVirtualServerRules validates the redirect and fixed response rules of the VirtualServer
+func validateVirtualServerRules(vs *cisapiv1.VirtualServer) error {
+	poolPaths := make(map[string]bool)
+	for _, pool := range vs.Spec.Pools {
+		if pool.Match == nil && pool.PathType != PathTypeRegex {
+			poolPaths[pool.Path] = true
+		}
+	}
+	rulePaths := make(map[string]bool)
+	for _, rule := range vs.Spec.Rules {
+		if !strings.HasPrefix(rule.Path, "/") {
+			return fmt.Errorf("invalid path %v of the rule", rule.Path)
+		}
+		if poolPaths[rule.Path] {
+			return fmt.Errorf("path %v of the rule is used by a pool", rule.Path)
+		}
+		if rulePaths[rule.Path] {
+			return fmt.Errorf("duplicate path %v of the rules", rule.Path)
+		}
+		rulePaths[rule.Path] = true
+		if (rule.Redirect == nil) == (rule.FixedResponse == nil) {
+			return fmt.Errorf("either redirect or fixedResponse is required for the rule of path %v", rule.Path)
+		}
+		if redirect := rule.Redirect; redirect != nil {
+			switch redirect.StatusCode {
+			case 0, 301, 302, 307, 308:
+			default:
+				return fmt.Errorf("invalid redirect status code %v for the rule of path %v", redirect.StatusCode, rule.Path)
+			}
+			switch redirect.Scheme {
+			case "", "http", "https":
+			default:
+				return fmt.Errorf("invalid redirect scheme %v for the rule of path %v", redirect.Scheme, rule.Path)
+			}
+			if redirect.Path != "" && redirect.ReplacePrefix != "" {
+				return fmt.Errorf("only one of path and replacePrefix is allowed for the redirect of path %v", rule.Path)
+			}
+			// the location of the redirect is a Tcl expression, so the command and variable substitution characters
+			// aren't allowed
+			for _, field := range []struct{ name, value string }{
+				{"host", redirect.Host}, {"path", redirect.Path}, {"replacePrefix", redirect.ReplacePrefix}} {
+				if strings.ContainsAny(field.value, redirectInvalidChars) {
+					return fmt.Errorf("invalid redirect %v %q for the rule of path %v", field.name, field.value,
+						rule.Path)
+				}
+			}
+		}
+		if response := rule.FixedResponse; response != nil {
+			if response.StatusCode < 100 || response.StatusCode > 599 {
+				return fmt.Errorf("invalid status code %v of the fixed response for the rule of path %v",
+					response.StatusCode, rule.Path)
+			}
+			// the fields of the response record are separated by "|"
+			if strings.Contains(response.ContentType, "|") {
+				return fmt.Errorf("invalid content type %q of the fixed response for the rule of path %v",
+					response.ContentType, rule.Path)
+			}
+			if response.BodyFrom != nil {
+				if response.Body != "" {
+					return fmt.Errorf("only one of body and bodyFrom is allowed for the fixed response of path %v",
+						rule.Path)
+				}
+				if response.BodyFrom.Name == "" || response.BodyFrom.Key == "" {
+					return fmt.Errorf("name and key of the ConfigMap are required for the fixed response of path %v",
+						rule.Path)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateHeaderActions validates the headers to insert, replace and remove
+func validateHeaderActions(headers *cisapiv1.HeaderActions) error {
+	if headers == nil {
+		return nil
+	}
+	for _, hdrs := range [][]cisapiv1.HTTPHeader{headers.Insert, headers.Replace} {
+		for _, hdr := range hdrs {
+			if hdr.Name == "" {
+				return fmt.Errorf("name is required for the header to insert or replace")
+			}
+			// AS3 requires the value of the header to insert or replace
+			if hdr.Value == "" {
+				return fmt.Errorf("value is required for the header %v to insert or replace", hdr.Name)
+			}
+		}
+	}
+	for _, hdr := range headers.Remove {
+		if hdr == "" {
+			return fmt.Errorf("empty header to remove")
+		}
+	}
+	return nil
+}
+
 func (ctlr *Controller) checkValidTransportServer(
 	tsResource *cisapiv1.TransportServer,
 ) bool {
@@ -129,6 +465,12 @@ func (ctlr *Controller) checkValidTransportServer(
 		ctlr.updateResourceStatus(TransportServer, tsResource, "", "", errors.New(err))
 		return false
 	}
+	if fbErr := validatePoolFallbackServices(tsResource.Spec.Pool.FallbackServices,
+		tsResource.Spec.Pool.MinimumMembersActive); fbErr != nil {
+		err = fmt.Sprintf("Invalid fallback services for transport server %s: %v", vsName, fbErr)
+		ctlr.updateResourceStatus(TransportServer, tsResource, "", "", errors.New(err))
+		return false
+	}
 	if tsResource.Spec.Pool.MultiClusterServices != nil {
 		for _, mcs := range tsResource.Spec.Pool.MultiClusterServices {
 			err := ctlr.checkValidExtendedService(mcs)
@@ -95,4 +95,180 @@ var _ = Describe("Validation Tests", func() {
 				"HA clusters to be defined in extendedServiceReference")))
 		})
 	})
+
+	It("Validating the match of the VirtualServer pools", func() {
+		Expect(validatePoolMatch(nil)).To(Succeed())
+		Expect(validatePoolMatch(&cisapiv1.PoolMatch{
+			Headers:         []cisapiv1.HTTPMatchCondition{{Name: "x-canary", Value: "true"}},
+			Cookies:         []cisapiv1.HTTPMatchCondition{{Name: "session", Type: MatchTypePresent}},
+			QueryParameters: []cisapiv1.HTTPMatchCondition{{Name: "version", Type: MatchTypePrefix, Value: "v2"}},
+			Methods:         []string{"GET"},
+		})).To(Succeed())
+		Expect(validatePoolMatch(&cisapiv1.PoolMatch{
+			Headers: []cisapiv1.HTTPMatchCondition{{Value: "true"}},
+		})).To(MatchError("name is required for the header match"))
+		Expect(validatePoolMatch(&cisapiv1.PoolMatch{
+			Cookies: []cisapiv1.HTTPMatchCondition{{Name: "session", Type: MatchTypeExact}},
+		})).To(MatchError("value is required for the cookie match session"))
+		Expect(validatePoolMatch(&cisapiv1.PoolMatch{
+			QueryParameters: []cisapiv1.HTTPMatchCondition{{Name: "version", Type: "regex", Value: "v[0-9]"}},
+		})).To(MatchError("invalid type regex of the query parameter match version"))
+		Expect(validatePoolMatch(&cisapiv1.PoolMatch{
+			Headers: []cisapiv1.HTTPMatchCondition{{Name: "x-canary", Type: "suffix", Value: "true"}},
+		})).To(MatchError("invalid type suffix of the header match x-canary"))
+	})
+
+	It("Validating the persistence of the VirtualServer pools", func() {
+		Expect(validatePoolPersistence(nil)).To(Succeed())
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceCookieInsert,
+			CookieName: "session", Expiry: "1d"})).To(Succeed())
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceCookieInsert,
+			CookieName: "session", Expiry: "12:30:00"})).To(Succeed())
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceSourceAddress,
+			Netmask: "255.255.255.0", TimeOut: 300})).To(Succeed())
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceHash,
+			HeaderName: "X-User"})).To(Succeed())
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceCookieInsert})).To(
+			MatchError("cookieName is required for the cookieInsert persistence"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceCookieInsert,
+			CookieName: "session"})).To(MatchError("expiry is required for the cookieInsert persistence"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceCookieInsert,
+			CookieName: "session", Expiry: "1 day"})).To(
+			MatchError(`invalid expiry "1 day" of the cookieInsert persistence, expected [Nd][HH:MM[:SS]]`))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceSourceAddress,
+			Netmask: "/24"})).To(MatchError("invalid netmask /24 of the sourceAddress persistence"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceUniversal})).To(
+			MatchError("headerName is required for the universal persistence"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceHash,
+			HeaderName: `x"] [HTTP::respond 200] ["`})).To(
+			MatchError(`invalid headerName "x\"] [HTTP::respond 200] [\"" of the hash persistence`))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: "ssl"})).To(
+			MatchError("invalid persistence method ssl"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceSourceAddress,
+			TimeOut: -1})).To(MatchError("invalid timeOut -1 of the sourceAddress persistence"))
+		Expect(validatePoolPersistence(&cisapiv1.PoolPersistence{Method: PersistenceHash, HeaderName: "X-User",
+			TimeOut: 65536})).To(MatchError("invalid timeOut 65536 of the hash persistence"))
+	})
+
+	It("Validating the fallback services of the pools", func() {
+		Expect(validatePoolFallbackServices(nil, 0)).To(Succeed())
+		Expect(validatePoolFallbackServices([]cisapiv1.FallbackService{{Service: "svc2"},
+			{Service: "svc3", ServiceNamespace: "backup"}}, 2)).To(Succeed())
+		Expect(validatePoolFallbackServices(nil, 2)).To(
+			MatchError("fallbackServices are required with the minimumMembersActive"))
+		Expect(validatePoolFallbackServices([]cisapiv1.FallbackService{{Service: "svc2"}}, -1)).To(
+			MatchError("invalid minimumMembersActive -1"))
+		Expect(validatePoolFallbackServices([]cisapiv1.FallbackService{{ServiceNamespace: "backup"}}, 0)).To(
+			MatchError("service is required for the fallback service"))
+	})
+
+	It("Validating the hosts of the VirtualServer", func() {
+		vs := &cisapiv1.VirtualServer{Spec: cisapiv1.VirtualServerSpec{
+			Host:  "test.com",
+			Hosts: []string{"foo.com", "*.example.com"},
+		}}
+		Expect(validateVirtualServerHosts(vs)).To(Succeed())
+		vs.Spec.Hosts = []string{"foo.*.com"}
+		Expect(validateVirtualServerHosts(vs)).To(MatchError("invalid wildcard host foo.*.com"))
+		vs.Spec.Hosts = []string{""}
+		Expect(validateVirtualServerHosts(vs)).To(MatchError("empty host in the hosts"))
+		vs.Spec.Host = ""
+		vs.Spec.Hosts = []string{"foo.com"}
+		Expect(validateVirtualServerHosts(vs)).To(MatchError("host is required with the hosts"))
+	})
+
+	It("Validating the path type of the VirtualServer pools", func() {
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/foo"})).To(Succeed())
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/foo", PathType: PathTypeExact})).To(Succeed())
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/api/v[0-9]+/.*", PathType: PathTypeRegex})).To(Succeed())
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/foo", PathType: "Suffix"})).To(MatchError(
+			"invalid path type Suffix"))
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/api/v[0-9", PathType: PathTypeRegex})).NotTo(Succeed())
+		Expect(validatePoolPathType(cisapiv1.VSPool{Path: "/api/.*", PathType: PathTypeRegex, Rewrite: "/"})).To(
+			MatchError("match, rewrite, hostRewrite, requestHeaders, responseHeaders, persistence, waf and " +
+				"alternateBackends are not supported with the regex path"))
+	})
+
+	It("Validating the header actions of the VirtualServer", func() {
+		Expect(validateHeaderActions(nil)).To(Succeed())
+		Expect(validateHeaderActions(&cisapiv1.HeaderActions{
+			Insert:  []cisapiv1.HTTPHeader{{Name: "Strict-Transport-Security", Value: "max-age=31536000"}},
+			Replace: []cisapiv1.HTTPHeader{{Name: "X-Forwarded-Proto", Value: "https"}},
+			Remove:  []string{"Server"},
+		})).To(Succeed())
+		Expect(validateHeaderActions(&cisapiv1.HeaderActions{
+			Replace: []cisapiv1.HTTPHeader{{Value: "https"}},
+		})).To(MatchError("name is required for the header to insert or replace"))
+		Expect(validateHeaderActions(&cisapiv1.HeaderActions{
+			Insert: []cisapiv1.HTTPHeader{{Name: "X-Frame-Options"}},
+		})).To(MatchError("value is required for the header X-Frame-Options to insert or replace"))
+		Expect(validateHeaderActions(&cisapiv1.HeaderActions{
+			Remove: []string{""},
+		})).To(MatchError("empty header to remove"))
+	})
+
+	It("Validating the error pages of the VirtualServer", func() {
+		Expect(validateVirtualServerErrorPages(nil)).To(Succeed())
+		errorPages := &cisapiv1.ErrorPages{
+			Maintenance:     true,
+			MaintenancePage: &cisapiv1.FixedResponse{Body: "Under maintenance"},
+			Pages: []cisapiv1.ErrorPage{
+				{StatusCodes: []int32{502, 504}, Page: cisapiv1.FixedResponse{Body: "Try again later"}},
+				{StatusCodes: []int32{404}, Page: cisapiv1.FixedResponse{StatusCode: 200,
+					BodyFrom: &cisapiv1.ConfigMapKeyReference{Name: "pages", Key: "404.html"}}},
+			},
+		}
+		Expect(validateVirtualServerErrorPages(errorPages)).To(Succeed())
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{Maintenance: true})).To(
+			MatchError("maintenancePage is required with the maintenance"))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{MaintenancePage: &cisapiv1.FixedResponse{
+			StatusCode: 99}})).To(MatchError("invalid maintenancePage: invalid status code 99"))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{MaintenancePage: &cisapiv1.FixedResponse{
+			ContentType: "text/html|200"}})).To(MatchError(`invalid maintenancePage: invalid content type "text/html|200"`))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{Pages: []cisapiv1.ErrorPage{{}}})).To(
+			MatchError("statusCodes are required for the error page"))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{Pages: []cisapiv1.ErrorPage{
+			{StatusCodes: []int32{302}}}})).To(MatchError("invalid status code 302 of the error page"))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{Pages: []cisapiv1.ErrorPage{
+			{StatusCodes: []int32{502}}, {StatusCodes: []int32{500, 502}}}})).To(
+			MatchError("duplicate status code 502 of the error pages"))
+		Expect(validateVirtualServerErrorPages(&cisapiv1.ErrorPages{Pages: []cisapiv1.ErrorPage{
+			{StatusCodes: []int32{502}, Page: cisapiv1.FixedResponse{Body: "Bad gateway",
+				BodyFrom: &cisapiv1.ConfigMapKeyReference{Name: "pages", Key: "502.html"}}}}})).To(
+			MatchError("invalid error page of the status codes [502]: only one of body and bodyFrom is allowed"))
+	})
+
+	It("Validating the rules of the VirtualServer", func() {
+		vs := &cisapiv1.VirtualServer{Spec: cisapiv1.VirtualServerSpec{
+			Pools: []cisapiv1.VSPool{{Path: "/foo", Service: "svc1"}},
+			Rules: []cisapiv1.VSRule{
+				{Path: "/old", Redirect: &cisapiv1.RuleRedirect{ReplacePrefix: "/new", StatusCode: 308}},
+				{Path: "/admin", FixedResponse: &cisapiv1.FixedResponse{StatusCode: 403, Body: "forbidden"}},
+			},
+		}}
+		Expect(validateVirtualServerRules(vs)).To(Succeed())
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/foo", Redirect: &cisapiv1.RuleRedirect{Path: "/bar"}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("path /foo of the rule is used by a pool"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old"}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("either redirect or fixedResponse is required for the rule of path /old"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old", Redirect: &cisapiv1.RuleRedirect{StatusCode: 303}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("invalid redirect status code 303 for the rule of path /old"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old", Redirect: &cisapiv1.RuleRedirect{Path: "/", ReplacePrefix: "/new"}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("only one of path and replacePrefix is allowed for the redirect of path /old"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old", Redirect: &cisapiv1.RuleRedirect{Host: "[HTTP::respond 200]"}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError(`invalid redirect host "[HTTP::respond 200]" for the rule of path /old`))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old", Redirect: &cisapiv1.RuleRedirect{ReplacePrefix: "/$a b"}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError(`invalid redirect replacePrefix "/$a b" for the rule of path /old`))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/old", Redirect: &cisapiv1.RuleRedirect{Path: "/new"}},
+			{Path: "/old", FixedResponse: &cisapiv1.FixedResponse{StatusCode: 410}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("duplicate path /old of the rules"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/admin", FixedResponse: &cisapiv1.FixedResponse{StatusCode: 403,
+			ContentType: "text/html|500"}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError(`invalid content type "text/html|500" of the fixed response for the rule of path /admin`))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/admin", FixedResponse: &cisapiv1.FixedResponse{}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("invalid status code 0 of the fixed response for the rule of path /admin"))
+		vs.Spec.Rules = []cisapiv1.VSRule{{Path: "/admin", FixedResponse: &cisapiv1.FixedResponse{StatusCode: 503,
+			BodyFrom: &cisapiv1.ConfigMapKeyReference{Name: "pages"}}}}
+		Expect(validateVirtualServerRules(vs)).To(MatchError("name and key of the ConfigMap are required for the fixed response of path /admin"))
+	})
 })
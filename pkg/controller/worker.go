@@ -27,6 +27,7 @@ import (
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/ipmanager"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/statusmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tracing"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"reflect"
@@ -49,6 +50,10 @@ import (
 
 // nextGenResourceWorker starts the Custom Resource Worker.
 func (ctlr *Controller) nextGenResourceWorker() {
+	// the worker isn't restarted once the resource queue is shut down
+	if ctlr.resourceQueue.ShuttingDown() {
+		return
+	}
 	ctlr.initNextGenResourceWorker()
 	for ctlr.processResources() {
 	}
@@ -56,7 +61,7 @@ func (ctlr *Controller) nextGenResourceWorker() {
 
 // initNextGenResourceWorker initializes the nextGenResourceWorker.
 func (ctlr *Controller) initNextGenResourceWorker() {
-	log.Debugf("Starting resource worker")
+	workerLog.Debugf("Starting resource worker")
 	ctlr.setInitialResourceCount()
 	// process the DeployConfig CR if present
 	ctlr.processGlobalDeployConfigCR()
@@ -70,8 +75,12 @@ func (ctlr *Controller) initNextGenResourceWorker() {
 	// process static routes after DeployConfig CR if present is processed to support external cluster static routes during cis init
 	ctlr.processStaticRouteUpdate()
 
-	log.Infof("Started Controller")
-	// update the status of the controller
+	workerLog.Infof("Started Controller")
+	ctlr.updateControllerStatus()
+}
+
+// updateControllerStatus updates the status of the controller in the DeployConfig CR
+func (ctlr *Controller) updateControllerStatus() {
 	ctlr.CMTokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.ControllerStatus{
 		Type:        ctlr.multiClusterMode,
 		Message:     Ok,
@@ -162,7 +171,7 @@ func (ctlr *Controller) setInitialResourceCount() {
 
 // function to process the keys at startup time
 func (ctlr *Controller) processKeyAtInitTime(rKey *rqKey) bool {
-	if ctlr.initState && rKey.kind != Namespace {
+	if ctlr.initState.Load() && rKey.kind != Namespace && rKey.kind != LeaderElected {
 		if rKey.kind == VirtualServer || rKey.kind == TransportServer || rKey.kind == Service ||
 			rKey.kind == IngressLink || rKey.kind == Route || rKey.kind == ExternalDNS {
 			if rKey.kind == Service {
@@ -179,7 +188,7 @@ func (ctlr *Controller) processKeyAtInitTime(rKey *rqKey) bool {
 				ctlr.initialResourceCount--
 			}
 			if ctlr.initialResourceCount <= 0 {
-				ctlr.initState = false
+				ctlr.initState.Store(false)
 			}
 		} else {
 			return true
@@ -195,19 +204,36 @@ func (ctlr *Controller) processResources() bool {
 	key, quit := ctlr.resourceQueue.Get()
 	if quit {
 		// The controller is shutting down.
-		log.Debugf("Resource Queue is empty, Going to StandBy Mode")
+		workerLog.Debugf("Resource Queue is empty, Going to StandBy Mode")
 		return false
 	}
 	var isRetryableError bool
 
 	defer ctlr.resourceQueue.Done(key)
+	// debug reads of the worker state aren't resources to process
+	if rKey, ok := key.(*rqKey); ok && rKey.kind == debugRead {
+		rKey.rsc.(debugReadRequest).run()
+		return true
+	}
 	// If CIS resources like CRDS, routes or servicetype LB are not present
 	// on startup, check initalresourcecount and update initState
 	if ctlr.initialResourceCount <= 0 {
-		ctlr.initState = false
+		ctlr.initState.Store(false)
 	}
 	rKey := key.(*rqKey)
-	log.Debugf("Processing Key: %v", rKey)
+	prometheus.ResourceQueueDepth.Set(float64(ctlr.resourceQueue.Len()))
+	startTime := time.Now()
+	defer func() {
+		prometheus.ResourceProcessingDuration.WithLabelValues(rKey.kind).Observe(time.Since(startTime).Seconds())
+	}()
+	ctx, span := tracing.Start(context.Background(), "reconcile",
+		tracing.ResourceKind.String(rKey.kind),
+		tracing.ResourceNamespace.String(rKey.namespace),
+		tracing.ResourceName.String(rKey.rscName),
+		tracing.ResourceEvent.String(rKey.event))
+	defer span.End()
+	rscLog := resourceLogger(rKey.kind, rKey.namespace, rKey.rscName, rKey.clusterName)
+	rscLog.Debugf("Processing Key: %v", rKey)
 	// During Init time, just process all the resources
 	if ctlr.processKeyAtInitTime(rKey) {
 		return true
@@ -218,6 +244,8 @@ func (ctlr *Controller) processResources() bool {
 		rscDelete = true
 	}
 
+	// span of building the resource config from the resource
+	_, buildSpan := tracing.Start(ctx, "resourceconfig.build")
 	// Check the type of resource and process accordingly.
 	switch rKey.kind {
 	case Route:
@@ -242,6 +270,7 @@ func (ctlr *Controller) processResources() bool {
 			delete(ctlr.resources.processedNativeResources, resourceKey)
 			// Delete the route entry from hostPath Map
 			ctlr.deleteHostPathMapEntry(route)
+			prometheus.ClearConfigurationWarnings(Route, route.Namespace, route.Name)
 		}
 		if rKey.event != Create {
 			// update the poolMem cache, clusterSvcResource & resource-svc maps
@@ -321,6 +350,19 @@ func (ctlr *Controller) processResources() bool {
 				isRetryableError = true
 			}
 		}
+	case ConfigMap:
+		if !ctlr.managedResources.ManageVirtualServer {
+			break
+		}
+		cm := rKey.rsc.(*v1.ConfigMap)
+		virtuals := getVirtualServersForConfigMap(ctlr.getAllVirtualServers(cm.Namespace), cm)
+		for _, virtual := range virtuals {
+			err := ctlr.processVirtualServers(virtual, false)
+			if err != nil {
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
 	case K8sSecret:
 		secret := rKey.rsc.(*v1.Secret)
 		mcc := ctlr.getClusterForSecret(secret)
@@ -328,7 +370,7 @@ func (ctlr *Controller) processResources() bool {
 		if mcc != (cisapiv1.ExternalClusterConfig{}) {
 			err := ctlr.updateClusterConfigStore(secret, mcc, rscDelete)
 			if err != nil {
-				log.Warningf(err.Error())
+				rscLog.Warningf(err.Error())
 			}
 			break
 		}
@@ -395,8 +437,8 @@ func (ctlr *Controller) processResources() bool {
 			break
 		}
 		ingLink := rKey.rsc.(*cisapiv1.IngressLink)
-		log.Infof("Worker got IngressLink: %v\n", ingLink)
-		log.Infof("IngressLink Selector: %v\n", ingLink.Spec.Selector.String())
+		rscLog.Infof("Worker got IngressLink: %v\n", ingLink)
+		rscLog.Infof("IngressLink Selector: %v\n", ingLink.Spec.Selector.String())
 		if rKey.event != Create {
 			rsRef := resourceRef{
 				name:      ingLink.Name,
@@ -491,7 +533,7 @@ func (ctlr *Controller) processResources() bool {
 
 		// Don't process the service as it's not used by any resource
 		if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
-			log.Debugf("Skipping service '%v' as it's not used by any CIS monitored resource", svcKey)
+			rscLog.Debugf("Skipping service '%v' as it's not used by any CIS monitored resource", svcKey)
 			break
 		}
 
@@ -514,7 +556,7 @@ func (ctlr *Controller) processResources() bool {
 		}
 		// Don't process the service as it's not used by any resource
 		if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
-			log.Debugf("Skipping endpoint '%v/%v' as it's not used by any CIS monitored resource", ep.Namespace, ep.Name)
+			rscLog.Debugf("Skipping endpoint '%v/%v' as it's not used by any CIS monitored resource", ep.Namespace, ep.Name)
 			break
 		}
 		_ = ctlr.processService(svc, rKey.clusterName)
@@ -544,7 +586,7 @@ func (ctlr *Controller) processResources() bool {
 		}
 		// Don't process the service as it's not used by any resource
 		if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
-			log.Debugf("Skipping pod '%v/%v' as it's not used by any CIS monitored resource", pod.Namespace, pod.Name)
+			rscLog.Debugf("Skipping pod '%v/%v' as it's not used by any CIS monitored resource", pod.Namespace, pod.Name)
 			break
 		}
 		_ = ctlr.processService(svc, rKey.clusterName)
@@ -582,14 +624,14 @@ func (ctlr *Controller) processResources() bool {
 				ctlr.namespacesMutex.Lock()
 				delete(ctlr.namespaces, nsName)
 				ctlr.namespacesMutex.Unlock()
-				log.Debugf("Removed namespace: '%v' from CIS scope", nsName)
+				rscLog.Debugf("Removed namespace: '%v' from CIS scope", nsName)
 				triggerDelete = true
 			} else {
 				ctlr.namespacesMutex.Lock()
 				ctlr.namespaces[nsName] = true
 				ctlr.namespacesMutex.Unlock()
 				_ = ctlr.addNamespacedInformers(nsName, true)
-				log.Debugf("Added namespace: '%v' to CIS scope", nsName)
+				rscLog.Debugf("Added namespace: '%v' to CIS scope", nsName)
 			}
 			if ctlr.namespaceLabelMode {
 				ctlr.processGlobalDeployConfigCR()
@@ -629,17 +671,28 @@ func (ctlr *Controller) processResources() bool {
 				ctlr.namespacesMutex.Lock()
 				delete(ctlr.namespaces, nsName)
 				ctlr.namespacesMutex.Unlock()
-				log.Debugf("Removed namespace: '%v' from CIS scope", nsName)
+				rscLog.Debugf("Removed namespace: '%v' from CIS scope", nsName)
 			} else {
 				ctlr.namespacesMutex.Lock()
 				ctlr.namespaces[nsName] = true
 				ctlr.namespacesMutex.Unlock()
 				_ = ctlr.addNamespacedInformers(nsName, true)
-				log.Debugf("Added namespace: '%v' to CIS scope", nsName)
+				rscLog.Debugf("Added namespace: '%v' to CIS scope", nsName)
 			}
 		}
 	case HACIS:
-		log.Debugf("posting declaration on primary cluster down event")
+		rscLog.Debugf("posting declaration on primary cluster down event")
+	case LeaderElected:
+		// the standby replica didn't process the static routes and the status, and didn't request the IPs
+		ctlr.processStaticRouteUpdate()
+		ctlr.updateControllerStatus()
+		if ctlr.ipamHandler != nil {
+			_ = ctlr.ipamHandler.CreateIPAMResource()
+			ctlr.ipamHandler.RequestPendingIPs()
+		}
+		// re-process the VS and TS resources to write their status skipped on the standby replica
+		ctlr.reprocessAllCustomResourcesOnConfigCRUpdate()
+		rscLog.Debugf("posting declaration on leader elected event")
 	case NodeUpdate:
 		if &ctlr.multiClusterResources.clusterSvcMap != nil {
 			if svcKeys, ok := ctlr.multiClusterResources.clusterSvcMap[rKey.clusterName]; ok {
@@ -648,22 +701,24 @@ func (ctlr *Controller) processResources() bool {
 				}
 			}
 		}
-		log.Debugf("posting declaration on node update")
+		rscLog.Debugf("posting declaration on node update")
 	default:
-		log.Errorf("Unknown resource Kind: %v", rKey.kind)
+		rscLog.Errorf("Unknown resource Kind: %v", rKey.kind)
 	}
+	buildSpan.SetAttributes(tracing.Retry.Bool(isRetryableError))
+	buildSpan.End()
 
 	if isRetryableError {
 		ctlr.resourceQueue.AddRateLimited(key)
 	} else {
 		ctlr.resourceQueue.Forget(key)
 	}
-	if ctlr.initState {
+	if ctlr.initState.Load() {
 		return true
 	}
 
 	if (ctlr.resourceQueue.Len() == 0) ||
-		(ctlr.multiClusterMode == SecondaryCIS && rKey.kind == HACIS) {
+		(ctlr.multiClusterMode == SecondaryCIS && rKey.kind == HACIS) || rKey.kind == LeaderElected {
 
 		if ctlr.multiClusterMode != "" {
 			// only standalone CIS & Primary CIS should post the teems data
@@ -682,24 +737,40 @@ func (ctlr *Controller) processResources() bool {
 		// set prometheus resource metrics
 		ctlr.setPrometheusResourceCount()
 		// Put each BIGIPConfig per bigip  pair into specific requestChannel
+		// standby replica keeps the caches updated but only the leader posts, the new leader posts all the configs
 		for bigip, bigipConfig := range ctlr.resources.bigIpMap {
-			if (!reflect.DeepEqual(bigipConfig.ltmConfig, LTMConfig{}) || !reflect.DeepEqual(bigipConfig.gtmConfig, GTMConfig{})) && ctlr.resources.isConfigUpdated(bigip) {
+			if !ctlr.isLeader() {
+				break
+			}
+			if (!reflect.DeepEqual(bigipConfig.ltmConfig, LTMConfig{}) || !reflect.DeepEqual(bigipConfig.gtmConfig, GTMConfig{})) &&
+				(ctlr.resources.isConfigUpdated(bigip) || rKey.kind == LeaderElected) {
 				config := ResourceConfigRequest{
 					bigIpConfig:         bigip,
 					bigIpResourceConfig: bigipConfig,
 					poolMemberType:      ctlr.PoolMemberType,
 				}
 				config.reqMeta = ctlr.enqueueReq(bigipConfig, bigip)
+				config.reqMeta.spanContext = span.SpanContext()
 				ctlr.RequestHandler.EnqueueRequestConfig(config)
 			}
 		}
-		ctlr.initState = false
+		ctlr.initState.Store(false)
 		ctlr.resources.updateCaches()
 
 	}
 	return true
 }
 
+// resourceLogger returns the log entry with the resource as fields
+func resourceLogger(kind, namespace, name, clusterName string) *log.Entry {
+	return workerLog.WithFields(log.Fields{
+		log.FieldKind:      kind,
+		log.FieldNamespace: namespace,
+		log.FieldName:      name,
+		log.FieldCluster:   clusterName,
+	})
+}
+
 // getServiceForEndpoints returns the service associated with endpoints.
 func (ctlr *Controller) getServiceForEndpoints(ep *v1.Endpoints, clusterName string) *v1.Service {
 	var svc interface{}
@@ -709,25 +780,25 @@ func (ctlr *Controller) getServiceForEndpoints(ep *v1.Endpoints, clusterName str
 	if clusterName == "" {
 		comInf, ok := ctlr.getNamespacedCommonInformer(ep.Namespace)
 		if !ok {
-			log.Errorf("Informer not found for namespace: %v", ep.Namespace)
+			workerLog.Errorf("Informer not found for namespace: %v", ep.Namespace)
 			return nil
 		}
 		svc, exists, err = comInf.svcInformer.GetIndexer().GetByKey(svcKey)
 	} else {
 		poolInf, ok := ctlr.getNamespaceMultiClusterPoolInformer(ep.Namespace, clusterName)
 		if !ok {
-			log.Errorf("[MultiCluster] Informer not found for namespace %v and cluster %v", ep.Namespace, clusterName)
+			workerLog.Errorf("[MultiCluster] Informer not found for namespace %v and cluster %v", ep.Namespace, clusterName)
 			return nil
 		}
 		svc, exists, err = poolInf.svcInformer.GetIndexer().GetByKey(svcKey)
 	}
 	if err != nil {
-		log.Infof("%v Error fetching service %v %v from the store: %v", ctlr.getMultiClusterLog(), svcKey,
+		workerLog.Infof("%v Error fetching service %v %v from the store: %v", ctlr.getMultiClusterLog(), svcKey,
 			getClusterLog(clusterName), err)
 		return nil
 	}
 	if !exists {
-		log.Infof("%v Service %v %v doesn't exist", ctlr.getMultiClusterLog(), svcKey, getClusterLog(clusterName))
+		workerLog.Infof("%v Service %v %v doesn't exist", ctlr.getMultiClusterLog(), svcKey, getClusterLog(clusterName))
 		return nil
 	}
 	return svc.(*v1.Service)
@@ -739,7 +810,7 @@ func (ctlr *Controller) getVirtualsForTLSProfile(tls *cisapiv1.TLSProfile) []*ci
 
 	allVirtuals := ctlr.getAllVirtualServers(tls.ObjectMeta.Namespace)
 	if nil == allVirtuals {
-		log.Infof("No VirtualServers found in namespace %s",
+		workerLog.Infof("No VirtualServers found in namespace %s",
 			tls.ObjectMeta.Namespace)
 		return nil
 	}
@@ -747,7 +818,7 @@ func (ctlr *Controller) getVirtualsForTLSProfile(tls *cisapiv1.TLSProfile) []*ci
 	// find VirtualServers that reference the TLSProfile
 	virtualsForTLSProfile := getVirtualServersForTLSProfile(allVirtuals, tls)
 	if nil == virtualsForTLSProfile {
-		log.Infof("Change in TLSProfile %s does not effect any VirtualServer",
+		workerLog.Infof("Change in TLSProfile %s does not effect any VirtualServer",
 			tls.ObjectMeta.Name)
 		return nil
 	}
@@ -781,7 +852,7 @@ func (ctlr *Controller) getVirtualsForTLSProfile(tls *cisapiv1.TLSProfile) []*ci
 func (ctlr *Controller) getTransportServersForCustomPolicy(plc *cisapiv1.Policy) []*cisapiv1.TransportServer {
 	nsVirtuals := ctlr.getAllTransportServers(plc.Namespace)
 	if nil == nsVirtuals {
-		log.Infof("No VirtualServers found in namespace %s",
+		workerLog.Infof("No VirtualServers found in namespace %s",
 			plc.Namespace)
 		return nil
 	}
@@ -795,7 +866,7 @@ func (ctlr *Controller) getTransportServersForCustomPolicy(plc *cisapiv1.Policy)
 		}
 	}
 
-	log.Debugf("VirtualServers %v are affected with Custom Policy %s: ",
+	workerLog.Debugf("VirtualServers %v are affected with Custom Policy %s: ",
 		plcVSNames, plc.Name)
 
 	return plcVSs
@@ -805,7 +876,7 @@ func (ctlr *Controller) getTransportServersForCustomPolicy(plc *cisapiv1.Policy)
 func (ctlr *Controller) getLBServicesForCustomPolicy(plc *cisapiv1.Policy) []*v1.Service {
 	LBServices := ctlr.getAllLBServices(plc.Namespace)
 	if nil == LBServices {
-		log.Infof("No LB service found in namespace %s",
+		workerLog.Infof("No LB service found in namespace %s",
 			plc.Namespace)
 		return nil
 	}
@@ -819,7 +890,7 @@ func (ctlr *Controller) getLBServicesForCustomPolicy(plc *cisapiv1.Policy) []*v1
 		}
 	}
 
-	log.Debugf("LB Services %v are affected with Custom Policy %s: ",
+	workerLog.Debugf("LB Services %v are affected with Custom Policy %s: ",
 		plcSvcNames, plc.Name)
 
 	return plcSvcs
@@ -831,11 +902,11 @@ func (ctlr *Controller) getAllVirtualServers(namespace string) []*cisapiv1.Virtu
 
 	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	if crInf.vsInformer == nil {
-		log.Errorf("virtual server informer not found for namespace: %v ", namespace)
+		workerLog.Errorf("virtual server informer not found for namespace: %v ", namespace)
 		return nil
 	}
 	var orderedVSs []interface{}
@@ -846,7 +917,7 @@ func (ctlr *Controller) getAllVirtualServers(namespace string) []*cisapiv1.Virtu
 		// Get list of VirtualServers and process them.
 		orderedVSs, err = crInf.vsInformer.GetIndexer().ByIndex("namespace", namespace)
 		if err != nil {
-			log.Errorf("Unable to get list of VirtualServers for namespace '%v': %v",
+			workerLog.Errorf("Unable to get list of VirtualServers for namespace '%v': %v",
 				namespace, err)
 			return nil
 		}
@@ -875,6 +946,33 @@ func (ctlr *Controller) getAllVSFromMonitoredNamespaces() []*cisapiv1.VirtualSer
 
 // getVirtualServersForTLS returns list of VirtualServers that are
 // affected by the TLSProfile under process.
+// getVirtualServersForConfigMap returns the VirtualServers which refer to the ConfigMap
+func getVirtualServersForConfigMap(allVirtuals []*cisapiv1.VirtualServer, cm *v1.ConfigMap) []*cisapiv1.VirtualServer {
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range allVirtuals {
+		if vs.Namespace != cm.Namespace {
+			continue
+		}
+		var responses []*cisapiv1.FixedResponse
+		for _, vsRule := range vs.Spec.Rules {
+			responses = append(responses, vsRule.FixedResponse)
+		}
+		if errorPages := vs.Spec.ErrorPages; errorPages != nil {
+			responses = append(responses, errorPages.MaintenancePage)
+			for i := range errorPages.Pages {
+				responses = append(responses, &errorPages.Pages[i].Page)
+			}
+		}
+		for _, response := range responses {
+			if response != nil && response.BodyFrom != nil && response.BodyFrom.Name == cm.Name {
+				result = append(result, vs)
+				break
+			}
+		}
+	}
+	return result
+}
+
 func getVirtualServersForTLSProfile(allVirtuals []*cisapiv1.VirtualServer,
 	tls *cisapiv1.TLSProfile) []*cisapiv1.VirtualServer {
 
@@ -886,14 +984,19 @@ func getVirtualServersForTLSProfile(allVirtuals []*cisapiv1.VirtualServer,
 		if vs.ObjectMeta.Namespace == tlsNamespace && vs.Spec.TLSProfileName == tlsName {
 			found := false
 			for _, host := range tls.Spec.Hosts {
-				if vs.Spec.Host == host {
+				for _, vsHost := range getVirtualServerHosts(vs) {
+					if vsHost == host {
+						found = true
+						break
+					}
+				}
+				if found {
 					result = append(result, vs)
-					found = true
 					break
 				}
 			}
 			if !found {
-				log.Errorf("TLSProfile hostname is not same as virtual host %s for profile %s", vs.Spec.Host, vs.Spec.TLSProfileName)
+				workerLog.Errorf("TLSProfile hostname is not same as virtual host %s for profile %s", vs.Spec.Host, vs.Spec.TLSProfileName)
 			}
 		}
 	}
@@ -908,7 +1011,7 @@ func (ctlr *Controller) getTerminationFromTLSProfileForVirtualServer(vs *cisapiv
 	// get TLSProfile for VirtualServer
 	tlsProfile, err := ctlr.getTLSProfile(vs.Spec.TLSProfileName, vs.Namespace)
 	if err != nil {
-		log.Errorf("Error fetching TLSProfile %s: %v", vs.Spec.TLSProfileName, err)
+		workerLog.Errorf("Error fetching TLSProfile %s: %v", vs.Spec.TLSProfileName, err)
 		return ""
 	}
 	return tlsProfile.Spec.TLS.Termination
@@ -935,52 +1038,61 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 
 	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 	if !ok {
-		log.Errorf("Common Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Common Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	// TODO: Create Internal Structure to hold TLSProfiles. Make API call only for a new TLSProfile
 	// Check if the TLSProfile exists and valid for us.
 	tlsProfile, err := ctlr.getTLSProfile(tlsName, namespace)
 	if err != nil {
-		log.Errorf("Error fetching TLSProfile %s: %v", tlsName, err)
+		workerLog.Errorf("Error fetching TLSProfile %s: %v", tlsName, err)
+		prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name, err.Error())
 		return nil
 	}
 
 	// validate TLSProfile
 	validation := validateTLSProfile(tlsProfile)
 	if validation == false {
+		prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name, fmt.Sprintf("TLSProfile %s is not valid", tlsName))
 		return nil
 	}
 
 	if tlsProfile.Spec.TLS.Reference == "secret" {
-		var match bool
-		if len(tlsProfile.Spec.TLS.ClientSSLs) > 0 {
-			for _, secret := range tlsProfile.Spec.TLS.ClientSSLs {
-				secretKey := namespace + "/" + secret
+		// certificates are validated for each host of the VirtualServer
+		for _, host := range getVirtualServerHosts(vs) {
+			var match bool
+			if len(tlsProfile.Spec.TLS.ClientSSLs) > 0 {
+				for _, secret := range tlsProfile.Spec.TLS.ClientSSLs {
+					secretKey := namespace + "/" + secret
+					clientSecretobj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
+					if err != nil || !found {
+						prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name, fmt.Sprintf("Secret %s of TLSProfile %s not found", secretKey, tlsName))
+						return nil
+					}
+					clientSecret := clientSecretobj.(*v1.Secret)
+					//validate at least one clientSSL certificates matches the VS hostname
+					if checkCertificateHost(host, VirtualServer, vsKey, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"]) {
+						match = true
+						break
+					}
+				}
+
+			} else {
+				secretKey := namespace + "/" + tlsProfile.Spec.TLS.ClientSSL
 				clientSecretobj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
 				if err != nil || !found {
+					prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name, fmt.Sprintf("Secret %s of TLSProfile %s not found", secretKey, tlsName))
 					return nil
 				}
 				clientSecret := clientSecretobj.(*v1.Secret)
-				//validate at least one clientSSL certificates matches the VS hostname
-				if checkCertificateHost(vs.Spec.Host, VirtualServer, vsKey, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"]) {
-					match = true
-					break
-				}
+				//validate clientSSL certificates and hostname
+				match = checkCertificateHost(host, VirtualServer, vsKey, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"])
 			}
-
-		} else {
-			secretKey := namespace + "/" + tlsProfile.Spec.TLS.ClientSSL
-			clientSecretobj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
-			if err != nil || !found {
+			if match == false {
+				prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name,
+					fmt.Sprintf("Certificate of TLSProfile %s does not match with host %s", tlsName, host))
 				return nil
 			}
-			clientSecret := clientSecretobj.(*v1.Secret)
-			//validate clientSSL certificates and hostname
-			match = checkCertificateHost(vs.Spec.Host, VirtualServer, vsKey, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"])
-		}
-		if match == false {
-			return nil
 		}
 	}
 	if len(vs.Spec.Host) == 0 {
@@ -991,23 +1103,33 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 		return tlsProfile
 	}
 
+	for _, host := range getVirtualServerHosts(vs) {
+		if !tlsProfileHasHost(tlsProfile, host) {
+			workerLog.Errorf("TLSProfile %s with host %s does not match with virtual server %s host.", tlsName, host, vs.ObjectMeta.Name)
+			prometheus.AddConfigurationWarning(VirtualServer, vs.Namespace, vs.Name,
+				fmt.Sprintf("TLSProfile %s does not match with host %s", tlsName, host))
+			return nil
+		}
+	}
+	// TLSProfile Object
+	return tlsProfile
+}
+
+// tlsProfileHasHost returns true if the host or a wildcard host of the TLSProfile matches with the host
+func tlsProfileHasHost(tlsProfile *cisapiv1.TLSProfile, vsHost string) bool {
 	for _, host := range tlsProfile.Spec.Hosts {
-		if host == vs.Spec.Host {
-			// TLSProfile Object
-			return tlsProfile
+		if host == vsHost {
+			return true
 		}
 		// check for wildcard match
 		if strings.HasPrefix(host, "*") {
 			host = strings.TrimPrefix(host, "*")
-			if strings.HasSuffix(vs.Spec.Host, host) {
-				// TLSProfile Object
-				return tlsProfile
+			if strings.HasSuffix(vsHost, host) {
+				return true
 			}
 		}
 	}
-	log.Errorf("TLSProfile %s with host %s does not match with virtual server %s host.", tlsName, vs.Spec.Host, vs.ObjectMeta.Name)
-	return nil
-
+	return false
 }
 
 func isTLSVirtualServer(vrt *cisapiv1.VirtualServer) bool {
@@ -1042,14 +1164,17 @@ func (ctlr *Controller) processVirtualServers(
 	virtual *cisapiv1.VirtualServer,
 	isVSDeleted bool,
 ) error {
+	rscLog := resourceLogger(VirtualServer, virtual.Namespace, virtual.Name, "")
 
 	startTime := time.Now()
 	defer func() {
 		endTime := time.Now()
-		log.Debugf("Finished syncing virtual servers %+v (%v)",
+		rscLog.Debugf("Finished syncing virtual servers %+v (%v)",
 			virtual, endTime.Sub(startTime))
 	}()
 
+	// clear the warnings of the earlier processing, warnings are added again if still invalid
+	prometheus.ClearConfigurationWarnings(VirtualServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name)
 	// Skip validation for a deleted Virtual Server
 	if !isVSDeleted {
 		// check if the virutal server matches all the requirements.
@@ -1057,12 +1182,11 @@ func (ctlr *Controller) processVirtualServers(
 		valid := ctlr.checkValidVirtualServer(virtual)
 		if false == valid {
 			warning := fmt.Sprintf("VirtualServer %s, is not valid", vkey)
-			log.Warningf(warning)
-			prometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, warning).Set(1)
+			rscLog.Warningf(warning)
+			prometheus.AddConfigurationWarning(VirtualServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, warning)
 			return nil
 		}
 	}
-	prometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, "").Set(0)
 	var allVirtuals []*cisapiv1.VirtualServer
 	if virtual.Spec.HostGroup != "" {
 		// grouping by hg across all namespaces
@@ -1076,7 +1200,7 @@ func (ctlr *Controller) processVirtualServers(
 
 	// Prepare list of associated VirtualServers to be processed
 	// In the event of deletion, exclude the deleted VirtualServer
-	log.Debugf("Process all the Virtual Servers which share same VirtualServerAddress")
+	rscLog.Debugf("Process all the Virtual Servers which share same VirtualServerAddress")
 	// TODO: phase2 get bigipLabel from cr resource or service address cr
 	// Phase1 setting bigipLabel to empty string
 	bigipLabel := BigIPLabel
@@ -1084,6 +1208,12 @@ func (ctlr *Controller) processVirtualServers(
 	VSSpecProps := &VSSpecProperties{}
 	virtuals := ctlr.getAssociatedVirtualServers(virtual, allVirtuals, isVSDeleted, VSSpecProps)
 	//ctlr.getAssociatedSpecVirtuals(virtuals,VSSpecProps)
+	// the associated virtuals are processed along with the virtual, clear their warnings as well
+	for _, vrt := range virtuals {
+		if vrt.Namespace != virtual.Namespace || vrt.Name != virtual.Name {
+			prometheus.ClearConfigurationWarnings(VirtualServer, vrt.Namespace, vrt.Name)
+		}
+	}
 
 	var ip string
 	var status int
@@ -1120,15 +1250,17 @@ func (ctlr *Controller) processVirtualServers(
 
 			switch status {
 			case ipmanager.NotEnabled:
-				log.Debug("IPAM Custom Resource Not Available")
+				rscLog.Debug("IPAM Custom Resource Not Available")
+				prometheus.AddConfigurationWarning(VirtualServer, virtual.Namespace, virtual.Name, "[IPAM] IPAM Custom Resource Not Available")
 				return nil
 			case ipmanager.InvalidInput:
-				log.Debugf("IPAM Invalid IPAM Label: %v for Virtual Server: %s/%s", ipamLabel, virtual.Namespace, virtual.Name)
+				rscLog.Debugf("IPAM Invalid IPAM Label: %v for Virtual Server: %s/%s", ipamLabel, virtual.Namespace, virtual.Name)
+				prometheus.AddConfigurationWarning(VirtualServer, virtual.Namespace, virtual.Name, fmt.Sprintf("[IPAM] Invalid IPAM Label: %v", ipamLabel))
 				return nil
 			case ipmanager.NotRequested:
 				return fmt.Errorf("unable make do IPAM Request, will be re-requested soon")
 			case ipmanager.Requested:
-				log.Debugf("IP address requested for service: %s/%s", virtual.Namespace, virtual.Name)
+				rscLog.Debugf("IP address requested for service: %s/%s", virtual.Namespace, virtual.Name)
 				return nil
 			}
 		}
@@ -1136,7 +1268,8 @@ func (ctlr *Controller) processVirtualServers(
 		var err error
 		ip, err = getVirtualServerAddress(virtual, virtuals)
 		if err != nil {
-			log.Errorf("Error in virtualserver address: %s", err.Error())
+			rscLog.Errorf("Error in virtualserver address: %s", err.Error())
+			prometheus.AddConfigurationWarning(VirtualServer, virtual.Namespace, virtual.Name, err.Error())
 			return err
 		}
 	}
@@ -1152,7 +1285,7 @@ func (ctlr *Controller) processVirtualServers(
 		if virtual.Spec.VirtualServerName != "" {
 			if virtual.Spec.HostGroup != "" {
 				//Ignore virtualServerName if hostgroup is configured on virtual
-				log.Warningf("virtualServerName is ignored as hostgroup is configured on virtualserver %v", virtual.Name)
+				rscLog.Warningf("virtualServerName is ignored as hostgroup is configured on virtualserver %v", virtual.Name)
 				rsName = formatVirtualServerName(
 					ip,
 					portS.port,
@@ -1221,7 +1354,7 @@ func (ctlr *Controller) processVirtualServers(
 		}
 		if err != nil {
 			processingError = true
-			log.Errorf("%v", err)
+			rscLog.Errorf("%v", err)
 			break
 		}
 
@@ -1247,8 +1380,11 @@ func (ctlr *Controller) processVirtualServers(
 				}
 			}
 
-			log.Debugf("Processing Virtual Server %s for port %v",
+			rscLog.Debugf("Processing Virtual Server %s for port %v",
 				vrt.ObjectMeta.Name, portS.port)
+			for _, pool := range vrt.Spec.Pools {
+				ctlr.addMissingServiceWarning(VirtualServer, vrt.Namespace, vrt.Name, pool.ServiceNamespace, pool.Service)
+			}
 			rsCfg.MetaData.baseResources[vrt.Namespace+"/"+vrt.Name] = VirtualServer
 			err := ctlr.prepareRSConfigFromVirtualServer(
 				rsCfg,
@@ -1279,7 +1415,7 @@ func (ctlr *Controller) processVirtualServers(
 					break
 				}
 
-				log.Debugf("Updated Virtual %s with TLSProfile %s",
+				rscLog.Debugf("Updated Virtual %s with TLSProfile %s",
 					vrt.ObjectMeta.Name, vrt.Spec.TLSProfileName)
 			}
 
@@ -1295,7 +1431,7 @@ func (ctlr *Controller) processVirtualServers(
 			ctlr.addDefaultWAFDisableRule(rsCfg, "vs_waf_disable")
 		}
 		if processingError {
-			log.Errorf("Cannot Publish VirtualServer %s", virtual.ObjectMeta.Name)
+			rscLog.Errorf("Cannot Publish VirtualServer %s", virtual.ObjectMeta.Name)
 			break
 		}
 
@@ -1380,7 +1516,7 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 		if currentVS.Spec.VirtualServerAddress != "" &&
 			currentVS.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress &&
 			currentVSPartition != ctlr.getCRPartition(vrt.Spec.Partition) {
-			log.Errorf("Multiple Virtual Servers %v,%v are configured with same VirtualServerAddress : %v with different partitions", currentVS.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
+			workerLog.Errorf("Multiple Virtual Servers %v,%v are configured with same VirtualServerAddress : %v with different partitions", currentVS.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
 			return nil
 		}
 
@@ -1388,7 +1524,7 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 		if vrt.Spec.HostGroup != currentVS.Spec.HostGroup {
 			if currentVS.Spec.VirtualServerAddress != "" && vrt.Spec.VirtualServerAddress != "" &&
 				currentVS.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress {
-				log.Errorf("Multiple Virtual Servers %v, %v are configured with same VirtualServerAddress: %v, "+
+				workerLog.Errorf("Multiple Virtual Servers %v, %v are configured with same VirtualServerAddress: %v, "+
 					"but different HostGroups: %s %s", currentVS.Name, vrt.Name, vrt.Spec.VirtualServerAddress,
 					currentVS.Spec.HostGroup, vrt.Spec.HostGroup)
 				return nil
@@ -1399,13 +1535,13 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 		if vrt.Spec.HostGroup != "" && currentVS.Spec.HostGroup != "" && vrt.Spec.HostGroup == currentVS.Spec.HostGroup {
 			if currentVS.Spec.VirtualServerAddress != "" && vrt.Spec.VirtualServerAddress != "" &&
 				currentVS.Spec.VirtualServerAddress != vrt.Spec.VirtualServerAddress {
-				log.Errorf("Multiple Virtual Servers %v, %v are configured with different VirtualServerAddress: %v %v, "+
+				workerLog.Errorf("Multiple Virtual Servers %v, %v are configured with different VirtualServerAddress: %v %v, "+
 					"but same HostGroup: %s", currentVS.Name, vrt.Name, currentVS.Spec.VirtualServerAddress,
 					vrt.Spec.VirtualServerAddress, currentVS.Spec.HostGroup)
 				return nil
 			}
 			if currentVS.Spec.IPAMLabel != "" && vrt.Spec.IPAMLabel != "" && currentVS.Spec.IPAMLabel != vrt.Spec.IPAMLabel {
-				log.Errorf("Multiple Virtual Servers %v, %v are configured with different IPAM Labels: %v %v, but "+
+				workerLog.Errorf("Multiple Virtual Servers %v, %v are configured with different IPAM Labels: %v %v, but "+
 					"same HostGroup: %s", currentVS.Name, vrt.Name, currentVS.Spec.IPAMLabel, vrt.Spec.IPAMLabel,
 					currentVS.Spec.HostGroup)
 				return nil
@@ -1432,7 +1568,7 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			// Same host with different VirtualServerAddress is invalid
 			if vrt.Spec.VirtualServerAddress != currentVS.Spec.VirtualServerAddress {
 				if vrt.Spec.Host != "" && vrt.Spec.Host == currentVS.Spec.Host {
-					log.Errorf("Same host %v is configured with different VirtualServerAddress : %v ", vrt.Spec.Host, vrt.Spec.VirtualServerName)
+					workerLog.Errorf("Same host %v is configured with different VirtualServerAddress : %v ", vrt.Spec.Host, vrt.Spec.VirtualServerName)
 					return nil
 				}
 				// In case of empty host name or host names not matching, skip the virtual with other VirtualServerAddress
@@ -1441,7 +1577,7 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			//with additonalVirtualServerAddresses, skip the virtuals if ip list doesn't match
 			if !reflect.DeepEqual(currentVS.Spec.AdditionalVirtualServerAddresses, vrt.Spec.AdditionalVirtualServerAddresses) {
 				if vrt.Spec.Host != "" {
-					log.Errorf("Same host %v is configured with different AdditionalVirtualServerAddress : %v ", vrt.Spec.Host, vrt.ObjectMeta.Name)
+					workerLog.Errorf("Same host %v is configured with different AdditionalVirtualServerAddress : %v ", vrt.Spec.Host, vrt.ObjectMeta.Name)
 					return nil
 				}
 				// In case of empty host name, skip the virtual with other AdditionalVirtualServerAddress
@@ -1451,19 +1587,19 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 
 		if ctlr.ipamHandler != nil {
 			if currentVS.Spec.HostGroup == "" && vrt.Spec.IPAMLabel != currentVS.Spec.IPAMLabel {
-				log.Errorf("Same host %v is configured with different IPAM labels: %v, %v. Unable to process %v", vrt.Spec.Host, vrt.Spec.IPAMLabel, currentVS.Spec.IPAMLabel, currentVS.Name)
+				workerLog.Errorf("Same host %v is configured with different IPAM labels: %v, %v. Unable to process %v", vrt.Spec.Host, vrt.Spec.IPAMLabel, currentVS.Spec.IPAMLabel, currentVS.Name)
 				return nil
 			}
 			// Empty host and hostGroup with IPAM label is invalid for a Virtual Server
 			if vrt.Spec.IPAMLabel != "" && vrt.Spec.Host == "" && vrt.Spec.HostGroup == "" {
-				log.Errorf("Hostless VS %v is configured with IPAM label: %v and missing HostGroup", vrt.ObjectMeta.Name, vrt.Spec.IPAMLabel)
+				workerLog.Errorf("Hostless VS %v is configured with IPAM label: %v and missing HostGroup", vrt.ObjectMeta.Name, vrt.Spec.IPAMLabel)
 				return nil
 			}
 
 			// Empty host with empty IPAM label is invalid
 			if vrt.Spec.Host == "" && vrt.Spec.VirtualServerAddress == "" && len(vrt.Spec.AdditionalVirtualServerAddresses) == 0 {
 				if vrt.Spec.IPAMLabel == "" && vrt.Spec.HostGroup != "" {
-					log.Errorf("Hostless VS %v is configured with missing IPAM label", vrt.ObjectMeta.Name)
+					workerLog.Errorf("Hostless VS %v is configured with missing IPAM label", vrt.ObjectMeta.Name)
 					return nil
 				}
 				if vrt.Spec.IPAMLabel == "" {
@@ -1479,30 +1615,39 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 
 		// skip the virtuals with different default pool
 		if !reflect.DeepEqual(currentVS.Spec.DefaultPool, vrt.Spec.DefaultPool) {
-			log.Errorf("%v/%v and %v/%v VS should have same default pool.", vrt.Namespace, vrt.Name, currentVS.Namespace, currentVS.Name)
+			workerLog.Errorf("%v/%v and %v/%v VS should have same default pool.", vrt.Namespace, vrt.Name, currentVS.Namespace, currentVS.Name)
 			continue
 		}
 
 		// Check for duplicate path entries among virtuals
-		uniquePaths, ok := uniqueHostPathMap[vrt.Spec.Host]
-		if !ok {
-			uniqueHostPathMap[vrt.Spec.Host] = make(map[string]struct{})
-			uniquePaths = uniqueHostPathMap[vrt.Spec.Host]
-		}
 		isUnique := true
 		for _, pool := range vrt.Spec.Pools {
 			//Setting PoolWAF to true if exists
 			if pool.WAF != "" {
 				VSSpecProperties.PoolWAF = true
 			}
-			if _, ok := uniquePaths[pool.Path]; ok {
-				// path already exists for the same host
-				log.Debugf("Discarding the VirtualServer %v/%v due to duplicate path",
-					vrt.ObjectMeta.Namespace, vrt.ObjectMeta.Name)
-				isUnique = false
+		}
+		for _, host := range getVirtualServerHosts(vrt) {
+			uniquePaths, ok := uniqueHostPathMap[host]
+			if !ok {
+				uniqueHostPathMap[host] = make(map[string]struct{})
+				uniquePaths = uniqueHostPathMap[host]
+			}
+			for _, pool := range vrt.Spec.Pools {
+				// pools of the same path with different match conditions are unique
+				path := pool.Path + poolMatchKey(pool.Match)
+				if _, ok := uniquePaths[path]; ok {
+					// path already exists for the same host
+					workerLog.Debugf("Discarding the VirtualServer %v/%v due to duplicate path",
+						vrt.ObjectMeta.Namespace, vrt.ObjectMeta.Name)
+					isUnique = false
+					break
+				}
+				uniquePaths[path] = struct{}{}
+			}
+			if !isUnique {
 				break
 			}
-			uniquePaths[pool.Path] = struct{}{}
 		}
 		if isUnique {
 			virtuals = append(virtuals, vrt)
@@ -1526,7 +1671,7 @@ func (ctlr *Controller) validateTSWithSameVSAddress(
 		if vrt.Spec.HostGroup != currentTS.Spec.HostGroup {
 			if currentTS.Spec.VirtualServerAddress != "" && vrt.Spec.VirtualServerAddress != "" &&
 				currentTS.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress {
-				log.Errorf("Multiple Transport Servers %v, %v are configured with same VirtualServerAddress: %v, "+
+				workerLog.Errorf("Multiple Transport Servers %v, %v are configured with same VirtualServerAddress: %v, "+
 					"but different HostGroups: %s %s", currentTS.Name, vrt.Name, vrt.Spec.VirtualServerAddress,
 					currentTS.Spec.HostGroup, vrt.Spec.HostGroup)
 				return false
@@ -1537,13 +1682,13 @@ func (ctlr *Controller) validateTSWithSameVSAddress(
 		if vrt.Spec.HostGroup != "" && currentTS.Spec.HostGroup != "" && vrt.Spec.HostGroup == currentTS.Spec.HostGroup {
 			if currentTS.Spec.VirtualServerAddress != "" && vrt.Spec.VirtualServerAddress != "" &&
 				currentTS.Spec.VirtualServerAddress != vrt.Spec.VirtualServerAddress {
-				log.Errorf("Multiple Transport Servers %v, %v are configured with different VirtualServerAddress: "+
+				workerLog.Errorf("Multiple Transport Servers %v, %v are configured with different VirtualServerAddress: "+
 					"%v %v, but same HostGroup: %s", currentTS.Name, vrt.Name, currentTS.Spec.VirtualServerAddress,
 					vrt.Spec.VirtualServerAddress, currentTS.Spec.HostGroup)
 				return false
 			}
 			if currentTS.Spec.IPAMLabel != "" && vrt.Spec.IPAMLabel != "" && currentTS.Spec.IPAMLabel != vrt.Spec.IPAMLabel {
-				log.Errorf("Multiple Transport Servers %v, %v are configured with different IPAM Labels: %v %v, "+
+				workerLog.Errorf("Multiple Transport Servers %v, %v are configured with different IPAM Labels: %v %v, "+
 					"but same HostGroup: %s", currentTS.Name, vrt.Name, currentTS.Spec.IPAMLabel, vrt.Spec.IPAMLabel,
 					currentTS.Spec.HostGroup)
 				return false
@@ -1578,7 +1723,7 @@ func (ctlr *Controller) validateILsWithSameVSAddress(
 		if currentIL.Spec.VirtualServerAddress != "" &&
 			currentIL.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress &&
 			currentILPartition != ctlr.getCRPartition(vrt.Spec.Partition) {
-			log.Errorf("Multiple Ingress Links %v,%v are configured with same VirtualServerAddress : %v "+
+			workerLog.Errorf("Multiple Ingress Links %v,%v are configured with same VirtualServerAddress : %v "+
 				"with different partitions", currentIL.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
 			return false
 		}
@@ -1595,7 +1740,7 @@ func (ctlr *Controller) getCRPartition(partition string) string {
 func (ctlr *Controller) getPolicyFromVirtuals(virtuals []*cisapiv1.VirtualServer) (*cisapiv1.Policy, error) {
 
 	if len(virtuals) == 0 {
-		log.Errorf("No virtuals to extract policy from")
+		workerLog.Errorf("No virtuals to extract policy from")
 		return nil, nil
 	}
 	plcName := ""
@@ -1634,7 +1779,7 @@ func (ctlr *Controller) getPolicyFromVirtuals(virtuals []*cisapiv1.VirtualServer
 func (ctlr *Controller) getPolicyFromTransportServer(virtual *cisapiv1.TransportServer) (*cisapiv1.Policy, error) {
 
 	if virtual == nil {
-		log.Errorf("No virtuals to extract policy from")
+		workerLog.Errorf("No virtuals to extract policy from")
 		return nil, nil
 	}
 
@@ -1650,20 +1795,20 @@ func (ctlr *Controller) getPolicyFromTransportServer(virtual *cisapiv1.Transport
 func (ctlr *Controller) getPolicy(ns string, plcName string) (*cisapiv1.Policy, error) {
 	crInf, ok := ctlr.getNamespacedCommonInformer(ns)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", ns)
+		workerLog.Errorf("Informer not found for namespace: %v", ns)
 		return nil, fmt.Errorf("Informer not found for namespace: %v", ns)
 	}
 	key := ns + "/" + plcName
 
 	obj, exist, err := crInf.plcInformer.GetIndexer().GetByKey(key)
 	if err != nil {
-		log.Errorf("Error while fetching Policy: %v: %v",
+		workerLog.Errorf("Error while fetching Policy: %v: %v",
 			key, err)
 		return nil, fmt.Errorf("Error while fetching Policy: %v: %v", key, err)
 	}
 
 	if !exist {
-		log.Errorf("Policy Not Found: %v", key)
+		workerLog.Errorf("Policy Not Found: %v", key)
 		return nil, fmt.Errorf("Policy Not Found: %v", key)
 	}
 	return obj.(*cisapiv1.Policy), nil
@@ -1813,6 +1958,19 @@ func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKe
 	}
 }
 
+// addMissingServiceWarning adds configuration warning for the pool service of the resource missing in the cluster
+func (ctlr *Controller) addMissingServiceWarning(kind, namespace, name, svcNamespace, svcName string) {
+	if svcName == "" {
+		return
+	}
+	if svcNamespace == "" {
+		svcNamespace = namespace
+	}
+	if err, _ := ctlr.fetchService(MultiClusterServiceKey{serviceName: svcName, namespace: svcNamespace}); err != nil {
+		prometheus.AddConfigurationWarning(kind, namespace, name, fmt.Sprintf("Service %v/%v not found", svcNamespace, svcName))
+	}
+}
+
 func (ctlr *Controller) fetchService(svcKey MultiClusterServiceKey) (error, *v1.Service) {
 	var svc *v1.Service
 	if svcKey.clusterName == "" {
@@ -1850,6 +2008,8 @@ func (ctlr *Controller) fetchService(svcKey MultiClusterServiceKey) (error, *v1.
 
 // updatePoolMembersForResources updates the pool members for service present in the provided Pool
 func (ctlr *Controller) updatePoolMembersForResources(pool *Pool) {
+	// members of the fallback services are added once the members of the services are updated
+	defer ctlr.updateFallbackPoolMembers(pool)
 	var poolMembers []PoolMember
 	// for local cluster
 	if pool.Cluster == "" {
@@ -1898,6 +2058,28 @@ func (ctlr *Controller) updatePoolMembersForResources(pool *Pool) {
 	pool.Members = poolMembers
 }
 
+// updateFallbackPoolMembers adds the members of the fallback services to the pool in the lower priority groups,
+// the members of the fallback services receive the traffic when the active members of the higher priority groups
+// are fewer than the minimum active members of the pool
+func (ctlr *Controller) updateFallbackPoolMembers(pool *Pool) {
+	if len(pool.FallbackServices) == 0 {
+		return
+	}
+	priority := int32(len(pool.FallbackServices))
+	for i := range pool.Members {
+		pool.Members[i].PriorityGroup = priority
+	}
+	for _, fb := range pool.FallbackServices {
+		priority--
+		members := ctlr.fetchPoolMembersForService(fb.ServiceName, fb.ServiceNamespace, fb.ServicePort,
+			pool.NodeMemberLabel, "", pool.ConnectionLimit)
+		for i := range members {
+			members[i].PriorityGroup = priority
+		}
+		pool.Members = append(pool.Members, members...)
+	}
+}
+
 // fetchPoolMembersForService returns pool members associated with a service created in specified cluster
 func (ctlr *Controller) fetchPoolMembersForService(serviceName string, serviceNamespace string,
 	servicePort intstr.IntOrString, nodeMemberLabel string, clusterName string, podConnections int32) []PoolMember {
@@ -1907,14 +2089,14 @@ func (ctlr *Controller) fetchPoolMembersForService(serviceName string, serviceNa
 		clusterName: clusterName,
 	}
 	if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
-		log.Debugf("Adding service '%v' in CIS cache %v", svcKey, getClusterLog(clusterName))
+		workerLog.Debugf("Adding service '%v' in CIS cache %v", svcKey, getClusterLog(clusterName))
 		ctlr.resources.poolMemCache[svcKey] = &poolMembersInfo{
 			memberMap: make(map[portRef][]PoolMember),
 		}
 	}
 	err, svc := ctlr.fetchService(svcKey)
 	if err != nil {
-		log.Errorf("%v %v", err, getClusterLog(clusterName))
+		workerLog.Errorf("%v %v", err, getClusterLog(clusterName))
 	}
 	var poolMembers []PoolMember
 	if svc != nil {
@@ -1938,7 +2120,7 @@ func (ctlr *Controller) getPoolMembersForEndpoints(mSvcKey MultiClusterServiceKe
 	var poolMembers []PoolMember
 	poolMemInfo, ok := ctlr.resources.poolMemCache[mSvcKey]
 	if !ok || len(poolMemInfo.memberMap) == 0 {
-		log.Errorf("[CORE]Endpoints could not be fetched for service %v with targetPort  %v:%v%v", mSvcKey, servicePort.Type, servicePort.IntVal, servicePort.StrVal)
+		workerLog.Errorf("[CORE]Endpoints could not be fetched for service %v with targetPort  %v:%v%v", mSvcKey, servicePort.Type, servicePort.IntVal, servicePort.StrVal)
 		return poolMembers
 	}
 	for ref, mems := range poolMemInfo.memberMap {
@@ -1961,7 +2143,7 @@ func (ctlr *Controller) getPoolMembersForService(mSvcKey MultiClusterServiceKey,
 	case string(v1.ServiceTypeNodePort), NodePort, string(v1.ServiceTypeLoadBalancer):
 		if !(poolMemInfo.svcType == v1.ServiceTypeNodePort ||
 			poolMemInfo.svcType == v1.ServiceTypeLoadBalancer) {
-			log.Errorf("Requested service backend %s not of NodePort or LoadBalancer type",
+			workerLog.Errorf("Requested service backend %s not of NodePort or LoadBalancer type",
 				mSvcKey)
 			return poolMembers
 		}
@@ -1985,7 +2167,7 @@ func (ctlr *Controller) getPoolMembersForService(mSvcKey MultiClusterServiceKey,
 		return ctlr.getPoolMembersForEndpoints(mSvcKey, servicePort)
 	case NodePortLocal:
 		if poolMemInfo.svcType == v1.ServiceTypeNodePort {
-			log.Debugf("Requested service backend %s is of type NodePort is not valid for nodeportlocal mode.",
+			workerLog.Debugf("Requested service backend %s is of type NodePort is not valid for nodeportlocal mode.",
 				mSvcKey)
 			return poolMembers
 		}
@@ -2012,7 +2194,7 @@ func (ctlr *Controller) getPoolMembersForService(mSvcKey MultiClusterServiceKey,
 	}
 	//check if endpoints are found
 	if len(poolMembers) == 0 {
-		log.Errorf("Pool Members could not be fetched for service %v with targetPort %v:%v%v", mSvcKey, servicePort.Type, servicePort.IntVal, servicePort.StrVal)
+		workerLog.Errorf("Pool Members could not be fetched for service %v with targetPort %v:%v%v", mSvcKey, servicePort.Type, servicePort.IntVal, servicePort.StrVal)
 	}
 	return poolMembers
 }
@@ -2035,6 +2217,7 @@ func (ctlr *Controller) getEndpointsForNodePort(
 			Address:    v.Addr,
 			Port:       nodePort,
 			Session:    "user-enabled",
+			Zone:       v.Labels[v1.LabelTopologyZone],
 		}
 		members = append(members, member)
 	}
@@ -2047,6 +2230,7 @@ func (ctlr *Controller) getEndpointsForNPL(
 	pods []*v1.Pod,
 ) []PoolMember {
 	var members []PoolMember
+	nodes := ctlr.getNodesFromCache("")
 	for _, pod := range pods {
 		anns, found := ctlr.resources.nplStore[pod.Namespace+"/"+pod.Name]
 		if !found {
@@ -2075,6 +2259,7 @@ func (ctlr *Controller) getEndpointsForNPL(
 					Address: annotation.NodeIP,
 					Port:    annotation.NodePort,
 					Session: "user-enabled",
+					Zone:    getNodeZone(nodes, pod.Spec.NodeName),
 				}
 				members = append(members, member)
 			}
@@ -2093,6 +2278,16 @@ func containsNode(nodes []Node, name string) bool {
 	return false
 }
 
+// getNodeZone returns the topology zone of the node, empty if the node isn't labeled with the zone
+func getNodeZone(nodes []Node, name string) string {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node.Labels[v1.LabelTopologyZone]
+		}
+	}
+	return ""
+}
+
 // processTransportServers takes the Transport Server as input and processes all
 // associated TransportServers to create a resource config(Internal DataStructure)
 // or to update if exists already.
@@ -2100,13 +2295,16 @@ func (ctlr *Controller) processTransportServers(
 	virtual *cisapiv1.TransportServer,
 	isTSDeleted bool,
 ) error {
+	rscLog := resourceLogger(TransportServer, virtual.Namespace, virtual.Name, "")
 	startTime := time.Now()
 	defer func() {
 		endTime := time.Now()
-		log.Debugf("Finished syncing transport servers %+v (%v)",
+		rscLog.Debugf("Finished syncing transport servers %+v (%v)",
 			virtual, endTime.Sub(startTime))
 	}()
 
+	// clear the warnings of the earlier processing, warnings are added again if still invalid
+	prometheus.ClearConfigurationWarnings(TransportServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name)
 	// Skip validation for a deleted Virtual Server
 	if !isTSDeleted {
 		// check if the virutal server matches all the requirements.
@@ -2114,12 +2312,11 @@ func (ctlr *Controller) processTransportServers(
 		valid := ctlr.checkValidTransportServer(virtual)
 		if false == valid {
 			warning := fmt.Sprintf("TransportServer %s, is not valid", vkey)
-			log.Warningf(warning)
-			prometheus.ConfigurationWarnings.WithLabelValues(TransportServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, warning).Set(1)
+			rscLog.Warningf(warning)
+			prometheus.AddConfigurationWarning(TransportServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, warning)
 			return nil
 		}
 	}
-	prometheus.ConfigurationWarnings.WithLabelValues(TransportServer, virtual.ObjectMeta.Namespace, virtual.ObjectMeta.Name, "").Set(0)
 	ctlr.TeemData.Lock()
 	ctlr.TeemData.ResourceType.TransportServer[virtual.ObjectMeta.Namespace] = len(ctlr.getAllTransportServers(virtual.Namespace))
 	ctlr.TeemData.Unlock()
@@ -2240,7 +2437,7 @@ func (ctlr *Controller) processTransportServers(
 	if plc != nil {
 		err := ctlr.handleTSResourceConfigForPolicy(rsCfg, plc)
 		if err != nil {
-			log.Errorf("%v", err)
+			rscLog.Errorf("%v", err)
 			return nil
 		}
 	}
@@ -2250,9 +2447,11 @@ func (ctlr *Controller) processTransportServers(
 		return nil
 	}
 
-	log.Debugf("Processing Transport Server %s for port %v",
+	rscLog.Debugf("Processing Transport Server %s for port %v",
 		virtual.ObjectMeta.Name, virtual.Spec.VirtualServerPort)
 	rsCfg.MetaData.baseResources[virtual.ObjectMeta.Namespace+"/"+virtual.ObjectMeta.Name] = TransportServer
+	ctlr.addMissingServiceWarning(TransportServer, virtual.Namespace, virtual.Name, virtual.Spec.Pool.ServiceNamespace,
+		virtual.Spec.Pool.Service)
 	err = ctlr.prepareRSConfigFromTransportServer(
 		rsCfg,
 		virtual,
@@ -2268,7 +2467,7 @@ func (ctlr *Controller) processTransportServers(
 			err := ctlr.handlePoolResourceConfigForPolicy(rsCfg, plc)
 			if err != nil {
 				if err != nil {
-					log.Errorf("%v", err)
+					rscLog.Errorf("%v", err)
 					return nil
 				}
 			}
@@ -2309,11 +2508,11 @@ func (ctlr *Controller) getAllTransportServers(namespace string) []*cisapiv1.Tra
 
 	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	if crInf.tsInformer == nil {
-		log.Errorf("transport server informer not found for namespace: %v ", namespace)
+		workerLog.Errorf("transport server informer not found for namespace: %v ", namespace)
 		return nil
 	}
 	var orderedTSs []interface{}
@@ -2324,7 +2523,7 @@ func (ctlr *Controller) getAllTransportServers(namespace string) []*cisapiv1.Tra
 	} else {
 		orderedTSs, err = crInf.tsInformer.GetIndexer().ByIndex("namespace", namespace)
 		if err != nil {
-			log.Errorf("Unable to get list of TransportServers for namespace '%v': %v",
+			workerLog.Errorf("Unable to get list of TransportServers for namespace '%v': %v",
 				namespace, err)
 			return nil
 		}
@@ -2344,7 +2543,7 @@ func (ctlr *Controller) getAllLBServices(namespace string) []*v1.Service {
 
 	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	var orderedSVCs []interface{}
@@ -2355,7 +2554,7 @@ func (ctlr *Controller) getAllLBServices(namespace string) []*v1.Service {
 	} else {
 		orderedSVCs, err = comInf.svcInformer.GetIndexer().ByIndex("namespace", namespace)
 		if err != nil {
-			log.Errorf("Unable to get list of Services for namespace '%v': %v",
+			workerLog.Errorf("Unable to get list of Services for namespace '%v': %v",
 				namespace, err)
 			return nil
 		}
@@ -2374,11 +2573,14 @@ func (ctlr *Controller) processLBServices(
 	svc *v1.Service,
 	isSVCDeleted bool,
 ) error {
+	rscLog := resourceLogger(Service, svc.Namespace, svc.Name, "")
 
+	// clear the warnings of the earlier processing, warnings are added again if still invalid
+	prometheus.ClearConfigurationWarnings(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name)
 	ip, ok1 := svc.Annotations[LBServiceIPAnnotation]
 	ipamLabel, ok2 := svc.Annotations[LBServiceIPAMLabelAnnotation]
 	if !ok1 && !ok2 {
-		log.Debugf("Service %v/%v does not have either of annotation: %v, annotation:%v, continuing.",
+		rscLog.Debugf("Service %v/%v does not have either of annotation: %v, annotation:%v, continuing.",
 			svc.Namespace,
 			svc.Name,
 			LBServiceIPAMLabelAnnotation,
@@ -2389,11 +2591,10 @@ func (ctlr *Controller) processLBServices(
 	if !ok1 {
 		if ctlr.ipamHandler == nil {
 			warning := "[IPAM] IPAM is not enabled, Unable to process Services of Type LoadBalancer"
-			log.Warningf(warning)
-			prometheus.ConfigurationWarnings.WithLabelValues(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name, warning).Set(1)
+			rscLog.Warningf(warning)
+			prometheus.AddConfigurationWarning(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name, warning)
 			return nil
 		}
-		prometheus.ConfigurationWarnings.WithLabelValues(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name, "").Set(0)
 		svcKey := svc.Namespace + "/" + svc.Name + "_svc"
 		var status int
 		resRef := ipmanager.ResourceRef{
@@ -2408,15 +2609,17 @@ func (ctlr *Controller) processLBServices(
 
 			switch status {
 			case ipmanager.NotEnabled:
-				log.Debug("[IPAM] IPAM Custom Resource Not Available")
+				rscLog.Debug("[IPAM] IPAM Custom Resource Not Available")
+				prometheus.AddConfigurationWarning(Service, svc.Namespace, svc.Name, "[IPAM] IPAM Custom Resource Not Available")
 				return nil
 			case ipmanager.InvalidInput:
-				log.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for service: %s/%s", ipamLabel, svc.Namespace, svc.Name)
+				rscLog.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for service: %s/%s", ipamLabel, svc.Namespace, svc.Name)
+				prometheus.AddConfigurationWarning(Service, svc.Namespace, svc.Name, fmt.Sprintf("[IPAM] Invalid IPAM Label: %v", ipamLabel))
 				return nil
 			case ipmanager.NotRequested:
 				return fmt.Errorf("[IPAM] unable to make IPAM Request, will be re-requested soon")
 			case ipmanager.Requested:
-				log.Debugf("[IPAM] IP address requested for service: %s/%s", svc.Namespace, svc.Name)
+				rscLog.Debugf("[IPAM] IP address requested for service: %s/%s", svc.Namespace, svc.Name)
 				return nil
 			}
 		}
@@ -2430,7 +2633,7 @@ func (ctlr *Controller) processLBServices(
 
 	for _, portSpec := range svc.Spec.Ports {
 
-		log.Debugf("Processing Service Type LB %s for port %v",
+		rscLog.Debugf("Processing Service Type LB %s for port %v",
 			svc.ObjectMeta.Name, portSpec)
 
 		rsName := AS3NameFormatter(fmt.Sprintf("vs_lb_svc_%s_%s_%s_%v", svc.Namespace, svc.Name, ip, portSpec.Port))
@@ -2474,21 +2677,33 @@ func (ctlr *Controller) processLBServices(
 		if plc != nil {
 			err := ctlr.handleTSResourceConfigForPolicy(rsCfg, plc)
 			if err != nil {
-				log.Errorf("%v", err)
+				rscLog.Errorf("%v", err)
 				processingError = true
 			}
 		}
 		if err != nil {
 			processingError = true
-			log.Errorf("%v", err)
+			rscLog.Errorf("%v", err)
 		}
 
 		if processingError {
-			log.Errorf("Cannot Publish LB Service %s", svc.ObjectMeta.Name)
+			rscLog.Errorf("Cannot Publish LB Service %s", svc.ObjectMeta.Name)
 			break
 		}
 
 		_ = ctlr.prepareRSConfigFromLBService(rsCfg, svc, portSpec)
+		// handle pool settings from policy cr
+		if plc != nil && plc.Spec.PoolSettings != (cisapiv1.PoolSettingsSpec{}) {
+			err := ctlr.handlePoolResourceConfigForPolicy(rsCfg, plc)
+			if err != nil {
+				rscLog.Errorf("%v", err)
+				processingError = true
+			}
+		}
+		if processingError {
+			rscLog.Errorf("Cannot Publish LB Service %s", svc.ObjectMeta.Name)
+			break
+		}
 
 		rsMap := ctlr.resources.getPartitionResourceMap(partition, bigipConfig)
 		rsMap[rsName] = rsCfg
@@ -2519,7 +2734,7 @@ func (ctlr *Controller) processService(
 	if clusterName == "" {
 		comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 		if !ok {
-			log.Errorf("Informer not found for namespace: %v %v", namespace, getClusterLog(clusterName))
+			workerLog.Errorf("Informer not found for namespace: %v %v", namespace, getClusterLog(clusterName))
 			return fmt.Errorf("unable to process Service: %v %v", svcKey, getClusterLog(clusterName))
 		}
 		if comInf.epsInformer != nil {
@@ -2569,6 +2784,9 @@ func (ctlr *Controller) processService(
 							Port:    p.Port,
 							Session: "user-enabled",
 						}
+						if addr.NodeName != nil {
+							member.Zone = getNodeZone(nodes, *addr.NodeName)
+						}
 						members = append(members, member)
 					}
 				}
@@ -2602,7 +2820,7 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 			if gtmPartitionConfig, ok := ctlr.resources.bigIpMap[bigipConfig].gtmConfig[DEFAULT_GTM_PARTITION]; ok {
 				if processedWIP, ok := gtmPartitionConfig.WideIPs[edns.Spec.DomainName]; ok {
 					if processedWIP.UID != string(edns.UID) {
-						log.Errorf("EDNS with same domain name %s present", edns.Spec.DomainName)
+						workerLog.Errorf("EDNS with same domain name %s present", edns.Spec.DomainName)
 						return
 					}
 				}
@@ -2658,13 +2876,13 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 		wip.LBMethod = "round-robin"
 	}
 
-	log.Debugf("Processing WideIP: %v", edns.Spec.DomainName)
+	workerLog.Debugf("Processing WideIP: %v", edns.Spec.DomainName)
 
 	partitions := ctlr.resources.getLTMPartitions(bigipLabel)
 	for _, pl := range edns.Spec.Pools {
 		UniquePoolName := strings.Replace(edns.Spec.DomainName, "*", "wildcard", -1) + "_" +
 			AS3NameFormatter(strings.TrimPrefix(bigipConfig.BigIpAddress, "https://")) + "_" + DEFAULT_GTM_PARTITION
-		log.Debugf("Processing WideIP Pool: %v", UniquePoolName)
+		workerLog.Debugf("Processing WideIP Pool: %v", UniquePoolName)
 		pool := GSLBPool{
 			Name:          UniquePoolName,
 			RecordType:    pl.DNSRecordType,
@@ -2709,7 +2927,7 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 						}
 						continue
 					}
-					log.Debugf("Adding WideIP Pool Member: %v", fmt.Sprintf("/%v/Shared/%v",
+					workerLog.Debugf("Adding WideIP Pool Member: %v", fmt.Sprintf("/%v/Shared/%v",
 						partition, vsName))
 					pool.Members = append(
 						pool.Members,
@@ -2783,7 +3001,7 @@ func (ctlr *Controller) getAllExternalDNS(namespace string) []*cisapiv1.External
 	var allEDNS []*cisapiv1.ExternalDNS
 	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	var orderedEDNSs []interface{}
@@ -2794,7 +3012,7 @@ func (ctlr *Controller) getAllExternalDNS(namespace string) []*cisapiv1.External
 	} else {
 		orderedEDNSs, err = comInf.ednsInformer.GetIndexer().ByIndex("namespace", namespace)
 		if err != nil {
-			log.Errorf("Unable to get list of ExternalDNSs for namespace '%v': %v",
+			workerLog.Errorf("Unable to get list of ExternalDNSs for namespace '%v': %v",
 				namespace, err)
 			return allEDNS
 		}
@@ -2846,23 +3064,23 @@ func (ctlr *Controller) ProcessAssociatedExternalDNS(hostnames []string) {
 func checkCertificateHost(host, kind, rkey string, certificate []byte, key []byte) bool {
 	cert, certErr := tls.X509KeyPair(certificate, key)
 	if certErr != nil {
-		log.Errorf("Failed to validate TLS cert and key: %v", certErr)
+		workerLog.Errorf("Failed to validate TLS cert and key: %v", certErr)
 		return false
 	}
 	x509cert, err := x509.ParseCertificate(cert.Certificate[0])
 	if err != nil {
-		log.Errorf("failed to parse certificate; %s", err)
+		workerLog.Errorf("failed to parse certificate; %s", err)
 		return false
 	}
 
 	if len(x509cert.DNSNames) > 0 {
 		ok := x509cert.VerifyHostname(host)
 		if ok != nil {
-			log.Warningf("Hostname in %v %v does not match with certificate hostname: %v", kind, rkey, ok)
+			workerLog.Warningf("Hostname in %v %v does not match with certificate hostname: %v", kind, rkey, ok)
 			return false
 		}
 	} else if !verifyCertificateCommonName(strings.ToLower(x509cert.Subject.CommonName), strings.ToLower(host)) {
-		log.Warningf("Hostname %v in %v %v does not match with certificate hostname: %v", host, kind, rkey, x509cert.Subject.CommonName)
+		workerLog.Warningf("Hostname %v in %v %v does not match with certificate hostname: %v", host, kind, rkey, x509cert.Subject.CommonName)
 		return false
 	}
 	return true
@@ -2951,9 +3169,30 @@ func validHostname(host string, isPattern bool) bool {
 	return true
 }
 
+// setIPAMMetrics sets the count of IP addresses allocated, pending and failed allocation by IPAM controller
+func setIPAMMetrics(ipam *ficV1.IPAM) {
+	var allocated, failed int
+	for _, ipSpec := range ipam.Status.IPStatus {
+		if ipSpec.IP != "" {
+			allocated++
+		} else {
+			failed++
+		}
+	}
+	pending := len(ipam.Spec.HostSpecs) - len(ipam.Status.IPStatus)
+	if pending < 0 {
+		pending = 0
+	}
+	prometheus.IPAMAllocations.WithLabelValues(prometheus.IPAMAllocated).Set(float64(allocated))
+	prometheus.IPAMAllocations.WithLabelValues(prometheus.IPAMPending).Set(float64(pending))
+	prometheus.IPAMAllocations.WithLabelValues(prometheus.IPAMFailed).Set(float64(failed))
+}
+
 func (ctlr *Controller) processIPAM(ipam *ficV1.IPAM) {
 	var hostSpecsToProcess []ficV1.HostSpec
 
+	setIPAMMetrics(ipam)
+
 	for _, ipSpec := range ipam.Status.IPStatus {
 		ipHostSpec := ficV1.HostSpec{
 			Key:       ipSpec.Key,
@@ -2974,14 +3213,14 @@ func (ctlr *Controller) processIPAM(ipam *ficV1.IPAM) {
 				crInf, ok1 := ctlr.getNamespacedCRInformer(rsc.Namespace)
 				comInf, ok2 := ctlr.getNamespacedCommonInformer(rsc.Namespace)
 				if !ok1 || !ok2 {
-					log.Errorf("Informer not found for namespace: %v", rsc.Namespace)
+					workerLog.Errorf("Informer not found for namespace: %v", rsc.Namespace)
 					continue
 				}
 				switch rsc.Kind {
 				case VirtualServer:
 					item, exists, err := crInf.vsInformer.GetIndexer().GetByKey(fmt.Sprintf("%s/%s", rsc.Namespace, rsc.Name))
 					if !exists || err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 						continue
 					}
 					ctlr.TeemData.Lock()
@@ -2990,12 +3229,12 @@ func (ctlr *Controller) processIPAM(ipam *ficV1.IPAM) {
 					vs := item.(*cisapiv1.VirtualServer)
 					err = ctlr.processVirtualServers(vs, false)
 					if err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 					}
 				case TransportServer:
 					item, exists, err := crInf.tsInformer.GetIndexer().GetByKey(fmt.Sprintf("%s/%s", rsc.Namespace, rsc.Name))
 					if !exists || err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 						continue
 					}
 					ctlr.TeemData.Lock()
@@ -3004,23 +3243,23 @@ func (ctlr *Controller) processIPAM(ipam *ficV1.IPAM) {
 					ts := item.(*cisapiv1.TransportServer)
 					err = ctlr.processTransportServers(ts, false)
 					if err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 					}
 				case IngressLink:
 					item, exists, err := crInf.ilInformer.GetIndexer().GetByKey(fmt.Sprintf("%s/%s", rsc.Namespace, rsc.Name))
 					if !exists || err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 						continue
 					}
 					il := item.(*cisapiv1.IngressLink)
 					err = ctlr.processIngressLink(il, false)
 					if err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 					}
 				case Service:
 					item, exists, err := comInf.svcInformer.GetIndexer().GetByKey(fmt.Sprintf("%s/%s", rsc.Namespace, rsc.Name))
 					if !exists || err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 						continue
 					}
 					ctlr.TeemData.Lock()
@@ -3029,10 +3268,10 @@ func (ctlr *Controller) processIPAM(ipam *ficV1.IPAM) {
 					svc := item.(*v1.Service)
 					err = ctlr.processLBServices(svc, false)
 					if err != nil {
-						log.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
+						workerLog.Errorf("[IPAM] Unable to process IPAM entry: %v", hostSpec)
 					}
 				default:
-					log.Errorf("Invalid resource kind: %v", rsc.Kind)
+					workerLog.Errorf("Invalid resource kind: %v", rsc.Kind)
 				}
 			}
 		}
@@ -3043,13 +3282,16 @@ func (ctlr *Controller) processIngressLink(
 	ingLink *cisapiv1.IngressLink,
 	isILDeleted bool,
 ) error {
+	rscLog := resourceLogger(IngressLink, ingLink.Namespace, ingLink.Name, "")
 
 	startTime := time.Now()
 	defer func() {
 		endTime := time.Now()
-		log.Debugf("Finished syncing Ingress Links %+v (%v)",
+		rscLog.Debugf("Finished syncing Ingress Links %+v (%v)",
 			ingLink, endTime.Sub(startTime))
 	}()
+	// clear the warnings of the earlier processing, warnings are added again if still invalid
+	prometheus.ClearConfigurationWarnings(IngressLink, ingLink.ObjectMeta.Namespace, ingLink.ObjectMeta.Name)
 	// Skip validation for a deleted ingressLink
 	if !isILDeleted {
 		// check if the virutal server matches all the requirements.
@@ -3057,12 +3299,11 @@ func (ctlr *Controller) processIngressLink(
 		valid := ctlr.checkValidIngressLink(ingLink)
 		if false == valid {
 			warning := fmt.Sprintf("ingressLink %s, is not valid", vkey)
-			log.Warningf(warning)
-			prometheus.ConfigurationWarnings.WithLabelValues(IngressLink, ingLink.ObjectMeta.Namespace, ingLink.ObjectMeta.Name, warning).Set(1)
+			rscLog.Warningf(warning)
+			prometheus.AddConfigurationWarning(IngressLink, ingLink.ObjectMeta.Namespace, ingLink.ObjectMeta.Name, warning)
 			return nil
 		}
 	}
-	prometheus.ConfigurationWarnings.WithLabelValues(IngressLink, ingLink.ObjectMeta.Namespace, ingLink.ObjectMeta.Name, "").Set(0)
 	var ingLinks []*cisapiv1.IngressLink
 	if ingLink.Spec.Host != "" {
 		ingLinks = ctlr.getAllIngLinkFromMonitoredNamespaces()
@@ -3110,7 +3351,7 @@ func (ctlr *Controller) processIngressLink(
 				ctlr.updateResourceStatus(IngressLink, ingLink, ip, "", errors.New(fmt.Sprintf("[IPAM] IP address requested for IngressLink: %s/%s", ingLink.Namespace, ingLink.Name)))
 				return nil
 			}
-			log.Debugf("[IPAM] requested IP for ingLink %v is: %v", ingLink.ObjectMeta.Name, ip)
+			rscLog.Debugf("[IPAM] requested IP for ingLink %v is: %v", ingLink.ObjectMeta.Name, ip)
 			if ip == "" {
 				ctlr.updateResourceStatus(IngressLink, ingLink, ip, "", errors.New(fmt.Sprintf("[IPAM] requested IP for ingLink %v is empty.", ingLink.ObjectMeta.Name)))
 				return nil
@@ -3180,12 +3421,12 @@ func (ctlr *Controller) processIngressLink(
 	if ctlr.PoolMemberType == NodePort || (ctlr.PoolMemberType == Auto && svc.Spec.Type != v1.ServiceTypeClusterIP) {
 		targetPort = getNodeport(svc, nginxMonitorPort)
 		if targetPort == 0 {
-			log.Errorf("Nodeport not found for nginx monitor port: %v", nginxMonitorPort)
+			rscLog.Errorf("Nodeport not found for nginx monitor port: %v", nginxMonitorPort)
 		}
 	} else if ctlr.PoolMemberType == NodePortLocal {
 		targetPort = ctlr.getNodeportForNPL(nginxMonitorPort, svc.Name, svc.Namespace)
 		if targetPort == 0 {
-			log.Errorf("Nodeport not found for nginx monitor port: %v", nginxMonitorPort)
+			rscLog.Errorf("Nodeport not found for nginx monitor port: %v", nginxMonitorPort)
 		}
 	}
 
@@ -3275,11 +3516,11 @@ func (ctlr *Controller) getAllIngressLinks(namespace string) []*cisapiv1.Ingress
 
 	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	if crInf.ilInformer == nil {
-		log.Errorf("ingressLink informer not found for namespace: %v ", namespace)
+		workerLog.Errorf("ingressLink informer not found for namespace: %v ", namespace)
 		return nil
 	}
 	var orderedIngLinks []interface{}
@@ -3290,7 +3531,7 @@ func (ctlr *Controller) getAllIngressLinks(namespace string) []*cisapiv1.Ingress
 		// Get list of VirtualServers and process them.
 		orderedIngLinks, err = crInf.ilInformer.GetIndexer().ByIndex("namespace", namespace)
 		if err != nil {
-			log.Errorf("Unable to get list of VirtualServers for namespace '%v': %v",
+			workerLog.Errorf("Unable to get list of VirtualServers for namespace '%v': %v",
 				namespace, err)
 			return nil
 		}
@@ -3384,6 +3625,9 @@ func (ctlr *Controller) setLBServiceIngressStatus(
 		svc.Status.LoadBalancer.Ingress[0] = lbIngress
 	}
 
+	if !ctlr.isLeader() {
+		return
+	}
 	_, updateErr := ctlr.clientsets.KubeClient.CoreV1().Services(svc.ObjectMeta.Namespace).UpdateStatus(context.TODO(), svc, metav1.UpdateOptions{})
 	if nil != updateErr {
 		// Multi-service causes the controller to try to update the status multiple times
@@ -3393,7 +3637,7 @@ func (ctlr *Controller) setLBServiceIngressStatus(
 		}
 		warning := fmt.Sprintf(
 			"Error when setting Service LB Ingress status IP: %v", updateErr)
-		log.Warning(warning)
+		workerLog.Warning(warning)
 		ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeWarning, "StatusIPError", warning)
 	} else {
 		message := fmt.Sprintf("F5 CIS assigned LoadBalancer IP: %v", ip)
@@ -3405,12 +3649,14 @@ func (ctlr *Controller) unSetLBServiceIngressStatus(
 	svc *v1.Service,
 	ip string,
 ) {
-
+	if !ctlr.isLeader() {
+		return
+	}
 	svcName := svc.Namespace + "/" + svc.Name
 	comInf, _ := ctlr.getNamespacedCommonInformer(svc.Namespace)
 	service, found, err := comInf.svcInformer.GetIndexer().GetByKey(svcName)
 	if !found || err != nil {
-		log.Debugf("Unable to Update Status of Service: %v due to unavailability", svcName)
+		workerLog.Debugf("Unable to Update Status of Service: %v due to unavailability", svcName)
 		return
 	}
 	svc = service.(*v1.Service)
@@ -3432,12 +3678,12 @@ func (ctlr *Controller) unSetLBServiceIngressStatus(
 			// Multi-service causes the controller to try to update the status multiple times
 			// at once. Ignore this error.
 			if strings.Contains(updateErr.Error(), "object has been modified") {
-				log.Debugf("Error while updating service: %v %v", svcName, updateErr.Error())
+				workerLog.Debugf("Error while updating service: %v %v", svcName, updateErr.Error())
 				return
 			}
 			warning := fmt.Sprintf(
 				"Error when unsetting Service LB Ingress status IP: %v", updateErr)
-			log.Warning(warning)
+			workerLog.Warning(warning)
 			ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeWarning, "StatusIPError", warning)
 		} else {
 			message := fmt.Sprintf("F5 CIS unassigned LoadBalancer IP: %v", ip)
@@ -3536,13 +3782,13 @@ func getNodeport(svc *v1.Service, servicePort int32) int32 {
 /*func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip string, statusOk string) {
 	// Set the vs status to include the virtual IP address
 	vsStatus := cisapiv1.VirtualServerStatus{VSAddress: ip, StatusOk: statusOk}
-	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", vsStatus, vs.Name, vs.Namespace)
+	workerLog.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", vsStatus, vs.Name, vs.Namespace)
 	vs.Status = vsStatus
 	vs.Status.VSAddress = ip
 	vs.Status.StatusOk = statusOk
 	_, updateErr := ctlr.clientsets.KubeCRClient.CisV1().VirtualServers(vs.ObjectMeta.Namespace).UpdateStatus(context.TODO(), vs, metav1.UpdateOptions{})
 	if nil != updateErr {
-		log.Debugf("Error while updating virtual server status:%v", updateErr)
+		workerLog.Debugf("Error while updating virtual server status:%v", updateErr)
 		return
 	}
 }*/
@@ -3551,13 +3797,16 @@ func getNodeport(svc *v1.Service, servicePort int32) int32 {
 func (ctlr *Controller) updateTransportServerStatus(ts *cisapiv1.TransportServer, ip string, statusOk string) {
 	// Set the vs status to include the virtual IP address
 	tsStatus := cisapiv1.TransportServerStatus{VSAddress: ip, StatusOk: statusOk}
-	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", tsStatus, ts.Name, ts.Namespace)
+	workerLog.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", tsStatus, ts.Name, ts.Namespace)
 	ts.Status = tsStatus
 	ts.Status.VSAddress = ip
 	ts.Status.StatusOk = statusOk
+	if !ctlr.isLeader() {
+		return
+	}
 	_, updateErr := ctlr.clientsets.KubeCRClient.CisV1().TransportServers(ts.ObjectMeta.Namespace).UpdateStatus(context.TODO(), ts, metav1.UpdateOptions{})
 	if nil != updateErr {
-		log.Debugf("Error while updating Transport server status:%v", updateErr)
+		workerLog.Debugf("Error while updating Transport server status:%v", updateErr)
 		return
 	}
 }
@@ -3567,21 +3816,26 @@ func (ctlr *Controller) updateIngressLinkStatus(il *cisapiv1.IngressLink, ip str
 	// Set the vs status to include the virtual IP address
 	ilStatus := cisapiv1.IngressLinkStatus{VSAddress: ip}
 	il.Status = ilStatus
+	if !ctlr.isLeader() {
+		return
+	}
 	_, updateErr := ctlr.clientsets.KubeCRClient.CisV1().IngressLinks(il.ObjectMeta.Namespace).UpdateStatus(context.TODO(), il, metav1.UpdateOptions{})
 	if nil != updateErr {
-		log.Debugf("Error while updating ingresslink status:%v", updateErr)
+		workerLog.Debugf("Error while updating ingresslink status:%v", updateErr)
 		return
 	}
 }
 
 func (ctlr *Controller) updateResourceStatus(rscType string, obj interface{}, ip string, statusOk string, err error) {
-
+	// the configuration warnings are reported by the standby replicas as well
+	standby := !ctlr.isLeader()
 	switch rscType {
 	case TransportServer:
 		ts := obj.(*cisapiv1.TransportServer)
 		tsStatus := cisapiv1.TransportServerStatus{LastUpdated: metav1.Now()}
 		if err != nil {
 			tsStatus.Error = err.Error()
+			prometheus.AddConfigurationWarning(TransportServer, ts.Namespace, ts.Name, err.Error())
 		} else if ip != "" {
 			tsStatus.VSAddress = ip
 			tsStatus.StatusOk = statusOk
@@ -3589,15 +3843,19 @@ func (ctlr *Controller) updateResourceStatus(rscType string, obj interface{}, ip
 			tsStatus.Error = fmt.Sprintf("Missing label f5cr on TS %v/%v", ts.Namespace, ts.Name)
 		}
 		ts.Status = tsStatus
+		if standby {
+			return
+		}
 		_, updateErr := ctlr.clientsets.KubeCRClient.CisV1().TransportServers(ts.ObjectMeta.Namespace).UpdateStatus(context.TODO(), ts, metav1.UpdateOptions{})
 		if nil != updateErr {
-			log.Debugf("Error while updating TS status:%v", updateErr)
+			workerLog.Debugf("Error while updating TS status:%v", updateErr)
 		}
 	case IngressLink:
 		il := obj.(*cisapiv1.IngressLink)
 		ilStatus := cisapiv1.IngressLinkStatus{LastUpdated: metav1.Now()}
 		if err != nil {
 			ilStatus.Error = err.Error()
+			prometheus.AddConfigurationWarning(IngressLink, il.Namespace, il.Name, err.Error())
 		} else if ip != "" {
 			ilStatus.VSAddress = ip
 			ilStatus.StatusOk = statusOk
@@ -3605,9 +3863,12 @@ func (ctlr *Controller) updateResourceStatus(rscType string, obj interface{}, ip
 			ilStatus.Error = fmt.Sprintf("Missing label f5cr on il %v/%v", il.Namespace, il.Name)
 		}
 		il.Status = ilStatus
+		if standby {
+			return
+		}
 		_, updateErr := ctlr.clientsets.KubeCRClient.CisV1().IngressLinks(il.ObjectMeta.Namespace).UpdateStatus(context.TODO(), il, metav1.UpdateOptions{})
 		if nil != updateErr {
-			log.Debugf("Error while updating il status:%v", updateErr)
+			workerLog.Debugf("Error while updating il status:%v", updateErr)
 		}
 	}
 }
@@ -3617,16 +3878,16 @@ func (ctlr *Controller) GetService(namespace, serviceName string) *v1.Service {
 	svcKey := namespace + "/" + serviceName
 	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	svc, found, err := comInf.svcInformer.GetIndexer().GetByKey(svcKey)
 	if err != nil {
-		log.Infof("Error fetching service %v from the store: %v", svcKey, err)
+		workerLog.Infof("Error fetching service %v from the store: %v", svcKey, err)
 		return nil
 	}
 	if !found {
-		log.Errorf("Error: Service %v not found", svcKey)
+		workerLog.Errorf("Error: Service %v not found", svcKey)
 		return nil
 	}
 	return svc.(*v1.Service)
@@ -3637,27 +3898,27 @@ func (ctlr *Controller) GetPodsForService(namespace, serviceName string, nplAnno
 	svcKey := namespace + "/" + serviceName
 	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", namespace)
 		return nil
 	}
 	svc, found, err := comInf.svcInformer.GetIndexer().GetByKey(svcKey)
 	if err != nil {
-		log.Infof("Error fetching service %v from the store: %v", svcKey, err)
+		workerLog.Infof("Error fetching service %v from the store: %v", svcKey, err)
 		return nil
 	}
 	if !found {
-		log.Errorf("Error: Service %v not found", svcKey)
+		workerLog.Errorf("Error: Service %v not found", svcKey)
 		return nil
 	}
 	annotations := svc.(*v1.Service).Annotations
 	if _, ok := annotations[NPLSvcAnnotation]; !ok && nplAnnotationRequired {
-		log.Errorf("NPL annotation %v not set on service %v", NPLSvcAnnotation, serviceName)
+		workerLog.Errorf("NPL annotation %v not set on service %v", NPLSvcAnnotation, serviceName)
 		return nil
 	}
 
 	selector := svc.(*v1.Service).Spec.Selector
 	if len(selector) == 0 {
-		log.Infof("label selector is not set on svc")
+		workerLog.Infof("label selector is not set on svc")
 		return nil
 	}
 	labelSelector, err := metav1.ParseToLabelSelector(labels.Set(selector).AsSelectorPreValidated().String())
@@ -3668,7 +3929,7 @@ func (ctlr *Controller) GetPodsForService(namespace, serviceName string, nplAnno
 	pl, _ := createLabel(labels.SelectorFromSet(labelmap).String())
 	podList, err := listerscorev1.NewPodLister(comInf.podInformer.GetIndexer()).Pods(namespace).List(pl)
 	if err != nil {
-		log.Debugf("Got error while listing Pods with selector %v: %v", selector, err)
+		workerLog.Debugf("Got error while listing Pods with selector %v: %v", selector, err)
 		return nil
 	}
 	return podList
@@ -3680,12 +3941,12 @@ func (ctlr *Controller) GetServicesForPod(pod *v1.Pod, clusterName string) *v1.S
 	if clusterName == "" {
 		comInf, ok := ctlr.getNamespacedCommonInformer(pod.Namespace)
 		if !ok {
-			log.Errorf("Informer not found for namespace: %v ", pod.Namespace)
+			workerLog.Errorf("Informer not found for namespace: %v ", pod.Namespace)
 			return nil
 		}
 		services, err = comInf.svcInformer.GetIndexer().ByIndex("namespace", pod.Namespace)
 		if err != nil {
-			log.Debugf("Unable to find services for namespace %v with error: %v", pod.Namespace, err)
+			workerLog.Debugf("Unable to find services for namespace %v with error: %v", pod.Namespace, err)
 		}
 	} else if _, ok := ctlr.multiClusterPoolInformers[clusterName]; ok {
 		var poolInf *MultiClusterPoolInformer
@@ -3694,16 +3955,16 @@ func (ctlr *Controller) GetServicesForPod(pod *v1.Pod, clusterName string) *v1.S
 			poolInf, found = ctlr.multiClusterPoolInformers[clusterName][pod.Namespace]
 		}
 		if !found {
-			log.Errorf("[MultiCluster] Informer not found for namespace: %v, cluster: %s", pod.Namespace, clusterName)
+			workerLog.Errorf("[MultiCluster] Informer not found for namespace: %v, cluster: %s", pod.Namespace, clusterName)
 			return nil
 		}
 		services, err = poolInf.svcInformer.GetIndexer().ByIndex("namespace", pod.Namespace)
 		if err != nil {
-			log.Debugf("[MultiCluster] Unable to find services for namespace %v in cluster %s with error: %v", pod.Namespace,
+			workerLog.Debugf("[MultiCluster] Unable to find services for namespace %v in cluster %s with error: %v", pod.Namespace,
 				clusterName, err)
 		}
 	} else {
-		log.Errorf("[MultiCluster] Informer not found for namespace: %v, cluster: %s", pod.Namespace, clusterName)
+		workerLog.Errorf("[MultiCluster] Informer not found for namespace: %v, cluster: %s", pod.Namespace, clusterName)
 		return nil
 	}
 	for _, obj := range services {
@@ -3744,19 +4005,19 @@ func (ctlr *Controller) processPod(pod *v1.Pod, ispodDeleted bool) error {
 	podKey := pod.Namespace + "/" + pod.Name
 	if ispodDeleted {
 		delete(ctlr.resources.nplStore, podKey)
-		log.Debugf("Deleting Pod '%v/%v' from CIS cache as it's not referenced by monitored resources", pod.Namespace, pod.Name)
+		workerLog.Debugf("Deleting Pod '%v/%v' from CIS cache as it's not referenced by monitored resources", pod.Namespace, pod.Name)
 		return nil
 	}
 	ann := pod.GetAnnotations()
 	var annotations []NPLAnnotation
 	if val, ok := ann[NPLPodAnnotation]; ok {
 		if err := json.Unmarshal([]byte(val), &annotations); err != nil {
-			log.Errorf("key: %s, got error while unmarshaling NPL annotations: %v", podKey, err)
+			workerLog.Errorf("key: %s, got error while unmarshaling NPL annotations: %v", podKey, err)
 		}
-		log.Debugf("Adding Pod '%v/%v' in CIS cache", pod.Namespace, pod.Name)
+		workerLog.Debugf("Adding Pod '%v/%v' in CIS cache", pod.Namespace, pod.Name)
 		ctlr.resources.nplStore[podKey] = annotations
 	} else {
-		log.Debugf("key: %s, NPL annotation not found for Pod", pod.Name)
+		workerLog.Debugf("key: %s, NPL annotation not found for Pod", pod.Name)
 		delete(ctlr.resources.nplStore, podKey)
 	}
 	return nil
@@ -3776,7 +4037,7 @@ func (ctlr *Controller) processCNIConfig(configCR *cisapiv1.DeployConfig) error
 	if ctlr.PoolMemberType == NodePort || ctlr.PoolMemberType == NodePortLocal {
 		ctlr.shareNodes = true
 		if ctlr.StaticRoutingMode && ctlr.PoolMemberType != Auto {
-			log.Warningf("static route CNI: %v not supported with nodeport/nodeportlocal mode. Only supported with cluster mode", ctlr.OrchestrationCNI)
+			workerLog.Warningf("static route CNI: %v not supported with nodeport/nodeportlocal mode. Only supported with cluster mode", ctlr.OrchestrationCNI)
 		}
 	} else if ctlr.PoolMemberType == Cluster || ctlr.PoolMemberType == Auto {
 		if ctlr.StaticRoutingMode {
@@ -3799,14 +4060,24 @@ func (ctlr *Controller) processConfigCR(configCR *cisapiv1.DeployConfig, isDelet
 		endTime := time.Now()
 		key := configCR.Namespace + string('/') + configCR.Name
 		if ctlr.CISConfigCRKey == key {
-			log.Debugf("Finished syncing global DeployConfig CR: %v/%v (%v)",
+			workerLog.Debugf("Finished syncing global DeployConfig CR: %v/%v (%v)",
 				configCR.Namespace, configCR.Name, endTime.Sub(startTime))
 		} else {
-			log.Debugf("Finished syncing local DeployConfig CR: %v/%v (%v)",
+			workerLog.Debugf("Finished syncing local DeployConfig CR: %v/%v (%v)",
 				configCR.Namespace, configCR.Name, endTime.Sub(startTime))
 		}
 
 	}()
+	// apply the runtime log config of the global DeployConfig
+	if ctlr.logConfig != nil && ctlr.isGlobalExtendedCR(configCR) {
+		logConfig := configCR.Spec.LogConfig
+		if isDelete {
+			logConfig = cisapiv1.LogConfig{}
+		}
+		if err := ctlr.logConfig.applyDeployConfig(logConfig); err != nil {
+			workerLog.Errorf("[LOG] Invalid log config in DeployConfig %v/%v: %v", configCR.Namespace, configCR.Name, err)
+		}
+	}
 	// get bigIpConfig and start/stop agent if needed
 	bigipconfig := configCR.Spec.BigIpConfig
 	ctlr.handleBigipConfigUpdates(bigipconfig)
@@ -3861,7 +4132,7 @@ func (ctlr *Controller) processConfigCR(configCR *cisapiv1.DeployConfig, isDelet
 				es.LocalClusterAdminState == clustermanager.Disable || es.LocalClusterAdminState == clustermanager.Offline {
 				ctlr.clusterAdminState[""] = es.LocalClusterAdminState
 			} else {
-				log.Warningf("[MultiCluster] Invalid cluster adminState: %v specified for local cluster, supported "+
+				workerLog.Warningf("[MultiCluster] Invalid cluster adminState: %v specified for local cluster, supported "+
 					"values (enable, disable, offline). Defaulting to enable", es.LocalClusterAdminState)
 				ctlr.clusterAdminState[""] = clustermanager.Enable
 			}
@@ -3899,7 +4170,7 @@ func (ctlr *Controller) reprocessAllCustomResourcesOnConfigCRUpdate() {
 			var crInf *CRInformer
 			var ok bool
 			if crInf, ok = ctlr.getNamespacedCRInformer(resRef.namespace); !ok {
-				log.Debugf("skipping resource %v as informer not found for namespace", resRef, resRef.namespace)
+				workerLog.Debugf("skipping resource %v as informer not found for namespace %v", resRef, resRef.namespace)
 				continue
 			}
 			switch resRef.kind {
@@ -4033,7 +4304,7 @@ func (ctlr *Controller) getTLSProfilesForSecret(secret *v1.Secret) []*cisapiv1.T
 
 	crInf, ok := ctlr.getNamespacedCRInformer(secret.Namespace)
 	if !ok {
-		log.Errorf("Informer not found for namespace: %v", secret.Namespace)
+		workerLog.Errorf("Informer not found for namespace: %v", secret.Namespace)
 		return nil
 	}
 
@@ -4041,7 +4312,7 @@ func (ctlr *Controller) getTLSProfilesForSecret(secret *v1.Secret) []*cisapiv1.T
 	var err error
 	orderedTLS, err = crInf.tlsInformer.GetIndexer().ByIndex("namespace", secret.Namespace)
 	if err != nil {
-		log.Errorf("Unable to get list of TLS Profiles for namespace '%v': %v",
+		workerLog.Errorf("Unable to get list of TLS Profiles for namespace '%v': %v",
 			secret.Namespace, err)
 		return nil
 	}
@@ -4106,7 +4377,7 @@ func (ctlr *Controller) fetchNodesFromClusters() []interface{} {
 			if config, ok := ctlr.multiClusterConfigs.ClusterConfigs[clusterName]; ok {
 				nodesObj, err := config.KubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: ctlr.resourceSelectorConfig.NodeLabel})
 				if err != nil {
-					log.Debugf("[MultiCluster] Unable to fetch nodes for cluster %v with err %v", clusterName, err)
+					workerLog.Debugf("[MultiCluster] Unable to fetch nodes for cluster %v with err %v", clusterName, err)
 				} else {
 					for _, node := range nodesObj.Items {
 						node := node
@@ -4187,6 +4458,11 @@ func (ctlr *Controller) handleBigipConfigUpdates(config []cisapiv1.BigIpConfig)
 				ctlr.RequestHandler.stopPostManager(existingConfig)
 				//remove bigipconfig from bigipMap
 				delete(ctlr.bigIpConfigMap, existingConfig)
+				// stop the central manager if no other bigip is managed by it
+				ctlr.releaseCMTokenManager(existingConfig)
+				if ctlr.networkManager != nil {
+					ctlr.networkManager.SetTokenManager(existingConfig.BigIpAddress, nil)
+				}
 				// remove the bigip from the deploy config status
 				ctlr.CMTokenManager.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.BigIPStatus{
 					BigIPAddress: existingConfig.BigIpAddress,
@@ -4197,7 +4473,10 @@ func (ctlr *Controller) handleBigipConfigUpdates(config []cisapiv1.BigIpConfig)
 		for _, newConfig := range config {
 			if !slices.Contains(existingBigipConfig, newConfig) {
 				// start agent
-				ctlr.RequestHandler.startPostManager(newConfig)
+				if err := ctlr.startPostManager(newConfig); err != nil {
+					workerLog.Errorf("Unable to start post manager for BIG-IP %v: %v", newConfig.BigIpAddress, err)
+					continue
+				}
 				//update bigipMap with new bigipconfig
 				ctlr.bigIpConfigMap[newConfig] = BigIpResourceConfig{ltmConfig: make(LTMConfig), gtmConfig: make(GTMConfig)}
 			}
@@ -4205,6 +4484,19 @@ func (ctlr *Controller) handleBigipConfigUpdates(config []cisapiv1.BigIpConfig)
 	}
 }
 
+// startPostManager starts the post manager for the bigip with the token manager of the central manager managing it
+func (ctlr *Controller) startPostManager(config cisapiv1.BigIpConfig) error {
+	tm, err := ctlr.getCMTokenManager(config)
+	if err != nil {
+		return err
+	}
+	ctlr.RequestHandler.startPostManager(config, tm)
+	if ctlr.networkManager != nil {
+		ctlr.networkManager.SetTokenManager(config.BigIpAddress, tm)
+	}
+	return nil
+}
+
 func (ctlr *Controller) getPartitionForBIGIP(bigipLabel string) string {
 	//get partition from bigip
 	for bigipconfig, _ := range ctlr.bigIpConfigMap {
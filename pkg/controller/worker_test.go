@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -152,6 +153,20 @@ var _ = Describe("Worker Tests", func() {
 		)
 	})
 
+	It("Clear the configuration warnings of the associated virtuals", func() {
+		vrt2 := vrt1.DeepCopy()
+		vrt2.Name = "SampleVS2"
+		vrt2.Spec.Pools[0].Path = "/path2"
+		mockCtlr.crInformers["default"].vsInformer.GetStore().Add(vrt1)
+		mockCtlr.crInformers["default"].vsInformer.GetStore().Add(vrt2)
+		bigIPPrometheus.ConfigurationWarnings.Reset()
+		bigIPPrometheus.AddConfigurationWarning(VirtualServer, namespace, vrt2.Name, "stale warning")
+		_ = mockCtlr.processVirtualServers(vrt1, false)
+		Expect(getGaugeValue(bigIPPrometheus.ConfigurationWarnings.WithLabelValues(VirtualServer, namespace, vrt2.Name,
+			"stale warning"))).To(BeZero(), "warnings of the associated virtual should be cleared")
+		bigIPPrometheus.ConfigurationWarnings.Reset()
+	})
+
 	Describe("Validating Ingress link functions", func() {
 		var namespace string
 		BeforeEach(func() {
@@ -311,6 +326,31 @@ var _ = Describe("Worker Tests", func() {
 			Expect(ipamCR).To(BeNil(), "Failed to GET IPAM")
 		})
 
+		It("Request IP Address on the standby replica", func() {
+			mockCtlr.ipamHandler.SetStandby(true)
+			Expect(mockCtlr.ipamHandler.CreateIPAMResource()).To(Succeed())
+			Expect(mockCtlr.ipamHandler.GetIPAMCR()).To(BeNil(), "Standby replica shouldn't create the IPAM CR")
+			resRef := ipmanager.ResourceRef{Namespace: namespace, Name: "test-vs", Kind: VirtualServer}
+			ip, status := mockCtlr.ipamHandler.RequestIP("test", "foo.com", "foo.com_host", resRef)
+			Expect(status).To(Equal(ipmanager.Requested))
+			Expect(ip).To(BeEmpty())
+			releasedRef := ipmanager.ResourceRef{Namespace: namespace, Name: "released-vs", Kind: VirtualServer}
+			_, _ = mockCtlr.ipamHandler.RequestIP("test", "bar.com", "bar.com_host", releasedRef)
+			mockCtlr.ipamHandler.ReleaseIP("test", "bar.com", "bar.com_host", releasedRef)
+
+			mockCtlr.ipamHandler.SetStandby(false)
+			Expect(mockCtlr.ipamHandler.CreateIPAMResource()).To(Succeed())
+			_, status = mockCtlr.ipamHandler.RequestIP("test", "", "ns/ts", resRef)
+			Expect(status).To(Equal(ipmanager.Requested))
+			Expect(mockCtlr.ipamHandler.GetIPAMCR().Spec.HostSpecs).To(HaveLen(1))
+			// the leader requests the host specs of the standby replica
+			mockCtlr.ipamHandler.RequestPendingIPs()
+			ipamCR := mockCtlr.ipamHandler.GetIPAMCR()
+			Expect(ipamCR.Spec.HostSpecs).To(HaveLen(2))
+			Expect(*ipamCR.Spec.HostSpecs[1]).To(Equal(ficV1.HostSpec{IPAMLabel: "test", Host: "foo.com",
+				Key: "foo.com_host"}))
+		})
+
 		It("Request IP Address", func() {
 
 			testSpec := make(map[string]string)
@@ -581,6 +621,26 @@ var _ = Describe("Worker Tests", func() {
 			Expect(res[1]).To(Equal(vrt3), "Wrong list of Virtual Servers")
 		})
 
+		It("Filter VS with hosts for TLSProfile", func() {
+			tlsProf := test.NewTLSProfile("sampleTLS", namespace, cisapiv1.TLSProfileSpec{
+				Hosts: []string{"test.com", "*.example.com"},
+			})
+			vrt2 := test.NewVirtualServer(
+				"SampleVS2",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:                 "test2.com",
+					Hosts:                []string{"test.com"},
+					VirtualServerAddress: "1.2.3.5",
+					TLSProfileName:       "sampleTLS",
+				})
+			res := getVirtualServersForTLSProfile([]*cisapiv1.VirtualServer{vrt2}, tlsProf)
+			Expect(res).To(Equal([]*cisapiv1.VirtualServer{vrt2}), "Wrong list of Virtual Servers")
+			Expect(tlsProfileHasHost(tlsProf, "test.com")).To(BeTrue())
+			Expect(tlsProfileHasHost(tlsProf, "foo.example.com")).To(BeTrue())
+			Expect(tlsProfileHasHost(tlsProf, "test2.com")).To(BeFalse())
+		})
+
 		It("VS Handling HTTP", func() {
 			Expect(doesVSHandleHTTP(vrt1)).To(BeTrue(), "HTTP VS in invalid")
 			vrt1.Spec.TLSProfileName = "TLSProf"
@@ -640,6 +700,26 @@ var _ = Describe("Worker Tests", func() {
 				Expect(len(virts)).To(Equal(1), "Wrong number of Virtual Servers")
 				Expect(virts[0].Name).To(Equal("SampleVS2"), "Wrong Virtual Server")
 			})
+			It("Duplicate Paths with match conditions", func() {
+				vrt3.Spec.Pools[0].Path = "/path"
+				vrt3.Spec.Pools[0].Match = &cisapiv1.PoolMatch{
+					Headers: []cisapiv1.HTTPMatchCondition{{Name: "x-canary", Value: "true"}},
+				}
+				virts := mockCtlr.getAssociatedVirtualServers(vrt2,
+					[]*cisapiv1.VirtualServer{vrt2, vrt3},
+					false, &VSSpecProperties{})
+				Expect(len(virts)).To(Equal(2), "Pools of the same path with different match should be unique")
+
+				vrt4.Spec.Pools[0].Path = "/path"
+				vrt4.Spec.Pools[0].Match = &cisapiv1.PoolMatch{
+					Headers: []cisapiv1.HTTPMatchCondition{{Name: "x-canary", Value: "true"}},
+				}
+				virts = mockCtlr.getAssociatedVirtualServers(vrt2,
+					[]*cisapiv1.VirtualServer{vrt2, vrt3, vrt4},
+					false, &VSSpecProperties{})
+				Expect(len(virts)).To(Equal(2), "Wrong number of Virtual Servers")
+				Expect(virts[1].Name).To(Equal("SampleVS3"), "Wrong Virtual Server")
+			})
 			It("Verify Pool Based WAF ", func() {
 				vrt2.Spec.Pools[0].WAF = "/Common/WAF_Policy"
 				virts := mockCtlr.getAssociatedVirtualServers(vrt2,
@@ -1616,7 +1696,7 @@ var _ = Describe("Worker Tests", func() {
 				status: http.StatusOK,
 				body:   "",
 			}}, http.MethodPost)
-			mockPM.AS3PostManager.firstPost = false
+			mockPM.AS3PostManager.firstPost.Store(false)
 			bigIpKey := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
 			mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpKey] = mockPM.PostManager
 			mockCtlr.RequestHandler.userAgent = "as3"
@@ -3135,7 +3215,7 @@ var _ = Describe("Worker Tests", func() {
 				status: http.StatusOK,
 				body:   "",
 			}}, http.MethodPost)
-			mockPM.AS3PostManager.firstPost = false
+			mockPM.AS3PostManager.firstPost.Store(false)
 			bigIpKey := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
 			mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpKey] = mockPM.PostManager
 			mockCtlr.RequestHandler.userAgent = "as3"
@@ -3243,10 +3323,10 @@ var _ = Describe("Worker Tests", func() {
 			})
 
 			It("Process Global ConfigCR", func() {
-				mockCtlr.initState = true
+				mockCtlr.initState.Store(true)
 				mockCtlr.processConfigCR(configCR, false)
 				mockCtlr.processConfigCR(localConfigCR, false)
-				mockCtlr.initState = false
+				mockCtlr.initState.Store(false)
 				mockCtlr.processConfigCR(configCR, false)
 				mockCtlr.processConfigCR(localConfigCR, false)
 
@@ -3999,13 +4079,13 @@ var _ = Describe("Worker Tests", func() {
 				bigIpKey := cisapiv1.BigIpConfig{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
 				go mockCtlr.RequestHandler.startRequestHandler()
 				go mockCtlr.responseHandler(mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpKey].respChan)
-				mockCtlr.initState = true
+				mockCtlr.initState.Store(true)
 				mockCtlr.resources.invertedNamespaceLabelMap[namespace] = routeGroup
 				mockCtlr.addConfigCR(configCR)
 				mockCtlr.processResources()
 				routeGroup := "default"
 
-				mockCtlr.initState = false
+				mockCtlr.initState.Store(false)
 
 				mockCtlr.resources.invertedNamespaceLabelMap[namespace] = routeGroup
 				mockCtlr.managedResources.ManageCustomResources = true
@@ -4348,7 +4428,7 @@ var _ = Describe("CIS Init time test cases", func() {
 
 	BeforeEach(func() {
 		mockCtlr = newMockController()
-		mockCtlr.initState = true
+		mockCtlr.initState.Store(true)
 		mockCtlr.managedResources = ManagedResources{
 			ManageRoutes:          true,
 			ManageCustomResources: true,
@@ -4464,15 +4544,15 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeFalse())
 				Expect(mockCtlr.initialResourceCount).To(Equal(2))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 
 				mockCtlr.processKeyAtInitTime(rKey)
 				Expect(mockCtlr.initialResourceCount).To(Equal(1))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 
 				mockCtlr.processKeyAtInitTime(rKey)
 				Expect(mockCtlr.initialResourceCount).To(Equal(0))
-				Expect(mockCtlr.initState).To(BeFalse())
+				Expect(mockCtlr.initState.Load()).To(BeFalse())
 			})
 
 			It("should return true for non-LoadBalancer Service", func() {
@@ -4485,7 +4565,7 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeTrue())
 				Expect(mockCtlr.initialResourceCount).To(Equal(3))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 			})
 
 			It("should decrement initialResourceCount for LoadBalancer Service", func() {
@@ -4498,7 +4578,7 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeTrue())
 				Expect(mockCtlr.initialResourceCount).To(Equal(2))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 			})
 
 			It("should return false for IngressLink when initialResourceCount reaches zero", func() {
@@ -4507,7 +4587,7 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeFalse())
 				Expect(mockCtlr.initialResourceCount).To(Equal(0))
-				Expect(mockCtlr.initState).To(BeFalse())
+				Expect(mockCtlr.initState.Load()).To(BeFalse())
 			})
 
 			It("should return false for namespace", func() {
@@ -4515,20 +4595,20 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeFalse())
 				Expect(mockCtlr.initialResourceCount).To(Equal(3))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 			})
 			It("should return true for policy", func() {
 				rKey := &rqKey{kind: CustomPolicy}
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeTrue())
 				Expect(mockCtlr.initialResourceCount).To(Equal(3))
-				Expect(mockCtlr.initState).To(BeTrue())
+				Expect(mockCtlr.initState.Load()).To(BeTrue())
 			})
 		})
 
 		Context("when initState is false", func() {
 			BeforeEach(func() {
-				mockCtlr.initState = false
+				mockCtlr.initState.Store(false)
 			})
 
 			It("should return false for any kind", func() {
@@ -4536,7 +4616,7 @@ var _ = Describe("CIS Init time test cases", func() {
 				result := mockCtlr.processKeyAtInitTime(rKey)
 				Expect(result).To(BeFalse())
 				Expect(mockCtlr.initialResourceCount).To(Equal(3))
-				Expect(mockCtlr.initState).To(BeFalse())
+				Expect(mockCtlr.initState.Load()).To(BeFalse())
 			})
 		})
 	})
@@ -4554,7 +4634,7 @@ var _ = Describe("Process Resources additional tests", func() {
 		mockCtlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
 			workqueue.DefaultControllerRateLimiter(), "custom-resource-controller")
 		// setting init state to avoid processing resources
-		mockCtlr.initState = true
+		mockCtlr.initState.Store(true)
 		mockCtlr.initialResourceCount = 1
 	})
 	AfterEach(func() {
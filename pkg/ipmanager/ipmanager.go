@@ -1,6 +1,7 @@
 package ipmanager
 
 import (
+	"fmt"
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 	"github.com/F5Networks/f5-ipam-controller/pkg/ipammachinery"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
@@ -9,8 +10,10 @@ import (
 	"k8s.io/client-go/rest"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unicode"
 )
 
@@ -27,6 +30,9 @@ type (
 		FirstPostResponse bool
 		// key is IPSec.Key and value is map of ResouceInfo
 		IpamResourceStore map[ficV1.HostSpec]map[ResourceRef]struct{}
+		// standby replicas don't write the IPAM CR shared by the replicas, the host specs they request are
+		// cached and requested once the replica becomes the leader
+		standby atomic.Bool
 	}
 	ipamCache struct {
 		// key is hostSpec and value is assigned ip address
@@ -38,6 +44,12 @@ type (
 		Name      string
 		Namespace string
 	}
+	// IPAMCacheEntry is a host spec of the IPAM cache with its allocated ip address and the resources using it
+	IPAMCacheEntry struct {
+		HostSpec  ficV1.HostSpec `json:"hostSpec"`
+		IPAddress string         `json:"ipAddress"`
+		Resources []ResourceRef  `json:"resources,omitempty"`
+	}
 )
 
 const (
@@ -122,6 +134,10 @@ func (h *IPAMHandler) CreateIPAMResource() error {
 		},
 	}
 	h.IPAMCR = h.IPAMCRNamespace + "/" + crName
+	if h.standby.Load() {
+		log.Debugf("[IPAM] Skipping the creation of IPAM Custom Resource %v on the standby replica", h.IPAMCR)
+		return nil
+	}
 
 	ipamCR, err := h.IpamCli.Create(f5ipam)
 	if err == nil {
@@ -186,6 +202,12 @@ func (h *IPAMHandler) RequestIP(ipamLabel string, host string, key string, ref R
 
 	ipamCR := h.GetIPAMCR()
 	if ipamCR == nil {
+		if h.standby.Load() {
+			// the IPAM CR isn't created by the leader yet
+			h.UpdateResourceRef(hostSpec, ref)
+			h.AddHostSpec(hostSpec)
+			return "", Requested
+		}
 		return "", NotEnabled
 	}
 
@@ -234,6 +256,10 @@ func (h *IPAMHandler) RequestIP(ipamLabel string, host string, key string, ref R
 
 	// update the cache
 	h.AddHostSpec(hostSpec)
+	if h.standby.Load() {
+		log.Debugf("[IPAM] Host spec %v will be requested once the replica becomes the leader", hostSpec)
+		return "", Requested
+	}
 	ipamCR.SetResourceVersion(ipamCR.ResourceVersion)
 	ipamCR.Spec.HostSpecs = append(ipamCR.Spec.HostSpecs, &hostSpec)
 
@@ -249,9 +275,8 @@ func (h *IPAMHandler) RequestIP(ipamLabel string, host string, key string, ref R
 }
 
 func (h *IPAMHandler) ReleaseIP(ipamLabel string, host string, key string, ref ResourceRef) string {
-	ipamCR := h.GetIPAMCR()
 	var ip string
-	if ipamCR == nil || ipamLabel == "" {
+	if ipamLabel == "" {
 		return ip
 	}
 	hostSpec := ficV1.HostSpec{
@@ -259,6 +284,14 @@ func (h *IPAMHandler) ReleaseIP(ipamLabel string, host string, key string, ref R
 		Host:      host,
 		IPAMLabel: ipamLabel,
 	}
+	ipamCR := h.GetIPAMCR()
+	if ipamCR == nil {
+		if h.standby.Load() {
+			h.RemoveResourceRef(hostSpec, ref)
+			h.removePendingHostSpec(hostSpec)
+		}
+		return ip
+	}
 	// Remove the resource ref
 	h.RemoveResourceRef(hostSpec, ref)
 	index := -1
@@ -277,6 +310,11 @@ func (h *IPAMHandler) ReleaseIP(ipamLabel string, host string, key string, ref R
 			break
 		}
 	}
+	if h.standby.Load() {
+		// the leader releases the host spec
+		h.removePendingHostSpec(hostSpec)
+		return ip
+	}
 	if index != -1 {
 		_, err := h.RemoveIPAMCRHostSpec(ipamCR, hostSpec, index)
 		if err != nil {
@@ -301,7 +339,7 @@ func (h *IPAMHandler) RemoveIPAMCRHostSpec(ipamCR *ficV1.IPAM, key ficV1.HostSpe
 }
 
 func (h *IPAMHandler) RemoveUnusedIPAMEntries() {
-	if !h.FirstPostResponse {
+	if !h.FirstPostResponse && !h.standby.Load() {
 		h.FirstPostResponse = true
 		// Remove Unused IPAM entries in IPAM CR after CIS restarts, applicable to only first PostCall
 		cisUsedIPAM := &ficV1.IPAM{
@@ -329,6 +367,58 @@ func (h *IPAMHandler) RemoveUnusedIPAMEntries() {
 	}
 }
 
+// removePendingHostSpec removes the host spec without the resources from the cache, so that the replica doesn't
+// request it once it becomes the leader
+func (h *IPAMHandler) removePendingHostSpec(hostSpec ficV1.HostSpec) {
+	if _, ok := h.IpamResourceStore[hostSpec]; !ok {
+		h.ipamCache.Lock()
+		delete(h.ipamCache.ipamCacheMap, hostSpec)
+		h.ipamCache.Unlock()
+	}
+}
+
+// SetStandby enables or disables the updates of the IPAM CR based on the leadership of the replica
+func (h *IPAMHandler) SetStandby(standby bool) {
+	h.standby.Store(standby)
+}
+
+// RequestPendingIPs requests the host specs cached by the replica as standby, it's called once the replica becomes
+// the leader. The resources of the host specs are processed on the IP allocation
+func (h *IPAMHandler) RequestPendingIPs() {
+	if h.standby.Load() {
+		return
+	}
+	ipamCR := h.GetIPAMCR()
+	if ipamCR == nil {
+		return
+	}
+	requested := make(map[ficV1.HostSpec]struct{})
+	for _, hostSpec := range ipamCR.Spec.HostSpecs {
+		requested[*hostSpec] = struct{}{}
+	}
+	var pending []ficV1.HostSpec
+	h.ipamCache.RLock()
+	for hostSpec, ip := range h.ipamCache.ipamCacheMap {
+		if _, ok := requested[hostSpec]; !ok && ip == "" {
+			pending = append(pending, hostSpec)
+		}
+	}
+	h.ipamCache.RUnlock()
+	if len(pending) == 0 {
+		return
+	}
+	sort.Slice(pending, func(i, j int) bool { return fmt.Sprint(pending[i]) < fmt.Sprint(pending[j]) })
+	for i := range pending {
+		ipamCR.Spec.HostSpecs = append(ipamCR.Spec.HostSpecs, &pending[i])
+	}
+	ipamCR.SetResourceVersion(ipamCR.ResourceVersion)
+	if _, err := h.IpamCli.Update(ipamCR); err != nil {
+		log.Errorf("[IPAM] Error updating IPAM CR : %v", err)
+		return
+	}
+	log.Debugf("[IPAM] Requested %v host specs of the standby replica", len(pending))
+}
+
 // GetIpAddressForHostSpec function to check if fic ipSpec exists in the ipam context
 func (h *IPAMHandler) GetIpAddressForHostSpec(key ficV1.HostSpec) (string, bool) {
 	h.ipamCache.RLock()
@@ -337,6 +427,29 @@ func (h *IPAMHandler) GetIpAddressForHostSpec(key ficV1.HostSpec) (string, bool)
 	return ipAdd, exists
 }
 
+// GetIPAMCacheEntries returns the entries of the IPAM cache sorted by the host spec, IpamResourceStore isn't
+// guarded by a lock so the caller shouldn't run concurrently with the resource updates
+func (h *IPAMHandler) GetIPAMCacheEntries() []IPAMCacheEntry {
+	h.ipamCache.RLock()
+	entries := make([]IPAMCacheEntry, 0, len(h.ipamCache.ipamCacheMap))
+	for hostSpec, ip := range h.ipamCache.ipamCacheMap {
+		entries = append(entries, IPAMCacheEntry{HostSpec: hostSpec, IPAddress: ip})
+	}
+	h.ipamCache.RUnlock()
+	for i := range entries {
+		for ref := range h.IpamResourceStore[entries[i].HostSpec] {
+			entries[i].Resources = append(entries[i].Resources, ref)
+		}
+		sort.Slice(entries[i].Resources, func(a, b int) bool {
+			return fmt.Sprint(entries[i].Resources[a]) < fmt.Sprint(entries[i].Resources[b])
+		})
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return fmt.Sprint(entries[a].HostSpec) < fmt.Sprint(entries[b].HostSpec)
+	})
+	return entries
+}
+
 // AddHostSpec function to add ipSpec to the ipam context
 func (h *IPAMHandler) AddHostSpec(key ficV1.HostSpec) {
 	h.ipamCache.Lock()
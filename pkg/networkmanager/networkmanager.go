@@ -2,8 +2,6 @@ package networkmanager
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
@@ -46,6 +44,11 @@ type (
 		NetworkChan      chan *NetworkConfigRequest
 		httpClient       *http.Client
 		DefaultL3Network string
+		// bigIpTokenManagers holds the token managers of the bigips managed by the additional central managers
+		bigIpTokenManagers map[string]*tokenmanager.TokenManager
+		// instanceTokenManagers holds the token managers of the instances managed by the additional central managers
+		instanceTokenManagers map[string]*tokenmanager.TokenManager
+		tmLock                sync.RWMutex
 	}
 
 	BigIP struct {
@@ -90,27 +93,9 @@ type (
 )
 
 func NewNetworkManager(tm *tokenmanager.TokenManager, clusterName string) *NetworkManager {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(tm.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debugf("%v No certs appended, using only system certs", networkManagerPrefix)
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: tm.SslInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
+	// transport of the token manager picks up the rotated trusted certificates
 	httpClient := &http.Client{
-		Transport: tr,
+		Transport: tm.Transport(),
 		Timeout:   timeoutLarge,
 	}
 	routeStore := L3ForwardStore{
@@ -128,20 +113,66 @@ func NewNetworkManager(tm *tokenmanager.TokenManager, clusterName string) *Netwo
 		NetworkChan:      make(chan *NetworkConfigRequest, 1),
 		httpClient:       httpClient,
 		DefaultL3Network: defaultL3Network,
+
+		bigIpTokenManagers:    make(map[string]*tokenmanager.TokenManager),
+		instanceTokenManagers: make(map[string]*tokenmanager.TokenManager),
+	}
+}
+
+// SetTokenManager sets the token manager of the central manager managing the bigip, nil resets it to the default one
+func (nm *NetworkManager) SetTokenManager(bigIpAddress string, tm *tokenmanager.TokenManager) {
+	nm.tmLock.Lock()
+	defer nm.tmLock.Unlock()
+	if tm == nil || tm == nm.CMTokenManager {
+		delete(nm.bigIpTokenManagers, bigIpAddress)
+		return
+	}
+	nm.bigIpTokenManagers[bigIpAddress] = tm
+}
+
+// getBigIpTokenManager returns the token manager of the central manager managing the bigip
+func (nm *NetworkManager) getBigIpTokenManager(bigIpAddress string) *tokenmanager.TokenManager {
+	nm.tmLock.RLock()
+	defer nm.tmLock.RUnlock()
+	if tm, ok := nm.bigIpTokenManagers[bigIpAddress]; ok {
+		return tm
+	}
+	return nm.CMTokenManager
+}
+
+// getTokenManager returns the token manager of the central manager managing the instance
+func (nm *NetworkManager) getTokenManager(instanceId string) *tokenmanager.TokenManager {
+	nm.tmLock.RLock()
+	defer nm.tmLock.RUnlock()
+	if tm, ok := nm.instanceTokenManagers[instanceId]; ok {
+		return tm
+	}
+	return nm.CMTokenManager
+}
+
+// getHttpClient returns the http client for the central manager
+func (nm *NetworkManager) getHttpClient(tm *tokenmanager.TokenManager) *http.Client {
+	if tm == nm.CMTokenManager {
+		return nm.httpClient
+	}
+	return &http.Client{
+		Transport: tm.Transport(),
+		Timeout:   nm.httpClient.Timeout,
 	}
 }
 
 func getDefaultL3Network(tm *tokenmanager.TokenManager) string {
-	if tm.CMVersion != "" {
-		verLst := strings.Split(tm.CMVersion, ".")
+	cmVersion := tm.GetCachedCMVersion()
+	if cmVersion != "" {
+		verLst := strings.Split(cmVersion, ".")
 		if len(verLst) == 3 {
 			v1, err1 := strconv.ParseFloat(verLst[0]+"."+verLst[1], 64)
 			v2, err2 := strconv.Atoi(verLst[2])
 			if err1 != nil {
-				log.Errorf("error parsing float CM version: %v, error: %v", tm.CMVersion, err1)
+				log.Errorf("error parsing float CM version: %v, error: %v", cmVersion, err1)
 			}
 			if err2 != nil {
-				log.Errorf("error parsing int CM version: %v, error: %v", tm.CMVersion, err2)
+				log.Errorf("error parsing int CM version: %v, error: %v", cmVersion, err2)
 			}
 			if err1 == nil && err2 == nil {
 				if v1 < 20.2 || (v1 == 20.2 && v2 < 1) {
@@ -154,16 +185,17 @@ func getDefaultL3Network(tm *tokenmanager.TokenManager) string {
 }
 
 func getTaskApi(tm *tokenmanager.TokenManager) string {
-	if tm.CMVersion != "" {
-		verLst := strings.Split(tm.CMVersion, ".")
+	cmVersion := tm.GetCachedCMVersion()
+	if cmVersion != "" {
+		verLst := strings.Split(cmVersion, ".")
 		if len(verLst) == 3 {
 			v1, err1 := strconv.ParseFloat(verLst[0]+"."+verLst[1], 64)
 			v2, err2 := strconv.Atoi(verLst[2])
 			if err1 != nil {
-				log.Errorf("error parsing float CM version: %v, error: %v", tm.CMVersion, err1)
+				log.Errorf("error parsing float CM version: %v, error: %v", cmVersion, err1)
 			}
 			if err2 != nil {
-				log.Errorf("error parsing int CM version: %v, error: %v", tm.CMVersion, err2)
+				log.Errorf("error parsing int CM version: %v, error: %v", cmVersion, err2)
 			}
 			if err1 == nil && err2 == nil {
 				if v1 < 20.2 || (v1 == 20.2 && v2 < 1) {
@@ -195,17 +227,39 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 	}
 	nm.L3ForwardStore.Unlock()
 
+	// group the monitored bigips by the central manager managing them
+	cmBigIps := make(map[*tokenmanager.TokenManager]map[string]struct{})
+	for bigIpAddress := range monitoredBigIps {
+		tm := nm.getBigIpTokenManager(bigIpAddress)
+		if _, ok := cmBigIps[tm]; !ok {
+			cmBigIps[tm] = make(map[string]struct{})
+		}
+		cmBigIps[tm][bigIpAddress] = struct{}{}
+	}
+	nm.tmLock.Lock()
+	nm.instanceTokenManagers = make(map[string]*tokenmanager.TokenManager)
+	nm.tmLock.Unlock()
+	for tm, bigIps := range cmBigIps {
+		if err := nm.setInstanceIdsFromCM(tm, bigIps, controllerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setInstanceIdsFromCM fetches the inventory of the central manager and stores the instance ids of the monitored bigips
+func (nm *NetworkManager) setInstanceIdsFromCM(tm *tokenmanager.TokenManager, monitoredBigIps map[string]struct{}, controllerID string) error {
 	// Create request
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+InventoryURI, nil)
+	req, err := http.NewRequest("GET", tm.ServerURL+InventoryURI, nil)
 	if err != nil {
 		return err
 	}
 
 	// Set authorization header
-	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetAccessToken())
+	tm.SetAuthorizationHeader(req)
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.getHttpClient(tm).Do(req)
 	if err != nil {
 		return err
 	}
@@ -232,6 +286,11 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 						// Add if the bigip is monitored
 						if _, ok := monitoredBigIps[address]; ok {
 							nm.DeviceMap[address] = id
+							if tm != nm.CMTokenManager {
+								nm.tmLock.Lock()
+								nm.instanceTokenManagers[id] = tm
+								nm.tmLock.Unlock()
+							}
 							nm.L3ForwardStore.Lock()
 							if _, ok := nm.L3ForwardStore.InstanceStaticRoutes[id]; !ok {
 								staticRouteMap, err := nm.GetL3ForwardsFromInstance(id, controllerID)
@@ -256,17 +315,19 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 // GetL3ForwardsFromInstance performs an HTTP GET request to the API, extracts name and route information, and stores them
 func (nm *NetworkManager) GetL3ForwardsFromInstance(instanceId string, controllerID string) (StaticRouteMap, error) {
 
+	tm := nm.getTokenManager(instanceId)
+
 	// Create request
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+InstancesURI+instanceId+L3Forwards, nil)
+	req, err := http.NewRequest("GET", tm.ServerURL+InstancesURI+instanceId+L3Forwards, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// Set authorization header
-	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetAccessToken())
+	tm.SetAuthorizationHeader(req)
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.getHttpClient(tm).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -326,8 +387,10 @@ func (nm *NetworkManager) GetL3ForwardsFromInstance(instanceId string, controlle
 // DeleteL3Forward sends an HTTP DELETE request to delete an L3Forward with the given ID
 func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error {
 
+	tm := nm.getTokenManager(instanceId)
+
 	// Create request URL
-	url := fmt.Sprintf("%s/%s", nm.CMTokenManager.ServerURL+InstancesURI+instanceId+L3Forwards, l3ForwardID)
+	url := fmt.Sprintf("%s/%s", tm.ServerURL+InstancesURI+instanceId+L3Forwards, l3ForwardID)
 
 	// Create request
 	req, err := http.NewRequest("DELETE", url, nil)
@@ -336,10 +399,10 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 	}
 
 	// Set authorization header
-	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetAccessToken())
+	tm.SetAuthorizationHeader(req)
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.getHttpClient(tm).Do(req)
 	if err != nil {
 		return err
 	}
@@ -363,7 +426,7 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 	var taskStatus, failureReason string
 	for {
 		time.Sleep(timeoutSmall)
-		taskStatus, failureReason, err = nm.GetTaskStatus(taskRef)
+		taskStatus, failureReason, err = nm.getTaskStatus(tm, taskRef)
 		if err != nil || taskStatus == Completed || taskStatus == Failed {
 			break
 		}
@@ -379,18 +442,23 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 
 // GetTaskStatus sends an HTTP GET request to get the task status of the given task ID
 func (nm *NetworkManager) GetTaskStatus(taskRef string) (string, string, error) {
+	return nm.getTaskStatus(nm.CMTokenManager, taskRef)
+}
+
+// getTaskStatus gets the task status of the given task ID from the central manager
+func (nm *NetworkManager) getTaskStatus(tm *tokenmanager.TokenManager, taskRef string) (string, string, error) {
 
 	// Create request
-	taskApi := getTaskApi(nm.CMTokenManager)
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+taskApi+taskRef, nil)
+	taskApi := getTaskApi(tm)
+	req, err := http.NewRequest("GET", tm.ServerURL+taskApi+taskRef, nil)
 	if err != nil {
 		return "", "", err
 	}
 	// Set authorization header
-	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetAccessToken())
+	tm.SetAuthorizationHeader(req)
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.getHttpClient(tm).Do(req)
 	if err != nil {
 		return "", "", err
 	}
@@ -420,6 +488,11 @@ func (nm *NetworkManager) GetTaskStatus(taskRef string) (string, string, error)
 
 // PostL3Forward sends an HTTP POST request to create an L3Forward with the given data
 func (nm *NetworkManager) PostL3Forward(apiURL, authToken string, l3ForwardReq *L3Forward) error {
+	return nm.postL3Forward(nm.CMTokenManager, apiURL, authToken, l3ForwardReq)
+}
+
+// postL3Forward creates the L3Forward on the central manager
+func (nm *NetworkManager) postL3Forward(tm *tokenmanager.TokenManager, apiURL, authToken string, l3ForwardReq *L3Forward) error {
 	// Convert L3ForwardRequest to JSON
 	reqBody, err := json.Marshal(l3ForwardReq)
 	if err != nil {
@@ -435,11 +508,13 @@ func (nm *NetworkManager) PostL3Forward(apiURL, authToken string, l3ForwardReq *
 	// Set Content-Type header
 	req.Header.Set("Content-Type", "application/json")
 
-	// Set authorization header
-	req.Header.Set("Authorization", "Bearer "+authToken)
+	// Set authorization header, requests authenticated with client certificate don't carry a token
+	if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.getHttpClient(tm).Do(req)
 	if err != nil {
 		return err
 	}
@@ -465,7 +540,7 @@ func (nm *NetworkManager) PostL3Forward(apiURL, authToken string, l3ForwardReq *
 	var taskStatus, failureReason string
 	for {
 		time.Sleep(timeoutSmall)
-		taskStatus, failureReason, err = nm.GetTaskStatus(taskRef)
+		taskStatus, failureReason, err = nm.getTaskStatus(tm, taskRef)
 		if err != nil || taskStatus == Completed || taskStatus == Failed {
 			break
 		}
@@ -575,8 +650,9 @@ func (nm *NetworkManager) HandleL3ForwardRequest(req *NetworkConfigRequest, l3Fo
 			return
 		}
 
-		// create the l3 forward
-		err := nm.PostL3Forward(nm.CMTokenManager.ServerURL+InstancesURI+req.BigIp.InstanceId+L3Forwards, nm.CMTokenManager.GetAccessToken(), l3Forward)
+		// create the l3 forward on the central manager managing the instance
+		tm := nm.getTokenManager(req.BigIp.InstanceId)
+		err := nm.postL3Forward(tm, tm.ServerURL+InstancesURI+req.BigIp.InstanceId+L3Forwards, tm.GetAccessToken(), l3Forward)
 		if err != nil {
 			bigipStatus.L3Status = &cisapiv1.L3Status{
 				Message:       Create + Failed,
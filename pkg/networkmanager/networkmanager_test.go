@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	"net/http"
 	"time"
 )
 
@@ -583,3 +584,56 @@ var _ = Describe("getTaskApi", func() {
 		})
 	})
 })
+
+var _ = Describe("Multiple Central Managers", func() {
+	var (
+		defaultServer, server *ghttp.Server
+		networkManager        *NetworkManager
+		defaultTM, tm         *tokenmanager.TokenManager
+	)
+	const (
+		bigIPAddress = "10.10.20.10"
+		bigIpId      = "7a0b0e4a-3d58-4c58-9c5c-6bb0d4d3a8a1"
+	)
+
+	BeforeEach(func() {
+		mockStatusManager := mockmanager.NewMockStatusManager()
+		defaultServer = ghttp.NewServer()
+		server = ghttp.NewServer()
+		defaultTM = tokenmanager.NewTokenManager(defaultServer.URL(), tokenmanager.Credentials{}, "", true, mockStatusManager)
+		tm, _ = tokenmanager.NewTokenManagerWithAuth(server.URL(), tokenmanager.Credentials{},
+			tokenmanager.AuthConfig{Type: tokenmanager.AuthAPIToken, APIToken: "dc2.token"}, "", true, mockStatusManager)
+		tm.SyncTokenWithoutRetry()
+		networkManager = NewNetworkManager(defaultTM, "cluster-1")
+	})
+
+	AfterEach(func() {
+		defaultServer.Close()
+		server.Close()
+		close(networkManager.NetworkChan)
+	})
+
+	It("should fetch the instances from the central manager managing the bigip", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InventoryURI),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer dc2.token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, stringToJson(fmt.Sprintf(
+					`{"_embedded": {"devices": [{"address": "%s", "id": "%s"}]}}`, bigIPAddress, bigIpId)))),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InstancesURI+bigIpId+L3Forwards),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, stringToJson(`{"_embedded": {"l3forwards": []}}`))),
+		)
+		networkManager.SetTokenManager(bigIPAddress, tm)
+		Expect(networkManager.getBigIpTokenManager(bigIPAddress)).To(Equal(tm))
+		Expect(networkManager.SetInstanceIds([]cisapiv1.BigIpConfig{{BigIpAddress: bigIPAddress}}, "cluster-1")).To(Succeed())
+		Expect(defaultServer.ReceivedRequests()).To(BeEmpty())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+		Expect(networkManager.DeviceMap[bigIPAddress]).To(Equal(bigIpId))
+		Expect(networkManager.getTokenManager(bigIpId)).To(Equal(tm))
+
+		// reset to the default central manager
+		networkManager.SetTokenManager(bigIPAddress, nil)
+		Expect(networkManager.getBigIpTokenManager(bigIPAddress)).To(Equal(defaultTM))
+	})
+})
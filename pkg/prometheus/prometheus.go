@@ -4,6 +4,7 @@ import (
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sync"
 )
 
 var ManagedServices = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -24,6 +25,35 @@ var ConfigurationWarnings = prometheus.NewGaugeVec(
 	[]string{"kind", "namespace", "name", "warning"},
 )
 
+// configurationWarnings holds the warnings of each resource, so that the series of the resolved warnings are removed
+var configurationWarnings = struct {
+	sync.Mutex
+	warnings map[[3]string]map[string]struct{}
+}{warnings: make(map[[3]string]map[string]struct{})}
+
+// AddConfigurationWarning sets the configuration warning of the resource
+func AddConfigurationWarning(kind, namespace, name, warning string) {
+	configurationWarnings.Lock()
+	defer configurationWarnings.Unlock()
+	key := [3]string{kind, namespace, name}
+	if _, ok := configurationWarnings.warnings[key]; !ok {
+		configurationWarnings.warnings[key] = make(map[string]struct{})
+	}
+	configurationWarnings.warnings[key][warning] = struct{}{}
+	ConfigurationWarnings.WithLabelValues(kind, namespace, name, warning).Set(1)
+}
+
+// ClearConfigurationWarnings removes the configuration warnings of the resource once it is valid or deleted
+func ClearConfigurationWarnings(kind, namespace, name string) {
+	configurationWarnings.Lock()
+	defer configurationWarnings.Unlock()
+	key := [3]string{kind, namespace, name}
+	for warning := range configurationWarnings.warnings[key] {
+		ConfigurationWarnings.DeleteLabelValues(kind, namespace, name, warning)
+	}
+	delete(configurationWarnings.warnings, key)
+}
+
 var AgentCount = prometheus.NewGauge(prometheus.GaugeOpts{
 	Name: "k8s_bigip_ctlr_managed_bigips",
 	Help: "The total number of bigips where the CIS Controller posts the declaration.",
@@ -37,6 +67,88 @@ var MonitoredNodes = prometheus.NewGaugeVec(
 	[]string{"nodeselector"},
 )
 
+// Outcomes of the tenant posts
+const (
+	PostSuccess = "success"
+	PostFailure = "failure"
+)
+
+// Allocation states of the IPAM addresses
+const (
+	IPAMAllocated = "allocated"
+	IPAMPending   = "pending"
+	IPAMFailed    = "failed"
+)
+
+var TenantPostDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_tenant_post_duration_seconds",
+		Help:    "Duration of posting the tenant declaration till the tenant is processed.",
+		Buckets: []float64{.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	},
+	[]string{"bigip", "tenant"},
+)
+
+var TenantPosts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_tenant_posts_total",
+		Help: "The total number of tenant declarations posted by the CIS Controller.",
+	},
+	[]string{"bigip", "tenant", "outcome"},
+)
+
+var AS3TaskPollDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_as3_task_poll_duration_seconds",
+		Help:    "Duration of polling the accepted AS3 task till it is completed.",
+		Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	},
+	[]string{"bigip"},
+)
+
+var LastSuccessfulPost = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "k8s_bigip_ctlr_last_successful_post_timestamp_seconds",
+		Help: "Unix timestamp of the last post with all the tenants processed successfully.",
+	},
+	[]string{"bigip"},
+)
+
+var ResourceQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "k8s_bigip_ctlr_resource_queue_depth",
+	Help: "The number of resources waiting in the queue to be processed by the CIS Controller.",
+})
+
+var ResourceProcessingDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_resource_processing_duration_seconds",
+		Help:    "Duration of processing the resource by the CIS Controller.",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"kind"},
+)
+
+var PoolMembers = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "k8s_bigip_ctlr_pool_members",
+		Help: "The number of members in the pool.",
+	},
+	[]string{"bigip", "partition", "pool"},
+)
+
+var IPAMAllocations = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "k8s_bigip_ctlr_ipam_allocations",
+		Help: "The number of IP addresses requested from IPAM controller by allocation state.",
+	},
+	[]string{"state"},
+)
+
+var StaticRoutes = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "k8s_bigip_ctlr_static_routes",
+	Help: "The number of static routes configured by the CIS Controller.",
+})
+
 var ClientInFlightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
 	Name: "k8s_bigip_ctlr_http_client_in_flight_requests",
 	Help: "Total count of in-flight requests for the wrapped http client.",
@@ -103,6 +215,15 @@ func RegisterMetrics(httpClientMetrics bool, cmAddress string) {
 			ConfigurationWarnings,
 			AgentCount,
 			MonitoredNodes,
+			TenantPostDuration,
+			TenantPosts,
+			AS3TaskPollDuration,
+			LastSuccessfulPost,
+			ResourceQueueDepth,
+			ResourceProcessingDuration,
+			PoolMembers,
+			IPAMAllocations,
+			StaticRoutes,
 			ClientInFlightGauge,
 			ClientAPIRequestsCounter,
 			ClientDNSLatencyVec,
@@ -116,6 +237,15 @@ func RegisterMetrics(httpClientMetrics bool, cmAddress string) {
 			ConfigurationWarnings,
 			AgentCount,
 			MonitoredNodes,
+			TenantPostDuration,
+			TenantPosts,
+			AS3TaskPollDuration,
+			LastSuccessfulPost,
+			ResourceQueueDepth,
+			ResourceProcessingDuration,
+			PoolMembers,
+			IPAMAllocations,
+			StaticRoutes,
 		)
 	}
 }
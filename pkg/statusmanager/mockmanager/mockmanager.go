@@ -42,3 +42,7 @@ func (sm *MockStatusManager) updateDeployConfigStatus(req *StatusRequest) {
 func (sm *MockStatusManager) AddDeployInformer(informer *cache.SharedIndexInformer, namespace string) {
 
 }
+
+func (sm *MockStatusManager) SetStandby(standby bool) {
+
+}
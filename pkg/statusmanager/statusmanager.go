@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	v1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned"
@@ -18,6 +20,18 @@ type (
 		kubeCRClient         *versioned.Interface
 		Status               chan *StatusRequest
 		deployConfigResource DeployConfigResource
+		// standby replicas don't write the status, only the leader does
+		standby atomic.Bool
+		// pending holds the latest status requests received while standby, written once the replica leads
+		pending     []pendingRequest
+		pendingLock sync.Mutex
+		// updates tracks the status updates in progress, Stop waits for them to be written
+		updates sync.WaitGroup
+		// stopped is closed once the status requests are consumed
+		stopped chan struct{}
+		// stopLock guards the status channel from the requests added after it's closed
+		stopLock sync.RWMutex
+		closed   bool
 	}
 	StatusRequest struct {
 		Kind      string
@@ -26,6 +40,10 @@ type (
 		Request   interface{}
 		Exit      bool
 	}
+	pendingRequest struct {
+		key string
+		req *StatusRequest
+	}
 	DeployConfigResource struct {
 		deployConfigInformer *cache.SharedIndexInformer
 		name                 string
@@ -42,6 +60,7 @@ type StatusManagerInterface interface {
 	GetDeployConfigCR(name, namespace string) *v1.DeployConfig
 	updateDeployConfigStatus(req *StatusRequest)
 	AddDeployInformer(informer *cache.SharedIndexInformer, namespace string)
+	SetStandby(standby bool)
 }
 
 const (
@@ -56,6 +75,7 @@ func NewStatusManager(kubeCRClient *versioned.Interface, crNamespace, crName str
 	return &StatusManager{
 		kubeCRClient: kubeCRClient,
 		Status:       make(chan *StatusRequest),
+		stopped:      make(chan struct{}),
 		deployConfigResource: DeployConfigResource{
 			name:      crName,
 			namespace: crNamespace,
@@ -65,19 +85,106 @@ func NewStatusManager(kubeCRClient *versioned.Interface, crNamespace, crName str
 
 // Start the StatusManager
 func (sm *StatusManager) Start() {
+	defer close(sm.stopped)
 	for req := range sm.Status {
-		switch req.Kind {
-		case DeployConfig:
-			go sm.updateDeployConfigStatus(req)
-		default:
-			log.Errorf("Unknown request kind: %s", req.Kind)
+		sm.pendingLock.Lock()
+		if sm.standby.Load() {
+			log.Debugf("Deferring the %v status update on the standby replica", req.Kind)
+			sm.addPending(req)
+			sm.pendingLock.Unlock()
+			continue
+		}
+		sm.pendingLock.Unlock()
+		sm.processRequest(req)
+	}
+}
+
+func (sm *StatusManager) processRequest(req *StatusRequest) {
+	switch req.Kind {
+	case DeployConfig:
+		sm.updates.Add(1)
+		go func(req *StatusRequest) {
+			defer sm.updates.Done()
+			sm.updateDeployConfigStatus(req)
+		}(req)
+	default:
+		log.Errorf("Unknown request kind: %s", req.Kind)
+	}
+}
+
+// SetStandby enables or disables the status updates based on the leadership of the replica,
+// the status updates deferred while standby are written once the replica leads
+func (sm *StatusManager) SetStandby(standby bool) {
+	sm.pendingLock.Lock()
+	sm.standby.Store(standby)
+	var pending []pendingRequest
+	if !standby {
+		pending = sm.pending
+		sm.pending = nil
+	}
+	sm.pendingLock.Unlock()
+	if len(pending) > 0 {
+		log.Debugf("Writing %v status updates deferred on the standby replica", len(pending))
+		sm.updates.Add(1)
+		go func() {
+			defer sm.updates.Done()
+			// write them in order so that the later requests for a BigIP override the earlier ones
+			for _, p := range pending {
+				sm.updateDeployConfigStatus(p.req)
+			}
+		}()
+	}
+}
+
+// addPending keeps the latest request per status field, pendingLock must be held
+func (sm *StatusManager) addPending(req *StatusRequest) {
+	if req.Kind != DeployConfig {
+		log.Errorf("Unknown request kind: %s", req.Kind)
+		return
+	}
+	key := pendingKey(req.Request)
+	pending := sm.pending[:0]
+	for _, p := range sm.pending {
+		// a request for the whole BigIP overrides its earlier AS3 and L3 requests
+		if p.key == key || strings.HasPrefix(p.key, key+"/") {
+			continue
+		}
+		pending = append(pending, p)
+	}
+	sm.pending = append(pending, pendingRequest{key: key, req: req})
+}
+
+// pendingKey returns the status field updated by the request
+func pendingKey(request interface{}) string {
+	switch status := request.(type) {
+	case *v1.CMStatus:
+		return "cm/" + status.URL
+	case *v1.BigIPStatus:
+		key := "bigip/" + status.BigIPAddress
+		if status.AS3Status != nil && status.L3Status == nil {
+			return key + "/as3"
 		}
+		if status.L3Status != nil && status.AS3Status == nil {
+			return key + "/l3"
+		}
+		return key
+	default:
+		return fmt.Sprintf("%T", request)
 	}
 }
 
-// Stop the StatusManager
+// Stop the StatusManager, waits for the pending status updates to be written
 func (sm *StatusManager) Stop() {
+	sm.stopLock.Lock()
+	if sm.closed {
+		sm.stopLock.Unlock()
+		return
+	}
+	sm.closed = true
 	close(sm.Status)
+	sm.stopLock.Unlock()
+	<-sm.stopped
+	sm.updates.Wait()
 }
 
 func (sm *StatusManager) AddRequest(kind, namespace, name string, exit bool, request interface{}) {
@@ -87,6 +194,12 @@ func (sm *StatusManager) AddRequest(kind, namespace, name string, exit bool, req
 		namespace = sm.deployConfigResource.namespace
 		sm.deployConfigResource.RUnlock()
 	}
+	sm.stopLock.RLock()
+	defer sm.stopLock.RUnlock()
+	if sm.closed {
+		log.Debugf("Skipping the %v status update, status manager is stopped", kind)
+		return
+	}
 	sm.Status <- &StatusRequest{
 		Kind:      kind,
 		Name:      name,
@@ -144,7 +257,13 @@ func (sm *StatusManager) updateDeployConfigStatus(req *StatusRequest) {
 	case *v1.CMStatus:
 		// Handle CMStatus
 		log.Debugf("updating CMStatus in DeployConfig CR for request: %v", req.Request)
-		configCR.Status.CMStatus = req.Request.(*v1.CMStatus)
+		cmStatus := req.Request.(*v1.CMStatus)
+		if cmStatus.URL != "" {
+			// status of the additional central managers referenced in the bigip config
+			sm.updateCMStatuses(configCR, cmStatus)
+			break
+		}
+		configCR.Status.CMStatus = cmStatus
 		if req.Exit {
 			exit = true
 			exitErr = fmt.Errorf("%v", configCR.Status.CMStatus.Error)
@@ -289,6 +408,23 @@ func (sm *StatusManager) updateBigIPStatus(configCR *v1.DeployConfig, bigipStatu
 	}
 }
 
+// func to update the status of the additional central managers, status with empty message removes the entry
+func (sm *StatusManager) updateCMStatuses(configCR *v1.DeployConfig, cmStatus *v1.CMStatus) {
+	for index, status := range configCR.Status.CMStatuses {
+		if status.URL == cmStatus.URL {
+			if cmStatus.Message == "" {
+				configCR.Status.CMStatuses = append(configCR.Status.CMStatuses[:index], configCR.Status.CMStatuses[index+1:]...)
+			} else {
+				configCR.Status.CMStatuses[index] = *cmStatus
+			}
+			return
+		}
+	}
+	if cmStatus.Message != "" {
+		configCR.Status.CMStatuses = append(configCR.Status.CMStatuses, *cmStatus)
+	}
+}
+
 func (sm *StatusManager) AddDeployInformer(informer *cache.SharedIndexInformer, namespace string) {
 	sm.deployConfigResource.Lock()
 	defer sm.deployConfigResource.Unlock()
@@ -66,6 +66,79 @@ var _ = Describe("Status Manager Tests", func() {
 				Expect(cr.Status.ControllerStatus.LastUpdated).To(Equal(timeStamp), "Last updated time should be equal")
 			})
 
+			It("Skip the status updates on the standby replica", func() {
+				sm.SetStandby(true)
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.ControllerStatus{
+					Message:     Ok,
+					LastUpdated: metaV1.Now(),
+				})
+				time.Sleep(1 * time.Second)
+				cr := sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr).ToNot(BeNil(), "CR should not be nil")
+				Expect(cr.Status.ControllerStatus).To(BeNil(), "standby replica shouldn't update the status")
+			})
+
+			It("Write the latest status updates once the standby replica leads", func() {
+				sm.SetStandby(true)
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.ControllerStatus{
+					Message:     "error",
+					LastUpdated: metaV1.Now(),
+				})
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.CMStatus{
+					Message:     Ok,
+					LastUpdated: metaV1.Now(),
+				})
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.BigIPStatus{
+					BigIPAddress: bigIPAddress,
+					AS3Status:    &cisapiv1.AS3Status{Message: "error", LastSubmitted: metaV1.Now()},
+				})
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.BigIPStatus{
+					BigIPAddress: bigIPAddress,
+					AS3Status:    &cisapiv1.AS3Status{Message: Ok, LastSubmitted: metaV1.Now()},
+				})
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.ControllerStatus{
+					Message:     Ok,
+					LastUpdated: metaV1.Now(),
+				})
+				time.Sleep(1 * time.Second)
+				cr := sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr).ToNot(BeNil(), "CR should not be nil")
+				Expect(cr.Status.ControllerStatus).To(BeNil(), "standby replica shouldn't update the status")
+				sm.pendingLock.Lock()
+				Expect(sm.pending).To(HaveLen(3), "only the latest request per status should be kept")
+				sm.pendingLock.Unlock()
+
+				sm.SetStandby(false)
+				time.Sleep(1 * time.Second)
+				cr = sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr).ToNot(BeNil(), "CR should not be nil")
+				Expect(cr.Status.ControllerStatus).ToNot(BeNil(), "leader should write the deferred status")
+				Expect(cr.Status.ControllerStatus.Message).To(Equal(Ok), "Controller status should be Ok")
+				Expect(cr.Status.CMStatus).ToNot(BeNil(), "leader should write the deferred CM status")
+				Expect(cr.Status.CMStatus.Message).To(Equal(Ok), "CM status should be Ok")
+				Expect(cr.Status.BigIPStatus).To(HaveLen(1), "leader should write the deferred BigIP status")
+				Expect(cr.Status.BigIPStatus[0].AS3Status.Message).To(Equal(Ok), "AS3 status should be Ok")
+				sm.pendingLock.Lock()
+				Expect(sm.pending).To(BeEmpty(), "deferred requests should be cleared")
+				sm.pendingLock.Unlock()
+			})
+
+			It("Write the pending status updates on stop", func() {
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.ControllerStatus{
+					Message:     Ok,
+					LastUpdated: metaV1.Now(),
+				})
+				sm.Stop()
+				cr := sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr).ToNot(BeNil(), "CR should not be nil")
+				Expect(cr.Status.ControllerStatus).ToNot(BeNil(), "status should be written before stopping")
+				Expect(cr.Status.ControllerStatus.Message).To(Equal(Ok), "Controller status should be Ok")
+				// requests after stopping are skipped
+				Expect(func() {
+					sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.ControllerStatus{})
+				}).NotTo(Panic())
+			})
+
 			It("Update the CM status", func() {
 				// update the ok status
 				timeStamp := metaV1.Now()
@@ -94,6 +167,38 @@ var _ = Describe("Status Manager Tests", func() {
 				Expect(cr.Status.CMStatus.LastUpdated).To(Equal(timeStamp), "Last updated time should be equal")
 			})
 
+			It("Update the additional CM status", func() {
+				cmURL := "https://10.10.20.1"
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.CMStatus{
+					URL:         cmURL,
+					Message:     Ok,
+					LastUpdated: metaV1.Now(),
+				})
+				time.Sleep(1 * time.Second)
+				cr := sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr.Status.CMStatus).To(BeNil(), "CM status of CIS should not be updated")
+				Expect(cr.Status.CMStatuses).To(HaveLen(1), "Additional CM status should be added")
+				Expect(cr.Status.CMStatuses[0].Message).To(Equal(Ok), "Additional CM status should be Ok")
+
+				// update the error status
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.CMStatus{
+					URL:         cmURL,
+					Message:     "error",
+					Error:       "Error message",
+					LastUpdated: metaV1.Now(),
+				})
+				time.Sleep(1 * time.Second)
+				cr = sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr.Status.CMStatuses).To(HaveLen(1), "Additional CM status should be updated")
+				Expect(cr.Status.CMStatuses[0].Error).To(Equal("Error message"), "Incorrect error of additional cm status")
+
+				// remove the status
+				sm.AddRequest(DeployConfig, "sampleConfigCR", "default", false, &cisapiv1.CMStatus{URL: cmURL})
+				time.Sleep(1 * time.Second)
+				cr = sm.GetDeployConfigCR("sampleConfigCR", "default")
+				Expect(cr.Status.CMStatuses).To(BeEmpty(), "Additional CM status should be removed")
+			})
+
 			It("Update the BigIP AS3 status", func() {
 				// update the ok status
 				timeStamp := metaV1.Now()
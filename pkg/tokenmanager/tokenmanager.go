@@ -2,6 +2,7 @@ package tokenmanager
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -12,6 +13,9 @@ import (
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +31,22 @@ const (
 	TokenFetchFailed         = "Failed to fetch accessToken"
 	Ok                       = "OK"
 	RetryInterval            = time.Duration(10)
+	// TokenFileSyncInterval is the interval at which the token file is checked for changes
+	TokenFileSyncInterval = 10 * time.Second
+	// CredentialsSyncInterval is the interval at which the credentials directory is checked for changes
+	CredentialsSyncInterval = 10 * time.Second
+)
+
+// Supported Central Manager authentication types
+const (
+	// AuthBasic uses username/password login against the CM login API with a refresh token
+	AuthBasic = "basic"
+	// AuthClientCert uses a client certificate (mTLS) to authenticate every request
+	AuthClientCert = "client-cert"
+	// AuthAPIToken uses a long-lived API token as bearer token
+	AuthAPIToken = "api-token"
+	// AuthTokenFile uses a bearer token read from a file, the file is re-read on change
+	AuthTokenFile = "token-file"
 )
 
 // TokenManager is responsible for managing the authentication accessToken.
@@ -42,6 +62,56 @@ type TokenManager struct {
 	httpClient        *http.Client
 	CMVersion         string
 	StatusManager     statusmanager.StatusManagerInterface
+	authConfig        AuthConfig
+	clientCert        *tls.Certificate
+	tokenFileModTime  time.Time
+	transport         *reloadableTransport
+	// statusURL is set for the additional Central Managers, their status is reported per url and is not fatal
+	statusURL string
+}
+
+// reloadableTransport is a http.RoundTripper that delegates to a transport which is rebuilt
+// whenever the TLS configuration changes. In-flight requests complete on the previous transport.
+type reloadableTransport struct {
+	sync.RWMutex
+	transport *http.Transport
+}
+
+// RoundTrip executes the request with the current transport.
+func (rt *reloadableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.RLock()
+	tr := rt.transport
+	rt.RUnlock()
+	return tr.RoundTrip(req)
+}
+
+// set replaces the current transport and releases the idle connections of the previous one.
+func (rt *reloadableTransport) set(tr *http.Transport) {
+	rt.Lock()
+	old := rt.transport
+	rt.transport = tr
+	rt.Unlock()
+	if old != nil {
+		old.CloseIdleConnections()
+	}
+}
+
+// AuthConfig represents the authentication method used with the Central Manager.
+type AuthConfig struct {
+	// Type is one of basic, client-cert, api-token or token-file
+	Type string
+	// ClientCertFile and ClientKeyFile hold the PEM encoded client certificate and key for client-cert auth
+	ClientCertFile string
+	ClientKeyFile  string
+	// ClientCertData and ClientKeyData hold the PEM encoded client certificate and key, used instead of the files
+	ClientCertData []byte
+	ClientKeyData  []byte
+	// APIToken is the long-lived token for api-token auth
+	APIToken string
+	// TokenFile is the path of the file holding the token for token-file auth
+	TokenFile string
+	// CredentialsDir is the directory holding the credentials files, it is watched for changes
+	CredentialsDir string
 }
 
 // Credentials represent the username and password used for authentication.
@@ -64,14 +134,240 @@ type AccessTokenResponse struct {
 
 // NewTokenManager creates a new instance of TokenManager.
 func NewTokenManager(serverURL string, credentials Credentials, trustedCerts string, sslInsecure bool, statusManager statusmanager.StatusManagerInterface) *TokenManager {
-	return &TokenManager{
+	tm, err := NewTokenManagerWithAuth(serverURL, credentials, AuthConfig{Type: AuthBasic}, trustedCerts, sslInsecure, statusManager)
+	if err != nil {
+		log.Errorf("[Token Manager] Unable to configure the authentication of Central Manager %v: %v", serverURL, err)
+	}
+	return tm
+}
+
+// NewTokenManagerWithAuth creates a new instance of TokenManager which authenticates with the given AuthConfig.
+func NewTokenManagerWithAuth(serverURL string, credentials Credentials, authConfig AuthConfig, trustedCerts string,
+	sslInsecure bool, statusManager statusmanager.StatusManagerInterface) (*TokenManager, error) {
+	if authConfig.Type == "" {
+		authConfig.Type = AuthBasic
+	}
+	tm := &TokenManager{
 		ServerURL:     serverURL,
 		credentials:   credentials,
 		TrustedCerts:  trustedCerts,
 		SslInsecure:   sslInsecure,
 		StatusManager: statusManager,
-		httpClient:    getHttpClient(trustedCerts, sslInsecure),
+		authConfig:    authConfig,
+	}
+	var err error
+	switch authConfig.Type {
+	case AuthBasic:
+	case AuthClientCert:
+		err = tm.loadClientCertificate()
+	case AuthAPIToken:
+		if authConfig.APIToken == "" {
+			err = fmt.Errorf("api token is required for %v authentication", AuthAPIToken)
+		}
+	case AuthTokenFile:
+		if authConfig.TokenFile == "" {
+			err = fmt.Errorf("token file is required for %v authentication", AuthTokenFile)
+		}
+	default:
+		err = fmt.Errorf("unsupported Central Manager authentication type: %v", authConfig.Type)
+	}
+	tm.transport = &reloadableTransport{transport: &http.Transport{TLSClientConfig: tm.TLSConfig()}}
+	tm.httpClient = &http.Client{Transport: tm.transport}
+	return tm, err
+}
+
+// SetStatusURL marks the token manager as one of the additional Central Managers, its status is reported
+// with the given url in DeployConfig status and authentication failures do not stop the controller.
+func (tm *TokenManager) SetStatusURL(url string) {
+	tm.statusURL = url
+}
+
+// cmStatus returns the CM status to be reported in DeployConfig status.
+func (tm *TokenManager) cmStatus(message, err string) *cisapiv1.CMStatus {
+	return &cisapiv1.CMStatus{
+		URL:         tm.statusURL,
+		Message:     message,
+		Error:       err,
+		LastUpdated: metav1.Now(),
+	}
+}
+
+// Transport returns the http.RoundTripper to be used by the clients communicating with the Central Manager.
+// It picks up the trusted certificates and client certificate whenever they are reloaded.
+func (tm *TokenManager) Transport() http.RoundTripper {
+	if tm.transport == nil {
+		return &http.Transport{TLSClientConfig: tm.TLSConfig()}
+	}
+	return tm.transport
+}
+
+// reloadTransport rebuilds the transport with the current TLS configuration.
+func (tm *TokenManager) reloadTransport() {
+	if tm.transport != nil {
+		tm.transport.set(&http.Transport{TLSClientConfig: tm.TLSConfig()})
+	}
+}
+
+// UpdateTrustedCerts updates the trusted certificates, rebuilds the TLS configuration and forces a re-login.
+func (tm *TokenManager) UpdateTrustedCerts(trustedCerts string) {
+	tm.mu.Lock()
+	if tm.TrustedCerts == trustedCerts {
+		tm.mu.Unlock()
+		return
+	}
+	tm.TrustedCerts = trustedCerts
+	tm.mu.Unlock()
+	log.Infof("[Token Manager] Trusted certificates updated, reloading TLS configuration")
+	tm.reloadTransport()
+	tm.SyncToken()
+}
+
+// UpdateCredentials updates the username/password used for login and forces a re-login.
+func (tm *TokenManager) UpdateCredentials(credentials Credentials) {
+	tm.mu.Lock()
+	if tm.credentials == credentials {
+		tm.mu.Unlock()
+		return
+	}
+	tm.credentials = credentials
+	tm.mu.Unlock()
+	log.Infof("[Token Manager] Central Manager credentials updated, re-login to Central Manager")
+	tm.SyncToken()
+}
+
+// UpdateAuth updates the credentials, API token or client certificate read from the credentials secret of the
+// Central Manager and forces a re-login, the authentication type can't be changed.
+func (tm *TokenManager) UpdateAuth(credentials Credentials, authConfig AuthConfig) error {
+	if authConfig.Type != tm.authConfig.Type {
+		return fmt.Errorf("authentication type can't be changed from %v to %v", tm.authConfig.Type, authConfig.Type)
+	}
+	switch authConfig.Type {
+	case AuthBasic:
+		tm.UpdateCredentials(credentials)
+	case AuthAPIToken:
+		tm.mu.Lock()
+		if tm.authConfig.APIToken == authConfig.APIToken {
+			tm.mu.Unlock()
+			return nil
+		}
+		tm.authConfig.APIToken = authConfig.APIToken
+		tm.mu.Unlock()
+		log.Infof("[Token Manager] Central Manager api token updated")
+		tm.SyncToken()
+	case AuthClientCert:
+		cert, err := tls.X509KeyPair(authConfig.ClientCertData, authConfig.ClientKeyData)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tm.mu.Lock()
+		if tm.clientCert != nil && bytes.Equal(tm.clientCert.Certificate[0], cert.Certificate[0]) {
+			tm.mu.Unlock()
+			return nil
+		}
+		tm.clientCert = &cert
+		tm.mu.Unlock()
+		log.Infof("[Token Manager] Client certificate updated")
+		tm.reloadTransport()
+	}
+	return nil
+}
+
+// ReloadClientCertificate re-reads the client certificate and key, new connections use the reloaded certificate.
+func (tm *TokenManager) ReloadClientCertificate() error {
+	if err := tm.loadClientCertificate(); err != nil {
+		return err
+	}
+	log.Infof("[Token Manager] Client certificate reloaded")
+	tm.reloadTransport()
+	return nil
+}
+
+// TrustedCertsFromConfigMapData returns the trusted certificates from the data of the trusted certs configmap.
+func TrustedCertsFromConfigMapData(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	// sort the keys so that unchanged configmaps always result in the same certificates
+	sort.Strings(keys)
+	var certs string
+	for _, key := range keys {
+		certs += data[key] + "\n"
+	}
+	return certs
+}
+
+// GetAuthType returns the authentication type used with the Central Manager.
+func (tm *TokenManager) GetAuthType() string {
+	return tm.authConfig.Type
+}
+
+// loadClientCertificate reads the client certificate and key used for client-cert authentication.
+func (tm *TokenManager) loadClientCertificate() error {
+	var cert tls.Certificate
+	var err error
+	switch {
+	case len(tm.authConfig.ClientCertData) > 0 && len(tm.authConfig.ClientKeyData) > 0:
+		cert, err = tls.X509KeyPair(tm.authConfig.ClientCertData, tm.authConfig.ClientKeyData)
+	case tm.authConfig.ClientCertFile != "" && tm.authConfig.ClientKeyFile != "":
+		cert, err = tls.LoadX509KeyPair(tm.authConfig.ClientCertFile, tm.authConfig.ClientKeyFile)
+	default:
+		return fmt.Errorf("client certificate and key are required for %v authentication", AuthClientCert)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %v", err)
+	}
+	tm.mu.Lock()
+	tm.clientCert = &cert
+	tm.mu.Unlock()
+	return nil
+}
+
+// getClientCertificate returns the client certificate presented to the Central Manager during TLS handshake.
+func (tm *TokenManager) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.clientCert == nil {
+		// no certificate is sent to the server
+		return &tls.Certificate{}, nil
+	}
+	return tm.clientCert, nil
+}
+
+// TLSConfig returns the TLS configuration to be used by the http clients communicating with the Central Manager.
+func (tm *TokenManager) TLSConfig() *tls.Config {
+	// Configure CA certificates
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	tm.mu.Lock()
+	certs := []byte(tm.TrustedCerts)
+	tm.mu.Unlock()
+
+	// Append our certs to the system pool
+	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
+		log.Debug("[Token Manager] No certs appended, using only system certs")
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tm.SslInsecure,
+		RootCAs:            rootCAs,
+	}
+	if tm.authConfig.Type == AuthClientCert {
+		tlsConfig.GetClientCertificate = tm.getClientCertificate
 	}
+	return tlsConfig
+}
+
+// SetAuthorizationHeader adds the authorization header to the request based on the authentication type.
+// With client-cert authentication the request is authenticated by the TLS client certificate.
+func (tm *TokenManager) SetAuthorizationHeader(req *http.Request) {
+	if tm.authConfig.Type == AuthClientCert {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+tm.GetAccessToken())
 }
 
 // GetRefreshToken returns the current valid saved accessToken.
@@ -91,7 +387,8 @@ func (tm *TokenManager) SetRefreshToken(token string) {
 
 // GetAccessToken returns the current valid saved accessToken.
 func (tm *TokenManager) GetAccessToken() string {
-	if time.Now().After(tm.accessTokenExpiry) {
+	// only the tokens fetched with username/password login expire and need a refresh
+	if tm.authConfig.Type == AuthBasic && time.Now().After(tm.accessTokenExpiry) {
 		if err := tm.RefreshAccessToken(); err == nil {
 			log.Debugf("[Token Manager] Successfully refreshed accessToken from Central Manager")
 		} else {
@@ -112,31 +409,6 @@ func (tm *TokenManager) SetAccessToken(token string) {
 	tm.accessTokenExpiry = time.Now().Add(CMAccessTokenExpiration)
 }
 
-func getHttpClient(trustedCerts string, sslInsecure bool) *http.Client {
-	// Configure CA certificates
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-
-	certs := []byte(trustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debug("[Token Manager] No certs appended, using only system certs")
-	}
-
-	// Create an insecure/secure client based on the SslInsecure flag
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: sslInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
-
-	return &http.Client{Transport: tr}
-}
-
 // RefreshAccessToken retrieves a new accessToken from the CM.
 func (tm *TokenManager) RefreshAccessToken() error {
 
@@ -183,11 +455,30 @@ func (tm *TokenManager) RefreshAccessToken() error {
 
 // SyncTokenWithoutRetry retrieves a new accessToken from the CM.
 func (tm *TokenManager) SyncTokenWithoutRetry() (err error, exit bool) {
+	switch tm.authConfig.Type {
+	case AuthClientCert:
+		// requests are authenticated with the client certificate, no token to fetch
+		return nil, false
+	case AuthAPIToken:
+		tm.mu.Lock()
+		token := tm.authConfig.APIToken
+		tm.mu.Unlock()
+		if token == "" {
+			return fmt.Errorf("api token is not configured"), true
+		}
+		tm.SetAccessToken(token)
+		return nil, false
+	case AuthTokenFile:
+		return tm.syncTokenFromFile()
+	}
 	var errMessage error
 	// Prepare the request payload
-	payload, err := json.Marshal(tm.credentials)
+	tm.mu.Lock()
+	credentials := tm.credentials
+	tm.mu.Unlock()
+	payload, err := json.Marshal(credentials)
 	if err != nil {
-		errMessage = fmt.Errorf("marshaling failed for credentials %v. error: %v", tm.credentials, err.Error())
+		errMessage = fmt.Errorf("marshaling failed for credentials of user %v. error: %v", credentials.Username, err.Error())
 		return errMessage, false
 	}
 
@@ -214,12 +505,9 @@ func (tm *TokenManager) SyncTokenWithoutRetry() (err error, exit bool) {
 				"Please check the credentials, status code: %d, response: %s", resp.StatusCode, body)
 			return errMessage, true
 		case http.StatusServiceUnavailable:
-			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.CMStatus{
-				Message: TokenFetchFailed,
-				Error: fmt.Sprintf("failed to get accessToken due to service unavailability, "+
-					"status code: %d, response: %s", resp.StatusCode, body),
-				LastUpdated: metav1.Now(),
-			})
+			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, tm.cmStatus(TokenFetchFailed,
+				fmt.Sprintf("failed to get accessToken due to service unavailability, "+
+					"status code: %d, response: %s", resp.StatusCode, body)))
 			errMessage = fmt.Errorf("failed to get accessToken due to service unavailability, "+
 				"status code: %d, response: %s", resp.StatusCode, body)
 			return errMessage, false
@@ -248,17 +536,147 @@ func (tm *TokenManager) SyncTokenWithoutRetry() (err error, exit bool) {
 	return nil, false
 }
 
+// syncTokenFromFile reads the accessToken from the token file.
+func (tm *TokenManager) syncTokenFromFile() (error, bool) {
+	info, err := os.Stat(tm.authConfig.TokenFile)
+	if err != nil {
+		return fmt.Errorf("unable to access token file %v: %v", tm.authConfig.TokenFile, err), false
+	}
+	fileBytes, err := os.ReadFile(tm.authConfig.TokenFile)
+	if err != nil {
+		return fmt.Errorf("unable to read token file %v: %v", tm.authConfig.TokenFile, err), false
+	}
+	token := strings.TrimSpace(string(fileBytes))
+	if token == "" {
+		return fmt.Errorf("token file %v is empty", tm.authConfig.TokenFile), false
+	}
+	tm.SetAccessToken(token)
+	tm.mu.Lock()
+	tm.tokenFileModTime = info.ModTime()
+	tm.mu.Unlock()
+	log.Debugf("[Token Manager] Successfully read accessToken from %v", tm.authConfig.TokenFile)
+	return nil, false
+}
+
+// tokenFileChanged checks whether the token file is modified since it was last read.
+func (tm *TokenManager) tokenFileChanged() bool {
+	info, err := os.Stat(tm.authConfig.TokenFile)
+	if err != nil {
+		log.Errorf("[Token Manager] Unable to access token file %v: %v", tm.authConfig.TokenFile, err)
+		return false
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return !info.ModTime().Equal(tm.tokenFileModTime)
+}
+
+// WatchCredentials watches the credentials directory and the client certificate and key files, and reloads the
+// credentials and client certificate on change.
+func (tm *TokenManager) WatchCredentials(stopCh <-chan struct{}) {
+	watchDir := tm.authConfig.Type == AuthBasic && tm.authConfig.CredentialsDir != ""
+	watchCert := tm.authConfig.Type == AuthClientCert && tm.authConfig.ClientCertFile != "" &&
+		tm.authConfig.ClientKeyFile != ""
+	if !watchDir && !watchCert {
+		return
+	}
+	if watchDir {
+		log.Debugf("[Token Manager] Watching credentials directory %v", tm.authConfig.CredentialsDir)
+	} else {
+		log.Debugf("[Token Manager] Watching client certificate %v and key %v", tm.authConfig.ClientCertFile,
+			tm.authConfig.ClientKeyFile)
+	}
+	certModTime := tm.clientCertModTime()
+	ticker := time.NewTicker(CredentialsSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if watchDir {
+				tm.syncCredentialsFromDirectory()
+			} else {
+				certModTime = tm.syncClientCertificate(certModTime)
+			}
+		case <-stopCh:
+			log.Debug("[Token Manager] Stopping watching credentials")
+			return
+		}
+	}
+}
+
+// syncClientCertificate reloads the client certificate when the certificate or key file is modified after the
+// given modification time, and returns the modification time of the loaded files.
+func (tm *TokenManager) syncClientCertificate(certModTime time.Time) time.Time {
+	modTime := tm.clientCertModTime()
+	if modTime.Equal(certModTime) {
+		return certModTime
+	}
+	if err := tm.ReloadClientCertificate(); err != nil {
+		log.Errorf("[Token Manager] Failed to reload client certificate: %v", err)
+		return certModTime
+	}
+	return modTime
+}
+
+// syncCredentialsFromDirectory reads the username and password from the credentials directory and updates them on change.
+func (tm *TokenManager) syncCredentialsFromDirectory() {
+	tm.mu.Lock()
+	credentials := tm.credentials
+	tm.mu.Unlock()
+	readField := func(field *string, filename string) {
+		if fileBytes, err := os.ReadFile(filepath.Join(tm.authConfig.CredentialsDir, filename)); err == nil {
+			*field = strings.TrimSpace(string(fileBytes))
+		}
+	}
+	readField(&credentials.Username, "username")
+	readField(&credentials.Password, "password")
+	tm.UpdateCredentials(credentials)
+}
+
+// clientCertModTime returns the latest modification time of the client certificate and key files.
+func (tm *TokenManager) clientCertModTime() time.Time {
+	var modTime time.Time
+	for _, file := range []string{tm.authConfig.ClientCertFile, tm.authConfig.ClientKeyFile} {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime
+}
+
 // Start maintains valid accessToken. It fetches a new accessToken before expiry.
 func (tm *TokenManager) Start(stopCh chan struct{}, duration time.Duration) {
+	switch tm.authConfig.Type {
+	case AuthClientCert, AuthAPIToken:
+		// static credentials, nothing to refresh
+		<-stopCh
+		log.Debug("[Token Manager] Stopping synchronizing refreshToken")
+		return
+	case AuthTokenFile:
+		// re-read the token whenever the token file changes
+		tokenFileTicker := time.NewTicker(TokenFileSyncInterval)
+		defer tokenFileTicker.Stop()
+		for {
+			select {
+			case <-tokenFileTicker.C:
+				if tm.tokenFileChanged() {
+					log.Infof("[Token Manager] Token file %v changed, reloading accessToken", tm.authConfig.TokenFile)
+					tm.SyncToken()
+				}
+			case <-stopCh:
+				log.Debug("[Token Manager] Stopping synchronizing token file")
+				return
+			}
+		}
+	}
 	// Set ticker to 1 minute less than refreshToken expiry time to ensure accessToken is refreshed on time
-	tokenUpdateTicker := time.Tick(duration - 60*time.Second)
+	tokenUpdateTicker := time.NewTicker(duration - 60*time.Second)
+	defer tokenUpdateTicker.Stop()
 	for {
 		select {
-		case <-tokenUpdateTicker:
+		case <-tokenUpdateTicker.C:
 			tm.SyncToken()
 		case <-stopCh:
 			log.Debug("[Token Manager] Stopping synchronizing refreshToken")
-			close(stopCh)
 			return
 		}
 	}
@@ -269,29 +687,77 @@ func (tm *TokenManager) SyncToken() {
 	for {
 		err, exit := tm.SyncTokenWithoutRetry()
 		if err != nil {
-			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", exit, &cisapiv1.CMStatus{
-				Message:     TokenFetchFailed,
-				Error:       fmt.Sprintf("%v", err.Error()),
-				LastUpdated: metav1.Now(),
-			})
+			// failures of the additional central managers are not fatal
+			exit = exit && tm.statusURL == ""
+			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", exit, tm.cmStatus(TokenFetchFailed, err.Error()))
 			if !exit {
 				log.Debugf("[Token Manager] Retrying to fetch refreshToken in %d seconds", RetryInterval)
 				time.Sleep(RetryInterval * time.Second)
 			}
 		} else {
 			// update the CM status
-			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, &cisapiv1.CMStatus{
-				Message:     Ok,
-				LastUpdated: metav1.Now(),
-			})
+			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, tm.cmStatus(Ok, ""))
 			break
 		}
 	}
 }
 
+// SyncTokenUntil fetches the accessToken retrying on failure until it succeeds or stopCh is closed.
+// It returns false if stopped before fetching the accessToken.
+func (tm *TokenManager) SyncTokenUntil(stopCh <-chan struct{}) bool {
+	for {
+		err, _ := tm.SyncTokenWithoutRetry()
+		if err == nil {
+			tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, tm.cmStatus(Ok, ""))
+			return true
+		}
+		tm.StatusManager.AddRequest(statusmanager.DeployConfig, "", "", false, tm.cmStatus(TokenFetchFailed, err.Error()))
+		log.Debugf("[Token Manager] Retrying to fetch accessToken from %v in %d seconds", tm.ServerURL, RetryInterval)
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(RetryInterval * time.Second):
+		}
+	}
+}
+
+// HasValidToken returns true if the requests to the Central Manager can be authenticated.
+func (tm *TokenManager) HasValidToken() bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	switch tm.authConfig.Type {
+	case AuthClientCert:
+		return tm.clientCert != nil
+	case AuthBasic:
+		// expired accessToken is refreshed using the refreshToken
+		return tm.accessToken != "" && (time.Now().Before(tm.accessTokenExpiry) || tm.refreshToken != "")
+	default:
+		return tm.accessToken != ""
+	}
+}
+
+// SetCMVersion sets the version of the Central Manager fetched with GetCMVersion.
+func (tm *TokenManager) SetCMVersion(version string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.CMVersion = version
+}
+
+// GetCachedCMVersion returns the version of the Central Manager set with SetCMVersion.
+func (tm *TokenManager) GetCachedCMVersion() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.CMVersion
+}
+
 func (tm *TokenManager) GetCMVersion() (string, error) {
+	return tm.GetCMVersionWithContext(context.Background())
+}
+
+// GetCMVersionWithContext fetches the version of the Central Manager, request is cancelled when the context is done.
+func (tm *TokenManager) GetCMVersionWithContext(ctx context.Context) (string, error) {
 	url := tm.ServerURL + CMVersionURL
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		log.Errorf("Creating new HTTP request error: %v ", err)
 		return "", err
@@ -299,7 +765,7 @@ func (tm *TokenManager) GetCMVersion() (string, error) {
 
 	log.Debugf("posting GET CM version request on %v", url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", "Bearer "+tm.GetAccessToken())
+	tm.SetAuthorizationHeader(req)
 
 	httpResp, err := tm.httpClient.Do(req)
 
@@ -318,7 +784,8 @@ func (tm *TokenManager) GetCMVersion() (string, error) {
 		return "", err
 	}
 
-	version := strings.Replace(response["version"].(string), "BIG-IP-Next-CentralManager-", "", -1)
+	cmVersion, _ := response["version"].(string)
+	version := strings.Replace(cmVersion, "BIG-IP-Next-CentralManager-", "", -1)
 
 	if len(strings.Split(version, "-")) > 1 {
 		return strings.Split(version, "-")[0], nil
@@ -1,10 +1,20 @@
 package tokenmanager
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/statusmanager/mockmanager"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -274,3 +284,254 @@ var _ = Describe("GetCMVersion", func() {
 		})
 	})
 })
+
+var _ = Describe("Authentication types", func() {
+	var (
+		server            *ghttp.Server
+		mockStatusManager *mockmanager.MockStatusManager
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		mockStatusManager = mockmanager.NewMockStatusManager()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should reject unsupported authentication type", func() {
+		_, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: "invalid"}, "", true, mockStatusManager)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should default to basic authentication", func() {
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{Username: "admin", Password: "admin"}, AuthConfig{}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tm.GetAuthType()).To(Equal(AuthBasic))
+	})
+
+	It("should use the api token without login", func() {
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthAPIToken, APIToken: "api.token"}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		tm.SyncToken()
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+		// token never expires
+		tm.accessTokenExpiry = time.Now()
+		Expect(tm.GetAccessToken()).To(Equal("api.token"))
+		req, _ := http.NewRequest("GET", server.URL(), nil)
+		tm.SetAuthorizationHeader(req)
+		Expect(req.Header.Get("Authorization")).To(Equal("Bearer api.token"))
+	})
+
+	It("should report the validity of the token", func() {
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{Username: "admin", Password: "admin"}, AuthConfig{}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tm.HasValidToken()).To(BeFalse())
+		tm.SetAccessToken("access.token")
+		Expect(tm.HasValidToken()).To(BeTrue())
+		// expired accessToken without refreshToken can't be refreshed
+		tm.accessTokenExpiry = time.Now()
+		Expect(tm.HasValidToken()).To(BeFalse())
+		tm.SetRefreshToken("refresh.token")
+		Expect(tm.HasValidToken()).To(BeTrue())
+	})
+
+	It("should fail api token authentication without a token", func() {
+		_, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthAPIToken}, "", true, mockStatusManager)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the token from file and reload on change", func() {
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("file.token\n"), 0600)).To(Succeed())
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthTokenFile, TokenFile: tokenFile}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		err, _ = tm.SyncTokenWithoutRetry()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tm.GetAccessToken()).To(Equal("file.token"))
+		Expect(tm.tokenFileChanged()).To(BeFalse())
+
+		Expect(os.WriteFile(tokenFile, []byte("rotated.token"), 0600)).To(Succeed())
+		Expect(os.Chtimes(tokenFile, time.Now(), time.Now().Add(time.Minute))).To(Succeed())
+		Expect(tm.tokenFileChanged()).To(BeTrue())
+		err, _ = tm.SyncTokenWithoutRetry()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tm.GetAccessToken()).To(Equal("rotated.token"))
+	})
+
+	It("should fail token file authentication with an empty file", func() {
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte(""), 0600)).To(Succeed())
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthTokenFile, TokenFile: tokenFile}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		err, _ = tm.SyncTokenWithoutRetry()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should present the client certificate and skip the authorization header", func() {
+		certFile, keyFile := writeClientCertificate(GinkgoT().TempDir())
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthClientCert,
+			ClientCertFile: certFile, ClientKeyFile: keyFile}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tm.TLSConfig().GetClientCertificate).NotTo(BeNil())
+		cert, err := tm.getClientCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Certificate).NotTo(BeEmpty())
+		err, _ = tm.SyncTokenWithoutRetry()
+		Expect(err).NotTo(HaveOccurred())
+		req, _ := http.NewRequest("GET", server.URL(), nil)
+		tm.SetAuthorizationHeader(req)
+		Expect(req.Header.Get("Authorization")).To(BeEmpty())
+	})
+
+	It("should fail client certificate authentication with invalid certificate", func() {
+		_, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthClientCert,
+			ClientCertFile: "/tmp/invalid.crt", ClientKeyFile: "/tmp/invalid.key"}, "", true, mockStatusManager)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Credentials reload", func() {
+	var (
+		server            *ghttp.Server
+		mockStatusManager *mockmanager.MockStatusManager
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		mockStatusManager = mockmanager.NewMockStatusManager()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should order trusted certificates from configmap data", func() {
+		certs := TrustedCertsFromConfigMapData(map[string]string{"b.crt": "cert-b", "a.crt": "cert-a"})
+		Expect(certs).To(Equal("cert-a\ncert-b\n"))
+		Expect(TrustedCertsFromConfigMapData(nil)).To(BeEmpty())
+	})
+
+	It("should reload the transport and re-login on trusted certificates update", func() {
+		tm := NewTokenManager(server.URL(), Credentials{Username: "admin", Password: "admin"}, "", true, mockStatusManager)
+		oldTransport := tm.transport.transport
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", CMLoginURL),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessTokenResponse{AccessToken: "new.accessToken"}),
+			))
+		tm.UpdateTrustedCerts("cert-a\n")
+		Expect(tm.TrustedCerts).To(Equal("cert-a\n"))
+		Expect(tm.transport.transport).NotTo(BeIdenticalTo(oldTransport))
+		Expect(tm.GetAccessToken()).To(Equal("new.accessToken"))
+
+		// no re-login when trusted certificates are unchanged
+		tm.UpdateTrustedCerts("cert-a\n")
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("should re-login when the credentials in the credentials directory change", func() {
+		credsDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(credsDir, "username"), []byte("admin"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credsDir, "password"), []byte("admin"), 0600)).To(Succeed())
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{Username: "admin", Password: "admin"},
+			AuthConfig{CredentialsDir: credsDir}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		tm.syncCredentialsFromDirectory()
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+
+		Expect(os.WriteFile(filepath.Join(credsDir, "password"), []byte("rotated\n"), 0600)).To(Succeed())
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", CMLoginURL),
+				ghttp.VerifyJSON(`{"username":"admin","password":"rotated"}`),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, AccessTokenResponse{AccessToken: "rotated.accessToken"}),
+			))
+		tm.syncCredentialsFromDirectory()
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(tm.GetAccessToken()).To(Equal("rotated.accessToken"))
+	})
+
+	It("should reload the client certificate", func() {
+		dir := GinkgoT().TempDir()
+		certFile, keyFile := writeClientCertificate(dir)
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthClientCert,
+			ClientCertFile: certFile, ClientKeyFile: keyFile, CredentialsDir: dir}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		oldCert, _ := tm.getClientCertificate(nil)
+		writeClientCertificate(dir)
+		Expect(tm.ReloadClientCertificate()).To(Succeed())
+		newCert, _ := tm.getClientCertificate(nil)
+		Expect(newCert.Certificate[0]).NotTo(Equal(oldCert.Certificate[0]))
+	})
+
+	It("should reload the client certificate on change without the credentials directory", func() {
+		dir := GinkgoT().TempDir()
+		certFile, keyFile := writeClientCertificate(dir)
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthClientCert,
+			ClientCertFile: certFile, ClientKeyFile: keyFile}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		oldCert, _ := tm.getClientCertificate(nil)
+		certModTime := tm.clientCertModTime()
+		Expect(tm.syncClientCertificate(certModTime)).To(Equal(certModTime))
+
+		writeClientCertificate(dir)
+		modTime := time.Now().Add(time.Second)
+		Expect(os.Chtimes(certFile, modTime, modTime)).To(Succeed())
+		Expect(tm.syncClientCertificate(certModTime)).To(BeTemporally("==", modTime))
+		newCert, _ := tm.getClientCertificate(nil)
+		Expect(newCert.Certificate[0]).NotTo(Equal(oldCert.Certificate[0]))
+	})
+
+	It("should update the api token and client certificate from the credentials secret", func() {
+		tm, err := NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthAPIToken,
+			APIToken: "api.token"}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		tm.SyncToken()
+		Expect(tm.UpdateAuth(Credentials{}, AuthConfig{Type: AuthAPIToken, APIToken: "rotated.token"})).To(Succeed())
+		Expect(tm.GetAccessToken()).To(Equal("rotated.token"))
+		Expect(tm.UpdateAuth(Credentials{Username: "admin", Password: "admin"}, AuthConfig{Type: AuthBasic})).To(
+			MatchError("authentication type can't be changed from api-token to basic"))
+
+		dir := GinkgoT().TempDir()
+		certFile, keyFile := writeClientCertificate(dir)
+		certData, _ := os.ReadFile(certFile)
+		keyData, _ := os.ReadFile(keyFile)
+		tm, err = NewTokenManagerWithAuth(server.URL(), Credentials{}, AuthConfig{Type: AuthClientCert,
+			ClientCertData: certData, ClientKeyData: keyData}, "", true, mockStatusManager)
+		Expect(err).NotTo(HaveOccurred())
+		oldCert, _ := tm.getClientCertificate(nil)
+		writeClientCertificate(dir)
+		certData, _ = os.ReadFile(certFile)
+		keyData, _ = os.ReadFile(keyFile)
+		Expect(tm.UpdateAuth(Credentials{}, AuthConfig{Type: AuthClientCert, ClientCertData: certData,
+			ClientKeyData: keyData})).To(Succeed())
+		newCert, _ := tm.getClientCertificate(nil)
+		Expect(newCert.Certificate[0]).NotTo(Equal(oldCert.Certificate[0]))
+		Expect(tm.UpdateAuth(Credentials{}, AuthConfig{Type: AuthClientCert, ClientCertData: []byte("cert"),
+			ClientKeyData: []byte("key")})).NotTo(Succeed())
+	})
+})
+
+// writeClientCertificate writes a self-signed client certificate and key to the given directory
+func writeClientCertificate(dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cis"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)).To(Succeed())
+	return certFile, keyFile
+}
@@ -0,0 +1,258 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The OTLP exporter posts the spans using the JSON encoding of the OTLP/HTTP protocol, it is kept
+// in tree to avoid pulling the grpc and protobuf dependencies of the upstream exporters
+
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		BoolValue   *bool           `json:"boolValue,omitempty"`
+		IntValue    *string         `json:"intValue,omitempty"`
+		DoubleValue *float64        `json:"doubleValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+)
+
+// encodeSpans converts the spans to the OTLP JSON representation, spans are grouped by the instrumentation scope
+func encodeSpans(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var traces otlpTraces
+	if len(spans) == 0 {
+		return json.Marshal(traces)
+	}
+	// all the spans are created by the same tracer provider and share its resource
+	rs := otlpResourceSpans{Resource: otlpResource{Attributes: encodeAttributes(spans[0].Resource().Attributes())}}
+	scopeIndex := make(map[string]int)
+	for _, span := range spans {
+		scope := span.InstrumentationScope()
+		idx, ok := scopeIndex[scope.Name+"/"+scope.Version]
+		if !ok {
+			idx = len(rs.ScopeSpans)
+			scopeIndex[scope.Name+"/"+scope.Version] = idx
+			rs.ScopeSpans = append(rs.ScopeSpans, otlpScopeSpans{Scope: otlpScope{Name: scope.Name, Version: scope.Version}})
+		}
+		rs.ScopeSpans[idx].Spans = append(rs.ScopeSpans[idx].Spans, encodeSpan(span))
+	}
+	traces.ResourceSpans = append(traces.ResourceSpans, rs)
+	return json.Marshal(traces)
+}
+
+func encodeSpan(span sdktrace.ReadOnlySpan) otlpSpan {
+	s := otlpSpan{
+		TraceID:           span.SpanContext().TraceID().String(),
+		SpanID:            span.SpanContext().SpanID().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime().UnixNano(), 10),
+		Attributes:        encodeAttributes(span.Attributes()),
+	}
+	if span.Parent().IsValid() {
+		s.ParentSpanID = span.Parent().SpanID().String()
+	}
+	for _, event := range span.Events() {
+		s.Events = append(s.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(event.Time.UnixNano(), 10),
+			Name:         event.Name,
+			Attributes:   encodeAttributes(event.Attributes),
+		})
+	}
+	// status codes of OTLP are ordered differently from the otel codes
+	switch span.Status().Code {
+	case codes.Ok:
+		s.Status.Code = 1
+	case codes.Error:
+		s.Status = otlpStatus{Code: 2, Message: span.Status().Description}
+	}
+	return s
+}
+
+func encodeAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	var kvs []otlpKeyValue
+	for _, attr := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: string(attr.Key), Value: encodeValue(attr.Value)})
+	}
+	return kvs
+}
+
+func encodeValue(v attribute.Value) otlpAnyValue {
+	var av otlpAnyValue
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		av.BoolValue = &b
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		av.IntValue = &i
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		av.DoubleValue = &f
+	case attribute.BOOLSLICE:
+		av.ArrayValue = &otlpArrayValue{}
+		for _, b := range v.AsBoolSlice() {
+			av.ArrayValue.Values = append(av.ArrayValue.Values, encodeValue(attribute.BoolValue(b)))
+		}
+	case attribute.INT64SLICE:
+		av.ArrayValue = &otlpArrayValue{}
+		for _, i := range v.AsInt64Slice() {
+			av.ArrayValue.Values = append(av.ArrayValue.Values, encodeValue(attribute.Int64Value(i)))
+		}
+	case attribute.FLOAT64SLICE:
+		av.ArrayValue = &otlpArrayValue{}
+		for _, f := range v.AsFloat64Slice() {
+			av.ArrayValue.Values = append(av.ArrayValue.Values, encodeValue(attribute.Float64Value(f)))
+		}
+	case attribute.STRINGSLICE:
+		av.ArrayValue = &otlpArrayValue{}
+		for _, s := range v.AsStringSlice() {
+			av.ArrayValue.Values = append(av.ArrayValue.Values, encodeValue(attribute.StringValue(s)))
+		}
+	default:
+		s := v.Emit()
+		av.StringValue = &s
+	}
+	return av
+}
+
+// OTLPExporter exports the spans to an OTLP/HTTP collector
+type OTLPExporter struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewOTLPExporter returns the exporter posting the spans to the traces endpoint of the collector
+func NewOTLPExporter(endpoint string, httpClient *http.Client) *OTLPExporter {
+	return &OTLPExporter{endpoint: endpoint, httpClient: httpClient}
+}
+
+// ExportSpans posts the spans to the collector
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	body, err := encodeSpans(spans)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to export spans: collector responded with status %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// Shutdown releases the idle connections of the exporter
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// FileExporter writes the spans as OTLP JSON lines, one export request per line
+type FileExporter struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// NewFileExporter returns the exporter writing the spans to w, w is closed on shutdown if it's a closer
+func NewFileExporter(w io.Writer) *FileExporter {
+	return &FileExporter{w: w}
+}
+
+// ExportSpans writes the spans to the file
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	body, err := encodeSpans(spans)
+	if err != nil {
+		return err
+	}
+	e.Lock()
+	defer e.Unlock()
+	_, err = e.w.Write(append(body, '\n'))
+	return err
+}
+
+// Shutdown closes the file
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.Lock()
+	defer e.Unlock()
+	if c, ok := e.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
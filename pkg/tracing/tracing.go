@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP exports the spans to an OTLP/HTTP collector
+	ExporterOTLP = "otlp"
+	// ExporterFile writes the spans to a file in OTLP JSON lines format
+	ExporterFile = "file"
+
+	// DefaultOTLPEndpoint is the default traces endpoint of the OTLP/HTTP collector
+	DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+
+	tracerName  = "github.com/F5Networks/k8s-bigip-ctlr/v3"
+	serviceName = "k8s-bigip-ctlr"
+)
+
+// Span attribute keys used across the reconcile and post pipeline
+const (
+	ResourceKind      = attribute.Key("cis.resource.kind")
+	ResourceNamespace = attribute.Key("cis.resource.namespace")
+	ResourceName      = attribute.Key("cis.resource.name")
+	ResourceEvent     = attribute.Key("cis.resource.event")
+	Retry             = attribute.Key("cis.resource.retry")
+	RequestID         = attribute.Key("cis.request.id")
+	BigIPAddress      = attribute.Key("cis.bigip.address")
+	BigIPLabel        = attribute.Key("cis.bigip.label")
+	Tenants           = attribute.Key("cis.tenants")
+	FailedTenants     = attribute.Key("cis.tenants.failed")
+	AS3TaskID         = attribute.Key("cis.as3.task_id")
+	HTTPURL           = attribute.Key("url.full")
+	HTTPStatusCode    = attribute.Key("http.response.status_code")
+)
+
+// Config holds the tracing configuration of the controller
+type Config struct {
+	// Exporter is the span exporter, tracing is disabled when empty
+	Exporter string
+	// Endpoint is the traces URL of the OTLP/HTTP collector
+	Endpoint string
+	// FilePath is the file the spans are written to with the file exporter
+	FilePath string
+	// SampleRatio is the ratio of the traces to sample, between 0 and 1
+	SampleRatio float64
+	// ServiceVersion is reported as service.version resource attribute
+	ServiceVersion string
+}
+
+// Validate verifies the exporter and its parameters
+func (cfg Config) Validate() error {
+	switch cfg.Exporter {
+	case "", ExporterOTLP:
+	case ExporterFile:
+		if cfg.FilePath == "" {
+			return fmt.Errorf("missing file path for %s tracing exporter", ExporterFile)
+		}
+	default:
+		return fmt.Errorf("'%v' is not a valid tracing exporter, allowed values are: %s/%s",
+			cfg.Exporter, ExporterOTLP, ExporterFile)
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio should be between 0 and 1, got %v", cfg.SampleRatio)
+	}
+	return nil
+}
+
+// Init sets up the global tracer provider as per the config and returns the function to flush and
+// stop it. When no exporter is configured tracing stays disabled and the spans are no-op.
+func Init(cfg Config) (func(context.Context) error, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		exporter = NewOTLPExporter(endpoint, &http.Client{Timeout: 10 * time.Second})
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tracing file: %v", err)
+		}
+		exporter = NewFileExporter(f)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(newResource(cfg.ServiceVersion)),
+	)
+	setTracerProvider(tp)
+	log.Infof("[TRACING] Tracing enabled with %s exporter", cfg.Exporter)
+	return tp.Shutdown, nil
+}
+
+// InitWithExporter sets up the global tracer provider to export every span synchronously with the
+// given exporter, it's meant for in-process exporters such as the in-memory exporter of the tests
+func InitWithExporter(exporter sdktrace.SpanExporter) func(context.Context) error {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithResource(newResource("")),
+	)
+	setTracerProvider(tp)
+	return tp.Shutdown
+}
+
+func setTracerProvider(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+func newResource(version string) *resource.Resource {
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if version != "" {
+		attrs = append(attrs, attribute.String("service.version", version))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// Start starts a span as a child of the span in the context
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartFrom starts a span as a child of the given span context, it's used to continue the trace of a
+// request handed over to another goroutine
+func StartFrom(parent trace.SpanContext, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Start(trace.ContextWithSpanContext(context.Background(), parent), name, attrs...)
+}
+
+// SetError records the error on the span and marks the span as failed
+func SetError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// InjectHeaders propagates the span in the context to the outgoing request headers
+func InjectHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
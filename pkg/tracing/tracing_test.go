@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracing Tests", func() {
+	It("validates the tracing config", func() {
+		Expect(Config{}.Validate()).To(BeNil())
+		Expect(Config{Exporter: ExporterOTLP, SampleRatio: 1}.Validate()).To(BeNil())
+		Expect(Config{Exporter: ExporterFile, SampleRatio: 1}.Validate()).ToNot(BeNil(), "file path is required")
+		Expect(Config{Exporter: "jaeger", SampleRatio: 1}.Validate()).ToNot(BeNil())
+		Expect(Config{Exporter: ExporterOTLP, SampleRatio: 2}.Validate()).ToNot(BeNil())
+	})
+
+	It("keeps tracing disabled without exporter", func() {
+		shutdown, err := Init(Config{})
+		Expect(err).To(BeNil())
+		Expect(shutdown(context.Background())).To(BeNil())
+	})
+
+	It("continues the trace from the span context", func() {
+		exporter := tracetest.NewInMemoryExporter()
+		shutdown := InitWithExporter(exporter)
+		defer shutdown(context.Background())
+
+		_, parent := Start(context.Background(), "reconcile", ResourceKind.String("VirtualServer"))
+		ctx, child := StartFrom(parent.SpanContext(), "as3.post", RequestID.Int(1))
+		header := http.Header{}
+		InjectHeaders(ctx, header)
+		child.End()
+		parent.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("as3.post"))
+		Expect(spans[0].SpanContext.TraceID()).To(Equal(spans[1].SpanContext.TraceID()))
+		Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+		Expect(header.Get("traceparent")).To(ContainSubstring(spans[0].SpanContext.SpanID().String()))
+	})
+
+	It("writes the spans to the file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "traces.json")
+		shutdown, err := Init(Config{Exporter: ExporterFile, FilePath: path, SampleRatio: 1, ServiceVersion: "3.0.0"})
+		Expect(err).To(BeNil())
+
+		_, span := Start(context.Background(), "as3.post", Tenants.StringSlice([]string{"test"}), RequestID.Int(2))
+		SetError(span, os.ErrDeadlineExceeded)
+		span.End()
+		Expect(shutdown(context.Background())).To(BeNil())
+
+		f, err := os.Open(path)
+		Expect(err).To(BeNil())
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		Expect(scanner.Scan()).To(BeTrue())
+		var traces otlpTraces
+		Expect(json.Unmarshal(scanner.Bytes(), &traces)).To(BeNil())
+		Expect(traces.ResourceSpans).To(HaveLen(1))
+		Expect(traces.ResourceSpans[0].Resource.Attributes).To(ContainElement(HaveField("Key", "service.version")))
+		spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("as3.post"))
+		Expect(spans[0].Status.Code).To(Equal(2))
+		Expect(spans[0].Events).To(HaveLen(1))
+		Expect(*spans[0].Attributes[0].Value.ArrayValue.Values[0].StringValue).To(Equal("test"))
+		Expect(*spans[0].Attributes[1].Value.IntValue).To(Equal("2"))
+	})
+
+	It("posts the spans to the OTLP collector", func() {
+		server := ghttp.NewServer()
+		defer server.Close()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/traces"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+			ghttp.RespondWith(http.StatusInternalServerError, ""),
+		)
+		exporter := tracetest.NewInMemoryExporter()
+		shutdown := InitWithExporter(exporter)
+		defer shutdown(context.Background())
+		_, span := Start(context.Background(), "reconcile")
+		span.End()
+
+		otlpExporter := NewOTLPExporter(server.URL()+"/v1/traces", &http.Client{})
+		Expect(otlpExporter.ExportSpans(context.Background(), exporter.GetSpans().Snapshots())).To(BeNil())
+		Expect(otlpExporter.ExportSpans(context.Background(), exporter.GetSpans().Snapshots())).ToNot(BeNil())
+		Expect(otlpExporter.Shutdown(context.Background())).To(BeNil())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+})
@@ -38,6 +38,20 @@ following package-level functions:
 	func Panic(msg string)
 	func Panicf(format string, params ... interface{})
 
+To add contextual fields such as the request id or the resource to the
+messages, create an entry with the fields and log through it:
+
+	log.WithFields(log.Fields{log.FieldRequestID: 1}).Infof("posting %v", tenant)
+
+The fields are emitted by the loggers implementing FieldLogger (for
+instance, the JSON logger), other loggers log the message only.
+
+Messages of a component are logged through the entry of the component,
+its level can be changed independently of the package-level filtering:
+
+	postManagerLog := log.Component("postmanager")
+	log.SetComponentLogLevel("postmanager", log.LL_DEBUG)
+
 # APPLICATION USAGE
 
 Logging in the main application is similar to logging in a library.  However,
@@ -51,6 +65,7 @@ vlogger (otherwise logging will default to the console).
 The following types of loggers are currently provided as subpackages:
 
 	func NewConsoleLogger() Logger
+	func NewJSONLogger(w io.Writer) Logger
 	func NewSyslogLogger(facility syslog.Priority, progname string) Logger
 	func NewSeelogLogger(filename string) Logger
 	func NewLogrusLogger() Logger
@@ -0,0 +1,163 @@
+// +gocover:ignore:file logging package
+// Copyright (c) 2019-2021, F5 Networks, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// fields.go:
+//
+//	Provides logging with contextual fields through the common interface.
+//	Fields are emitted by the loggers implementing FieldLogger, other
+//	loggers log the message only. To use, create the entry with:
+//	  WithFields(Fields{FieldRequestID: 1}).Infof("posting request")
+package vlogger
+
+import "fmt"
+
+// Keys of the contextual fields
+const (
+	FieldRequestID = "requestId"
+	FieldTenant    = "tenant"
+	FieldBigIP     = "bigip"
+	FieldKind      = "kind"
+	FieldNamespace = "namespace"
+	FieldName      = "name"
+	FieldCluster   = "cluster"
+	FieldComponent = "component"
+)
+
+type (
+	// Fields are the contextual key value pairs of a log message
+	Fields map[string]interface{}
+
+	// FieldLogger is implemented by the loggers that emit the contextual fields
+	FieldLogger interface {
+		Logger
+		LogFields(level LogLevel, fields Fields, msg string)
+	}
+
+	// Entry logs the messages with its contextual fields
+	Entry struct {
+		fields Fields
+		// component filters the messages with the level of the component
+		component string
+	}
+)
+
+// Component returns an entry for the messages of the component, its level can be
+// overridden with SetComponentLogLevel
+func Component(component string) *Entry {
+	return &Entry{fields: Fields{FieldComponent: component}, component: component}
+}
+
+// WithFields returns an entry with the fields, empty values are dropped
+func WithFields(fields Fields) *Entry {
+	return (&Entry{}).WithFields(fields)
+}
+
+// WithFields returns a new entry with the fields added to the fields of the entry
+func (e *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		if v == nil || v == "" {
+			continue
+		}
+		merged[k] = v
+	}
+	return &Entry{fields: merged, component: e.component}
+}
+
+// Fields returns the contextual fields of the entry
+func (e *Entry) Fields() Fields {
+	return e.fields
+}
+
+func (e *Entry) enabled(level LogLevel) bool {
+	if e.component != "" {
+		return level >= getComponentLogLevel(e.component)
+	}
+	return level >= GetLogLevel()
+}
+
+func (e *Entry) log(level LogLevel, msg string) {
+	if !e.enabled(level) {
+		return
+	}
+	logger := vlog[level]
+	if fl, ok := logger.(FieldLogger); ok {
+		fl.LogFields(level, e.fields, msg)
+		return
+	}
+	switch level {
+	case LL_DEBUG:
+		logger.Debug(msg)
+	case LL_INFO:
+		logger.Info(msg)
+	case LL_WARNING:
+		logger.Warning(msg)
+	case LL_ERROR:
+		logger.Error(msg)
+	case LL_CRITICAL:
+		logger.Critical(msg)
+	}
+}
+
+func (e *Entry) logf(level LogLevel, format string, params ...interface{}) {
+	// avoid formatting the messages that are filtered out
+	if !e.enabled(level) {
+		return
+	}
+	e.log(level, fmt.Sprintf(format, params...))
+}
+
+func (e *Entry) Debug(msg string) {
+	e.log(LL_DEBUG, msg)
+}
+
+func (e *Entry) Debugf(format string, params ...interface{}) {
+	e.logf(LL_DEBUG, format, params...)
+}
+
+func (e *Entry) Info(msg string) {
+	e.log(LL_INFO, msg)
+}
+
+func (e *Entry) Infof(format string, params ...interface{}) {
+	e.logf(LL_INFO, format, params...)
+}
+
+func (e *Entry) Warning(msg string) {
+	e.log(LL_WARNING, msg)
+}
+
+func (e *Entry) Warningf(format string, params ...interface{}) {
+	e.logf(LL_WARNING, format, params...)
+}
+
+func (e *Entry) Error(msg string) {
+	e.log(LL_ERROR, msg)
+}
+
+func (e *Entry) Errorf(format string, params ...interface{}) {
+	e.logf(LL_ERROR, format, params...)
+}
+
+func (e *Entry) Critical(msg string) {
+	e.log(LL_CRITICAL, msg)
+}
+
+func (e *Entry) Criticalf(format string, params ...interface{}) {
+	e.logf(LL_CRITICAL, format, params...)
+}
@@ -23,6 +23,8 @@ import (
 	"log/syslog" // For LOG level definitions
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // LogLevel is used for global (package-level) filtering of log messages based on their priority
@@ -128,8 +130,15 @@ var (
 	vlog [LL_LOGLEVEL_SIZE]Logger
 
 	// logLevel indicates the current package-level filtering being applied
-	// (may be further restricted by specific concrete loggers).
-	logLevel LogLevel = LL_DEBUG
+	// (may be further restricted by specific concrete loggers), zero value is LL_DEBUG.
+	logLevel atomic.Int32
+
+	// componentLevels overrides the package-level filtering for the messages of the
+	// components, concrete loggers are set to the lowest of the levels.
+	componentLevels = struct {
+		sync.RWMutex
+		levels map[string]LogLevel
+	}{levels: make(map[string]LogLevel)}
 
 	// logLevelToSyslogLevel maps vlogger log levels to the internal representation used
 	// by the implementations (which use syslog's definitions).
@@ -151,12 +160,18 @@ func RegisterLogger(minLogLevel, maxLogLevel LogLevel, log Logger) {
 
 // Debug sends a message to the logger object to record debug/trace level statements
 func Debug(msg string) {
+	if LL_DEBUG < GetLogLevel() {
+		return
+	}
 	vlog[LL_DEBUG].Debug(msg)
 }
 
 // Debugf formats a message before sending it to the logger object to record
 // debug/trace level statements
 func Debugf(format string, params ...interface{}) {
+	if LL_DEBUG < GetLogLevel() {
+		return
+	}
 	vlog[LL_DEBUG].Debugf(format, params...)
 }
 
@@ -164,6 +179,9 @@ func Debugf(format string, params ...interface{}) {
 // (these should be statements that can normally be logged without causing performance
 // issues).
 func Info(msg string) {
+	if LL_INFO < GetLogLevel() {
+		return
+	}
 	vlog[LL_INFO].Info(msg)
 }
 
@@ -171,6 +189,9 @@ func Info(msg string) {
 // informational level statements (there should be statements that can normally
 // be logged without causing performance issues).
 func Infof(format string, params ...interface{}) {
+	if LL_INFO < GetLogLevel() {
+		return
+	}
 	vlog[LL_INFO].Infof(format, params...)
 }
 
@@ -178,6 +199,9 @@ func Infof(format string, params ...interface{}) {
 // (these indication conditions that are unexpected or may cause issues but are not
 // normally going to affect the program execution).
 func Warning(msg string) {
+	if LL_WARNING < GetLogLevel() {
+		return
+	}
 	vlog[LL_WARNING].Warning(msg)
 }
 
@@ -185,6 +209,9 @@ func Warning(msg string) {
 // warning level statements (these indication conditions that are unexpected or
 // may cause issues but are not normally going to affect the program execution).
 func Warningf(format string, params ...interface{}) {
+	if LL_WARNING < GetLogLevel() {
+		return
+	}
 	vlog[LL_WARNING].Warningf(format, params...)
 }
 
@@ -192,6 +219,9 @@ func Warningf(format string, params ...interface{}) {
 // (these indicate conditions that should not occur and may indicate a failure
 // in performing the requested action).
 func Error(msg string) {
+	if LL_ERROR < GetLogLevel() {
+		return
+	}
 	vlog[LL_ERROR].Error(msg)
 }
 
@@ -199,6 +229,9 @@ func Error(msg string) {
 // error level statements (these indicate conditions that should not occur
 // and may indicate a failure in performing the requested action).
 func Errorf(format string, params ...interface{}) {
+	if LL_ERROR < GetLogLevel() {
+		return
+	}
 	vlog[LL_ERROR].Errorf(format, params...)
 }
 
@@ -206,6 +239,9 @@ func Errorf(format string, params ...interface{}) {
 // (these indicate conditions that should never occur and might cause a failure/crash
 // of the executing program or unexpected outcome from the requested action).
 func Critical(msg string) {
+	if LL_CRITICAL < GetLogLevel() {
+		return
+	}
 	vlog[LL_CRITICAL].Critical(msg)
 }
 
@@ -214,6 +250,9 @@ func Critical(msg string) {
 // and might cause a failure/crash of the executing program or unexpected
 // outcome from the requested action).
 func Criticalf(format string, params ...interface{}) {
+	if LL_CRITICAL < GetLogLevel() {
+		return
+	}
 	vlog[LL_CRITICAL].Criticalf(format, params...)
 }
 
@@ -254,10 +293,64 @@ func Panicf(format string, params ...interface{}) {
 
 // SetLogLevel sets the current package-level filtering
 func SetLogLevel(level LogLevel) {
-	logLevel = level
+	logLevel.Store(int32(level))
+	setLoggersLogLevel()
+}
+
+// GetLogLevel returns the current package-level filtering
+func GetLogLevel() LogLevel {
+	return LogLevel(logLevel.Load())
+}
 
-	// Update all loggers to the new level
-	slLogLevel := logLevelToSyslogLevel[logLevel]
+// SetComponentLogLevel overrides the package-level filtering for the messages of the component
+func SetComponentLogLevel(component string, level LogLevel) {
+	componentLevels.Lock()
+	componentLevels.levels[component] = level
+	componentLevels.Unlock()
+	setLoggersLogLevel()
+}
+
+// ClearComponentLogLevels removes the overrides of all the components
+func ClearComponentLogLevels() {
+	componentLevels.Lock()
+	componentLevels.levels = make(map[string]LogLevel)
+	componentLevels.Unlock()
+	setLoggersLogLevel()
+}
+
+// GetComponentLogLevels returns the overridden levels of the components
+func GetComponentLogLevels() map[string]LogLevel {
+	componentLevels.RLock()
+	defer componentLevels.RUnlock()
+	levels := make(map[string]LogLevel, len(componentLevels.levels))
+	for component, level := range componentLevels.levels {
+		levels[component] = level
+	}
+	return levels
+}
+
+// getComponentLogLevel returns the filtering of the component, package-level when not overridden
+func getComponentLogLevel(component string) LogLevel {
+	componentLevels.RLock()
+	defer componentLevels.RUnlock()
+	if level, ok := componentLevels.levels[component]; ok {
+		return level
+	}
+	return GetLogLevel()
+}
+
+// setLoggersLogLevel updates all loggers to the lowest of the package and component levels,
+// the messages are filtered by their level before reaching the loggers
+func setLoggersLogLevel() {
+	level := GetLogLevel()
+	componentLevels.RLock()
+	for _, componentLevel := range componentLevels.levels {
+		if componentLevel < level {
+			level = componentLevel
+		}
+	}
+	componentLevels.RUnlock()
+	slLogLevel := logLevelToSyslogLevel[level]
 	for i, _ := range vlog {
 		if vlog[i] != nil {
 			vlog[i].SetLogLevel(slLogLevel)
@@ -265,11 +358,6 @@ func SetLogLevel(level LogLevel) {
 	}
 }
 
-// GetLogLevel returns the current package-level filtering
-func GetLogLevel() LogLevel {
-	return logLevel
-}
-
 // Close informs the configured loggers that they are being closed and
 // should cleanup (for instance, flushing any queued log messages)
 func Close() {
@@ -0,0 +1,179 @@
+// +gocover:ignore:file logging package
+// Copyright (c) 2019-2021, F5 Networks, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// log_json.go:
+//
+//	Provides structured logging, each message is written as a JSON object
+//	on a single line along with the contextual fields.
+//	To use, create the logger object with the following syntax:
+//	  NewJSONLogger(os.Stdout) or NewJSONFileLogger(filename)
+package vlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// keys of the JSON object reserved for the message
+const (
+	jsonTimeKey  = "time"
+	jsonLevelKey = "level"
+	jsonMsgKey   = "msg"
+)
+
+type (
+	JSONLogger struct {
+		// slLogLevel uses syslog's definitions which have higher priority
+		// levels defined in descending order (0 is highest)
+		slLogLevel syslog.Priority
+		mutex      sync.Mutex
+		w          io.Writer
+	}
+)
+
+// NewJSONLogger creates a logger writing the messages to w
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{
+		slLogLevel: syslog.LOG_DEBUG,
+		w:          w,
+	}
+}
+
+// NewJSONFileLogger creates a logger writing the messages to the file, stdout & stderr are redirected
+// to the file as done by the FileLogger
+func NewJSONFileLogger(fn string) *JSONLogger {
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		panic(err)
+	}
+	os.Stdout = f
+	os.Stderr = f
+	return NewJSONLogger(f)
+}
+
+// LogFields writes the message along with the fields
+func (jl *JSONLogger) LogFields(level LogLevel, fields Fields, msg string) {
+	if jl.slLogLevel < logLevelToSyslogLevel[level] {
+		return
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONField(&buf, jsonTimeKey, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeJSONField(&buf, jsonLevelKey, level.String())
+	buf.WriteByte(',')
+	writeJSONField(&buf, jsonMsgKey, msg)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buf.WriteByte(',')
+		switch key {
+		case jsonTimeKey, jsonLevelKey, jsonMsgKey:
+			// fields don't overwrite the message keys
+			writeJSONField(&buf, "fields."+key, fields[key])
+		default:
+			writeJSONField(&buf, key, fields[key])
+		}
+	}
+	buf.WriteString("}\n")
+	jl.mutex.Lock()
+	defer jl.mutex.Unlock()
+	_, _ = jl.w.Write(buf.Bytes())
+}
+
+func (jl *JSONLogger) logf(level LogLevel, format string, params ...interface{}) {
+	if jl.slLogLevel >= logLevelToSyslogLevel[level] {
+		jl.LogFields(level, nil, fmt.Sprintf(format, params...))
+	}
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buf.Write(v)
+}
+
+func (jl *JSONLogger) Debug(msg string) {
+	jl.LogFields(LL_DEBUG, nil, msg)
+}
+
+func (jl *JSONLogger) Debugf(format string, params ...interface{}) {
+	jl.logf(LL_DEBUG, format, params...)
+}
+
+func (jl *JSONLogger) Info(msg string) {
+	jl.LogFields(LL_INFO, nil, msg)
+}
+
+func (jl *JSONLogger) Infof(format string, params ...interface{}) {
+	jl.logf(LL_INFO, format, params...)
+}
+
+func (jl *JSONLogger) Warning(msg string) {
+	jl.LogFields(LL_WARNING, nil, msg)
+}
+
+func (jl *JSONLogger) Warningf(format string, params ...interface{}) {
+	jl.logf(LL_WARNING, format, params...)
+}
+
+func (jl *JSONLogger) Error(msg string) {
+	jl.LogFields(LL_ERROR, nil, msg)
+}
+
+func (jl *JSONLogger) Errorf(format string, params ...interface{}) {
+	jl.logf(LL_ERROR, format, params...)
+}
+
+func (jl *JSONLogger) Critical(msg string) {
+	jl.LogFields(LL_CRITICAL, nil, msg)
+}
+
+func (jl *JSONLogger) Criticalf(format string, params ...interface{}) {
+	jl.logf(LL_CRITICAL, format, params...)
+}
+
+func (jl *JSONLogger) SetLogLevel(slLogLevel syslog.Priority) {
+	jl.slLogLevel = slLogLevel
+}
+
+func (jl *JSONLogger) GetLogLevel() syslog.Priority {
+	return jl.slLogLevel
+}
+
+// Close closes the writer if it's a file other than stdout & stderr
+func (jl *JSONLogger) Close() {
+	if f, ok := jl.w.(*os.File); ok && f.Fd() > 2 {
+		f.Close()
+	}
+}